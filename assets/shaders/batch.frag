@@ -2,11 +2,12 @@
 
 in vec2 TexCoord;
 in vec4 Color;
+flat in int TexIndex;
 out vec4 FragColor;
 
-uniform sampler2D texture1;
+#include "include/sprite.glsl"
 
 void main()
 {
-    FragColor = texture(texture1, TexCoord) * Color;
+    FragColor = spriteOutput(sampleTexture(TexIndex, TexCoord) * Color);
 }
\ No newline at end of file
@@ -3,15 +3,21 @@
 layout(location = 0) in vec3 aPos;
 layout(location = 1) in vec2 aTexCoord;
 layout(location = 2) in vec4 aColor;
+layout(location = 3) in float aTexIndex;
 
 out vec2 TexCoord;
 out vec4 Color;
+flat out int TexIndex;
 
-uniform mat4 view;
-uniform mat4 projection;
+// Shared by every material shader; see CameraUniforms in pkg/graphics/sprite/batch.go
+layout(std140) uniform Camera {
+    mat4 projection;
+    mat4 view;
+};
 
 void main(){
     gl_Position = projection * view * vec4(aPos, 1.0);
     TexCoord = aTexCoord;
     Color = aColor;
+    TexIndex = int(aTexIndex + 0.5);
 }
\ No newline at end of file
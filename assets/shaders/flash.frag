@@ -0,0 +1,18 @@
+#version 410 core
+
+in vec2 TexCoord;
+in vec4 Color;
+flat in int TexIndex;
+out vec4 FragColor;
+
+#include "include/sprite.glsl"
+
+uniform vec3 flashColor;
+uniform float amount;
+
+void main()
+{
+    // Tints towards flashColor without changing the sprite's shape
+    vec4 color = sampleTexture(TexIndex, TexCoord) * Color;
+    FragColor = spriteOutput(vec4(mix(color.rgb, flashColor, amount), color.a));
+}
\ No newline at end of file
@@ -0,0 +1,31 @@
+// Sprite batch texture slots, shared by batch.frag and material shaders
+
+// Must match MaxTextureSlots in pkg/graphics/sprite/batch.go
+uniform sampler2D textures[8];
+
+// Set by the material for blend modes that expect premultiplied alpha
+uniform bool premultiplyAlpha;
+
+vec4 sampleTexture(int index, vec2 uv)
+{
+    // GLSL 4.10 only allows constant sampler array indices
+    switch (index) {
+        case 0: return texture(textures[0], uv);
+        case 1: return texture(textures[1], uv);
+        case 2: return texture(textures[2], uv);
+        case 3: return texture(textures[3], uv);
+        case 4: return texture(textures[4], uv);
+        case 5: return texture(textures[5], uv);
+        case 6: return texture(textures[6], uv);
+        default: return texture(textures[7], uv);
+    }
+}
+
+// spriteOutput finishes a fragment's color for the material's blend mode
+vec4 spriteOutput(vec4 color)
+{
+    if (premultiplyAlpha) {
+        color.rgb *= color.a;
+    }
+    return color;
+}
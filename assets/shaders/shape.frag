@@ -0,0 +1,11 @@
+#version 410 core
+
+// Edge antialiasing comes from vertex alpha fading to zero across a thin
+// fringe around each shape
+in vec4 Color;
+
+out vec4 FragColor;
+
+void main(){
+    FragColor = Color;
+}
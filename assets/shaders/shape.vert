@@ -0,0 +1,17 @@
+#version 410 core
+
+layout(location = 0) in vec2 aPos;
+layout(location = 1) in vec4 aColor;
+
+out vec4 Color;
+
+// Same block as batch.vert; see CameraUniforms in pkg/graphics/sprite/batch.go
+layout(std140) uniform Camera {
+    mat4 projection;
+    mat4 view;
+};
+
+void main(){
+    gl_Position = projection * view * vec4(aPos, 0.0, 1.0);
+    Color = aColor;
+}
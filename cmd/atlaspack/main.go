@@ -0,0 +1,52 @@
+// Command atlaspack packs a directory of images into atlas pages and writes
+// them with a JSON manifest that texture.LoadAtlas reads at runtime.
+//
+//	go run ./cmd/atlaspack -in assets/textures -out assets/atlases -name sprites
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lunararch/helios/pkg/graphics/texture"
+)
+
+func main() {
+	config := texture.DefaultPackerConfig()
+
+	input := flag.String("in", "", "directory of PNG/JPEG images to pack")
+	output := flag.String("out", ".", "directory to write the pages and manifest to")
+	name := flag.String("name", "atlas", "base name of the page images and manifest")
+	flag.IntVar(&config.MaxWidth, "width", config.MaxWidth, "maximum page width")
+	flag.IntVar(&config.MaxHeight, "height", config.MaxHeight, "maximum page height")
+	flag.IntVar(&config.Padding, "padding", config.Padding, "transparent pixels between images")
+	flag.IntVar(&config.Extrude, "extrude", config.Extrude, "edge pixels repeated around each image")
+	flag.BoolVar(&config.PowerOfTwo, "pot", config.PowerOfTwo, "round page sizes up to powers of two")
+	flag.BoolVar(&config.MultiPage, "multipage", config.MultiPage, "allow more than one page")
+	flag.Parse()
+
+	if *input == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	packer := texture.NewAtlasPacker(config)
+	if err := packer.AddDirectory(*input); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	packed, err := packer.Pack()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := packed.Save(*output, *name); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("packed %d images into %d page(s) in %s\n", len(packed.Regions), len(packed.Pages), *output)
+}
@@ -2,9 +2,8 @@ module github.com/lunararch/helios
 
 go 1.24.3
 
-require github.com/go-gl/glfw/v3.3/glfw v0.0.0-20250301202403-da16c1255728
-
 require (
-	github.com/go-gl/gl v0.0.0-20231021071112-07e5d0ea2e71 // indirect
-	github.com/go-gl/mathgl v1.2.0 // indirect
+	github.com/go-gl/gl v0.0.0-20231021071112-07e5d0ea2e71
+	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20250301202403-da16c1255728
+	github.com/go-gl/mathgl v1.2.0
 )
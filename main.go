@@ -1,15 +1,29 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
 	"runtime"
+	"strconv"
+	"time"
 
 	"github.com/go-gl/gl/all-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/console"
+	"github.com/lunararch/helios/pkg/debug"
 	"github.com/lunararch/helios/pkg/engine"
 	"github.com/lunararch/helios/pkg/graphics/camera"
+	"github.com/lunararch/helios/pkg/graphics/shader"
+	"github.com/lunararch/helios/pkg/graphics/sprite"
 	"github.com/lunararch/helios/pkg/input"
+	"github.com/lunararch/helios/pkg/inspector"
+	"github.com/lunararch/helios/pkg/profiler"
+	"github.com/lunararch/helios/pkg/replay"
+	"github.com/lunararch/helios/pkg/save"
 	"github.com/lunararch/helios/pkg/scene"
+	"github.com/lunararch/helios/pkg/ui"
 )
 
 func init() {
@@ -17,6 +31,21 @@ func init() {
 }
 
 func main() {
+	execFile := flag.String("exec", "", "console script to run at startup")
+	headless := flag.Bool("headless", false, "hide the window and read console commands from stdin")
+	recordFile := flag.String("record", "", "record input and timing to a replay file")
+	replayFile := flag.String("replay", "", "play a replay file back, checking it doesn't diverge")
+	flag.Parse()
+
+	// Registered first so it runs after every other deferred cleanup; a
+	// headless replay that diverged exits with 1
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
 	err := glfw.Init()
 	if err != nil {
 		panic(err)
@@ -28,6 +57,9 @@ func main() {
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLDebugContext, glfw.True)
 	glfw.WindowHint(glfw.Resizable, glfw.True)
+	if *headless {
+		glfw.WindowHint(glfw.Visible, glfw.False)
+	}
 
 	window, err := glfw.CreateWindow(640, 480, "Helios - Animation System", nil, nil)
 	if err != nil {
@@ -42,17 +74,27 @@ func main() {
 		panic(err)
 	}
 
-	width, height := window.GetSize()
-	gl.Viewport(0, 0, int32(width), int32(height))
+	const width, height = 640, 480
 
 	gl.Enable(gl.BLEND)
 	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
 
+	// Sprite batches draw in painter's order with the depth test off, except
+	// in SortFrontToBack mode; see sprite.SortMode
 	gl.Enable(gl.DEPTH_TEST)
 	gl.DepthFunc(gl.LESS)
 
 	gl.ClearColor(0.2, 0.3, 0.8, 1.0)
 
+	// Shaders under assets/shaders are rebuilt when they change on disk
+	shaderWatcher := shader.EnableHotReload()
+
+	debugDrawer, err := debug.Enable()
+	if err != nil {
+		panic(err)
+	}
+	defer debugDrawer.Delete()
+
 	inputManager := input.NewInputManager(window)
 	inputMapping := input.NewInputMapping()
 
@@ -72,11 +114,22 @@ func main() {
 	inputMapping.MapKey("fast_forward", glfw.KeyTab)
 	inputMapping.MapKey("reset_time", glfw.KeyR)
 	inputMapping.MapKey("menu", glfw.KeyM)
+	ui.MapDefaultActions(inputMapping)
 
 	gameCamera := camera.New(float32(width), float32(height))
 	gameCamera.Position = mgl32.Vec2{float32(width) / 2, float32(height) / 2}
 	gameCamera.SetBounds(0, 0, float32(width), float32(height))
 
+	viewport := camera.NewViewport(gameCamera, camera.ScaleFit, width, height)
+	updateViewport := func() {
+		windowWidth, windowHeight := window.GetSize()
+		framebufferWidth, framebufferHeight := window.GetFramebufferSize()
+		viewport.Update(windowWidth, windowHeight, framebufferWidth, framebufferHeight)
+		viewport.Apply()
+	}
+	updateViewport()
+	inputManager.SetViewport(viewport)
+
 	sceneManager := scene.NewSceneManager()
 	defer sceneManager.Cleanup()
 
@@ -88,17 +141,350 @@ func main() {
 	sceneManager.RegisterScene(gameplayScene)
 	sceneManager.RegisterScene(animatedGameplayScene)
 
+	// The menu is either the first scene or pushed over gameplay with M
+	menuScene.OnStart = func(sceneName string) {
+		if sceneManager.PopScene() != nil {
+			sceneManager.SwitchToScene(sceneName)
+		}
+	}
+	menuScene.OnQuit = func() {
+		window.SetShouldClose(true)
+	}
+
 	sceneManager.SwitchToScene("menu")
 
+	gameLoop := engine.NewGameLoop(window)
+	gameLoop.UseFixedTimestep(true)
+	gameLoop.SetTargetFPS(60)
+
+	// The console's time_scale is the base the slow motion and fast forward
+	// keys multiply
+	timeScale := 1.0
+
+	devConsole := console.New()
+	devConsole.RegisterVar(console.FloatVar("time_scale", "Game speed, 1 is normal",
+		func() float64 { return timeScale },
+		func(value float64) { timeScale = max(value, 0) }))
+	devConsole.RegisterVar(console.IntVar("target_fps", "Frame rate limit, 0 for none",
+		gameLoop.GetTargetFPS, gameLoop.SetTargetFPS))
+	devConsole.RegisterVar(console.BoolVar("fixed_timestep", "Update in fixed steps instead of once a frame",
+		gameLoop.IsFixedTimestep, gameLoop.UseFixedTimestep))
+	devConsole.RegisterVar(console.FloatVar("fixed_delta", "Seconds per fixed update",
+		gameLoop.GetFixedDeltaTime, gameLoop.SetFixedDeltaTime))
+	devConsole.RegisterVar(console.BoolVar("paused", "Stops game time",
+		gameLoop.TimeManager().IsPaused,
+		func(paused bool) {
+			if paused {
+				gameLoop.PauseGame()
+			} else {
+				gameLoop.ResumeGame()
+			}
+		}))
+	devConsole.RegisterVar(console.BoolVar("debug_draw", "Draws debug shapes and overlays",
+		func() bool { return debugDrawer.Enabled },
+		func(enabled bool) { debugDrawer.Enabled = enabled }))
+
+	sceneNames := []string{"menu", "gameplay", "animated_gameplay"}
+	devConsole.RegisterCommand(console.Command{
+		Name:     "scene",
+		Args:     "<name>",
+		Help:     "Switches to a scene",
+		Run:      func(args []string) error { return sceneManager.SwitchToScene(args[0]) },
+		Complete: func(prefix string) []string { return sceneNames },
+	})
+	devConsole.RegisterCommand(console.Command{
+		Name: "stats",
+		Help: "Prints frame timing",
+		Run: func(args []string) error {
+			timeManager := gameLoop.TimeManager()
+			devConsole.Printf("fps %.1f, frame %.2f ms (min %.2f, max %.2f)", timeManager.FPS(),
+				timeManager.AvgDeltaTime()*1000, timeManager.MinDeltaTime()*1000, timeManager.MaxDeltaTime()*1000)
+			return nil
+		},
+	})
+	devConsole.RegisterCommand(console.Command{
+		Name: "reset_stats",
+		Help: "Resets frame timing",
+		Run: func(args []string) error {
+			gameLoop.TimeManager().ResetPerformanceStats()
+			return nil
+		},
+	})
+	devConsole.RegisterCommand(console.Command{
+		Name: "quit",
+		Help: "Closes the game",
+		Run: func(args []string) error {
+			window.SetShouldClose(true)
+			return nil
+		},
+	})
+
+	// Shared by the console, the profiler graph and the inspector, which draw
+	// one after the other
+	overlayShader, err := shader.New("assets/shaders/shape.vert", "assets/shaders/shape.frag")
+	if err != nil {
+		panic(err)
+	}
+	defer overlayShader.Delete()
+	overlayShapes := sprite.NewShapeBatch(overlayShader)
+	defer overlayShapes.Delete()
+	consoleOverlay := console.NewOverlay(devConsole, overlayShapes)
+	profilerOverlay := profiler.NewOverlay(profiler.Get(), overlayShapes)
+
+	overlayBatchShader, err := shader.New("assets/shaders/batch.vert", "assets/shaders/batch.frag")
+	if err != nil {
+		panic(err)
+	}
+	defer overlayBatchShader.Delete()
+	overlaySprites := sprite.NewSpriteBatch(overlayBatchShader)
+	defer overlaySprites.Delete()
+	entityInspector := inspector.NewInspector(gameCamera, ui.NewRenderer(overlaySprites, overlayShapes))
+
+	devConsole.RegisterVar(console.BoolVar("inspector", "Shows the entity inspector",
+		func() bool { return entityInspector.Visible },
+		func(visible bool) { entityInspector.Visible = visible }))
+
+	devConsole.RegisterVar(console.BoolVar("profiler", "Records frame timing",
+		profiler.IsEnabled, profiler.SetEnabled))
+	devConsole.RegisterVar(console.BoolVar("profiler_graph", "Shows the frame timing graph, recording while shown",
+		func() bool { return profilerOverlay.Visible },
+		func(visible bool) {
+			profilerOverlay.Visible = visible
+			if visible {
+				profiler.SetEnabled(true)
+			}
+		}))
+	devConsole.RegisterCommand(console.Command{
+		Name: "profile_save",
+		Args: "[file]",
+		Help: "Writes the recorded frames as a Chrome trace, for Perfetto",
+		Run: func(args []string) error {
+			path := "profile.json"
+			if len(args) == 1 {
+				path = args[0]
+			}
+			if err := profiler.Get().SaveChromeTrace(path); err != nil {
+				return err
+			}
+			devConsole.Printf("Saved %d frames to %s", profiler.Get().GetFrameCount(), path)
+			return nil
+		},
+	})
+
+	if *headless {
+		devConsole.SetOutput(os.Stdout)
+		devConsole.Feed(os.Stdin)
+	}
+	if *execFile != "" {
+		if err := devConsole.ExecFile(*execFile); err != nil {
+			devConsole.PrintError(err)
+		}
+	}
+
+	worldChecksum := func() uint64 {
+		if worldScene, ok := sceneManager.GetCurrentScene().(scene.WorldScene); ok {
+			return worldScene.GetWorld().Checksum()
+		}
+		return 0
+	}
+
+	// Replays start from a fresh game, so both begin here, after anything
+	// the startup script changed
+	var recorder *replay.Recorder
+	if *recordFile != "" {
+		// Reseeded so the recording and its playback draw the same numbers from here
+		engine.GetRandom().Seed(time.Now().UnixNano())
+		windowWidth, windowHeight := window.GetSize()
+		recorder, err = replay.Create(*recordFile, replay.Header{
+			Seed:           engine.GetRandom().GetSeed(),
+			FixedDeltaTime: gameLoop.GetFixedDeltaTime(),
+			Scene:          sceneManager.GetCurrentScene().GetName(),
+			Width:          windowWidth,
+			Height:         windowHeight,
+		})
+		if err != nil {
+			panic(err)
+		}
+		defer func() {
+			if recorder == nil {
+				return
+			}
+			if err := recorder.Close(); err != nil {
+				println(err.Error())
+			}
+			println("Recorded", recorder.GetTickCount(), "ticks to", *recordFile)
+		}()
+	}
+
+	var player *replay.Player
+	stopReplay := func() {
+		gameLoop.SetDriver(nil)
+		inputManager.SetSource(nil)
+		player = nil
+	}
+	if *replayFile != "" {
+		player, err = replay.Load(*replayFile)
+		if err != nil {
+			panic(err)
+		}
+
+		header := player.GetHeader()
+		if header.Scene != sceneManager.GetCurrentScene().GetName() {
+			devConsole.Printf("Replay was recorded from scene '%s', not '%s'", header.Scene, sceneManager.GetCurrentScene().GetName())
+		}
+		if windowWidth, windowHeight := window.GetSize(); windowWidth != header.Width || windowHeight != header.Height {
+			window.SetSize(header.Width, header.Height)
+		}
+		engine.GetRandom().Seed(header.Seed)
+		gameLoop.SetFixedDeltaTime(header.FixedDeltaTime)
+		gameLoop.SetDriver(player)
+		inputManager.SetSource(player)
+
+		// Without a window to watch, check the whole replay as fast as possible
+		if *headless {
+			player.FastForward(player.GetTickCount())
+		}
+		devConsole.Printf("Playing %d ticks from %s", player.GetTickCount(), *replayFile)
+	}
+
+	devConsole.RegisterVar(console.FloatVar("replay_speed", "Replay playback speed, 1 is real time",
+		func() float64 {
+			if player == nil {
+				return 0
+			}
+			return float64(player.Speed)
+		},
+		func(speed float64) {
+			if player != nil {
+				player.Speed = float32(max(speed, 0))
+			}
+		}))
+	devConsole.RegisterCommand(console.Command{
+		Name: "replay_seek",
+		Args: "<tick>",
+		Help: "Fast-forwards the replay to a tick",
+		Run: func(args []string) error {
+			if player == nil {
+				return fmt.Errorf("no replay is playing")
+			}
+			tick, err := strconv.Atoi(args[0])
+			if err != nil || tick < player.GetTick() {
+				return fmt.Errorf("'%s' is not a tick after %d", args[0], player.GetTick())
+			}
+			player.FastForward(tick)
+			return nil
+		},
+	})
+	devConsole.RegisterCommand(console.Command{
+		Name: "replay_stop",
+		Help: "Stops the replay and hands control back",
+		Run: func(args []string) error {
+			if player == nil {
+				return fmt.Errorf("no replay is playing")
+			}
+			devConsole.Printf("Replay stopped at tick %d of %d", player.GetTick(), player.GetTickCount())
+			stopReplay()
+			return nil
+		},
+	})
+
+	saveStore := save.NewStore("saves")
+	slotArg := func(args []string) string {
+		if len(args) == 1 {
+			return args[0]
+		}
+		return "quick"
+	}
+	completeSlots := func(prefix string) []string {
+		slots, _ := saveStore.List()
+		names := make([]string, len(slots))
+		for i, slot := range slots {
+			names[i] = slot.Slot
+		}
+		return names
+	}
+	devConsole.RegisterVar(console.BoolVar("save_compress", "Compresses new save games",
+		func() bool { return saveStore.Compress },
+		func(compress bool) { saveStore.Compress = compress }))
+	devConsole.RegisterCommand(console.Command{
+		Name:     "save",
+		Args:     "[slot]",
+		Help:     "Saves the game to a slot, quick by default",
+		Complete: completeSlots,
+		Run: func(args []string) error {
+			snapshot, err := save.Capture(sceneManager)
+			if err != nil {
+				return err
+			}
+			if err := saveStore.Save(slotArg(args), snapshot); err != nil {
+				return err
+			}
+			devConsole.Printf("Saved to slot '%s'", slotArg(args))
+			return nil
+		},
+	})
+	devConsole.RegisterCommand(console.Command{
+		Name:     "load",
+		Args:     "[slot]",
+		Help:     "Loads the game from a slot, quick by default",
+		Complete: completeSlots,
+		Run: func(args []string) error {
+			// Replays only reproduce runs from a fresh game
+			if recorder != nil || player != nil {
+				return fmt.Errorf("can't load a save while recording or playing a replay")
+			}
+			snapshot, err := saveStore.Load(slotArg(args))
+			if err != nil {
+				return err
+			}
+			if err := save.Restore(sceneManager, snapshot); err != nil {
+				return err
+			}
+			devConsole.Printf("Loaded slot '%s'", slotArg(args))
+			return nil
+		},
+	})
+	devConsole.RegisterCommand(console.Command{
+		Name: "saves",
+		Help: "Lists the save slots, newest first",
+		Run: func(args []string) error {
+			slots, err := saveStore.List()
+			if err != nil {
+				return err
+			}
+			if len(slots) == 0 {
+				devConsole.Printf("No saves in %s", saveStore.Dir)
+			}
+			for _, slot := range slots {
+				devConsole.Printf("%-12s %s  %s  %d bytes", slot.Slot, slot.SavedAt.Format("2006-01-02 15:04:05"), slot.Scene, slot.Size)
+			}
+			return nil
+		},
+	})
+	devConsole.RegisterCommand(console.Command{
+		Name:     "delete_save",
+		Args:     "<slot>",
+		Help:     "Deletes a save slot",
+		Complete: completeSlots,
+		Run:      func(args []string) error { return saveStore.Delete(args[0]) },
+	})
+
+	// The console and text fields take the keyboard from the game's bindings
+	keyboardTaken := func() bool {
+		return consoleOverlay.WantsKeyboard() || menuScene.WantsKeyboard() || entityInspector.WantsKeyboard()
+	}
+
 	inputManager.AddInputCallback(func(event input.InputEvent) {
 		switch e := event.(type) {
 		case input.KeyPressEvent:
+			if keyboardTaken() {
+				return
+			}
 			switch e.Key {
 			case glfw.KeyEscape:
-				window.SetShouldClose(true)
-			case glfw.KeyEnter:
-				if sceneManager.GetCurrentScene().GetName() == "menu" {
-					sceneManager.SwitchToScene("animated_gameplay")
+				// The menu's UI uses Escape to cancel and quits from there
+				if sceneManager.GetCurrentScene().GetName() != "menu" {
+					window.SetShouldClose(true)
 				}
 			case glfw.KeyM:
 				currentScene := sceneManager.GetCurrentScene().GetName()
@@ -107,6 +493,26 @@ func main() {
 				} else if currentScene == "menu" {
 					sceneManager.PopScene()
 				}
+			case glfw.KeyF1:
+				debugDrawer.ToggleOverlay(debug.OverlayTransforms)
+			case glfw.KeyF2:
+				debugDrawer.ToggleOverlay(debug.OverlayColliders)
+			case glfw.KeyF3:
+				debugDrawer.ToggleOverlay(debug.OverlayCameraBounds)
+			case glfw.KeyF4:
+				debugDrawer.ToggleOverlay(debug.OverlaySpriteBounds)
+			case glfw.KeyF5:
+				devConsole.Execute("toggle profiler_graph")
+			case glfw.KeyF6:
+				entityInspector.Toggle()
+			case glfw.KeyF7, glfw.KeyF8:
+				command := "save"
+				if e.Key == glfw.KeyF8 {
+					command = "load"
+				}
+				if err := devConsole.Execute(command); err != nil {
+					devConsole.PrintError(err)
+				}
 			case glfw.KeyG:
 				currentScene := sceneManager.GetCurrentScene().GetName()
 				if currentScene == "gameplay" {
@@ -119,50 +525,115 @@ func main() {
 	})
 
 	window.SetFramebufferSizeCallback(func(w *glfw.Window, width, height int) {
-		gl.Viewport(0, 0, int32(width), int32(height))
-		gameCamera.Size = mgl32.Vec2{float32(width), float32(height)}
+		updateViewport()
 	})
 
-	gameLoop := engine.NewGameLoop(window)
-	gameLoop.UseFixedTimestep(true)
-	gameLoop.SetTargetFPS(60)
-
 	gameLoop.SetUpdateFunc(func(deltaTime float32) {
+		scope := profiler.Begin("input")
 		inputManager.SetDeltaTime(deltaTime)
 		inputManager.Update()
+		scope.End()
+
+		debugDrawer.Update(deltaTime)
 
-		if inputMapping.IsActionPressed("pause", inputManager) {
-			gameLoop.TogglePause()
+		scope = profiler.Begin("console")
+		devConsole.Update()
+		consoleOverlay.Update(gameLoop.TimeManager().UnscaledDeltaTime(), inputManager)
+		scope.End()
+
+		if err := shaderWatcher.Update(); err != nil {
+			println(err.Error())
 		}
 
-		if inputMapping.IsActionPressed("reset_time", inputManager) {
-			gameLoop.TimeManager().ResetPerformanceStats()
+		if keyboardTaken() {
+			gameLoop.SetTimeScale(timeScale)
+		} else {
+			if inputMapping.IsActionPressed("pause", inputManager) {
+				gameLoop.TogglePause()
+			}
+
+			if inputMapping.IsActionPressed("reset_time", inputManager) {
+				gameLoop.TimeManager().ResetPerformanceStats()
+			}
+
+			if inputMapping.IsActionHeld("slow_motion", inputManager) {
+				gameLoop.SetTimeScale(timeScale * 0.3)
+			} else if inputMapping.IsActionHeld("fast_forward", inputManager) {
+				gameLoop.SetTimeScale(timeScale * 2.0)
+			} else {
+				gameLoop.SetTimeScale(timeScale)
+			}
 		}
 
-		if inputMapping.IsActionHeld("slow_motion", inputManager) {
-			gameLoop.SetTimeScale(0.3)
-		} else if inputMapping.IsActionHeld("fast_forward", inputManager) {
-			gameLoop.SetTimeScale(2.0)
+		if worldScene, ok := sceneManager.GetCurrentScene().(scene.WorldScene); ok {
+			entityInspector.SetWorld(worldScene.GetWorld())
 		} else {
-			gameLoop.SetTimeScale(1.0)
+			entityInspector.SetWorld(nil)
+		}
+		if !consoleOverlay.WantsKeyboard() {
+			scope = profiler.Begin("inspector")
+			entityInspector.Update(gameLoop.TimeManager().UnscaledDeltaTime(), inputManager)
+			scope.End()
 		}
 
+		scope = profiler.Begin("scene_update")
 		sceneManager.Update(deltaTime)
+		scope.End()
+
+		// Scenes get no input while the console is open, or while the inspector
+		// takes the keyboard or mouse
+		if !consoleOverlay.WantsKeyboard() && !entityInspector.WantsKeyboard() && !entityInspector.IsPointerOver() {
+			scope = profiler.Begin("scene_input")
+			sceneManager.HandleInput(inputManager, inputMapping)
+			scope.End()
+		}
 
-		sceneManager.HandleInput(inputManager, inputMapping)
+		if recorder != nil {
+			if err := recorder.Record(deltaTime, inputManager.GetFrame(), worldChecksum); err != nil {
+				devConsole.PrintError(err)
+				recorder.Close()
+				recorder = nil
+			}
+		}
+		if player != nil {
+			if err := player.Check(worldChecksum); err != nil {
+				devConsole.PrintError(err)
+			}
+			if player.IsFinished() {
+				devConsole.Printf("Replay finished: %d ticks, %d checkpoints matched", player.GetTickCount(), player.GetVerified())
+				if player.GetDivergence() != nil && *headless {
+					exitCode = 1
+				}
+				if *headless {
+					window.SetShouldClose(true)
+				}
+				stopReplay()
+			}
+		}
 	})
 
 	gameLoop.SetRenderFunc(func(alpha float32) {
 		sceneManager.Render(alpha)
+
+		scope := profiler.Begin("overlays")
+		entityInspector.Render()
+		profilerOverlay.Render(gameCamera)
+		consoleOverlay.Render(gameCamera)
+		scope.End()
 	})
 
 	println("Controls:")
-	println("Enter - Switch from menu to animated gameplay")
+	println("Menu: Arrows/Gamepad - Navigate, Enter/Space/A - Accept, Escape/B - Quit")
 	println("M - Toggle between animated gameplay and menu")
 	println("G - Switch between regular and animated gameplay")
 	println("WASD/Arrow Keys - Move camera")
 	println("E/Q - Zoom in/out")
 	println("1/2/3 - Control animations (Idle/Walk/Jump)")
+	println("F1-F4 - Debug overlays (transforms, colliders, camera bounds, sprite bounds)")
+	println("F5 - Profiler graph (profile_save in the console writes a Chrome trace)")
+	println("F6 - Entity inspector (click an entity to select it)")
+	println("F7/F8 - Quick save/load (save, load and saves in the console use slots)")
+	println("` - Developer console (help lists commands)")
 	println("P - Pause")
 	println("Escape - Quit")
 
@@ -0,0 +1,142 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (c *Console) registerBuiltins() {
+	commands := []Command{
+		{
+			Name:     "help",
+			Args:     "[name]",
+			Help:     "Lists commands, or describes one command or variable",
+			Run:      c.help,
+			Complete: c.completeName,
+		},
+		{
+			Name: "vars",
+			Args: "[prefix]",
+			Help: "Lists variables and their values",
+			Run:  c.listVars,
+		},
+		{
+			Name:     "set",
+			Args:     "<name> <value>",
+			Help:     "Sets a variable",
+			Run:      func(args []string) error { return c.withVar(args[0], func(v *Var) error { return v.Set(args[1]) }) },
+			Complete: c.completeVar,
+		},
+		{
+			Name: "get",
+			Args: "<name>",
+			Help: "Prints a variable",
+			Run: func(args []string) error {
+				return c.withVar(args[0], func(v *Var) error {
+					c.Printf("%s = %s", v.Name, v.Get())
+					return nil
+				})
+			},
+			Complete: c.completeVar,
+		},
+		{
+			Name:     "reset",
+			Args:     "<name>",
+			Help:     "Sets a variable back to its default",
+			Run:      func(args []string) error { return c.withVar(args[0], (*Var).Reset) },
+			Complete: c.completeVar,
+		},
+		{
+			Name: "toggle",
+			Args: "<name>",
+			Help: "Switches an on/off variable",
+			Run: func(args []string) error {
+				return c.withVar(args[0], func(v *Var) error {
+					if v.Get() == "0" {
+						return v.Set("1")
+					}
+					return v.Set("0")
+				})
+			},
+			Complete: c.completeVar,
+		},
+		{
+			Name: "echo",
+			Args: "[text...]",
+			Help: "Prints its arguments",
+			Run: func(args []string) error {
+				c.Print(strings.Join(args, " "))
+				return nil
+			},
+		},
+		{
+			Name: "exec",
+			Args: "<file>",
+			Help: "Runs a script file, one line at a time",
+			Run:  func(args []string) error { return c.ExecFile(args[0]) },
+		},
+		{
+			Name: "history",
+			Help: "Lists submitted lines",
+			Run: func(args []string) error {
+				for i, line := range c.history {
+					c.Printf("%3d  %s", i+1, line)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "clear",
+			Help: "Clears the output",
+			Run: func(args []string) error {
+				c.Clear()
+				return nil
+			},
+		},
+	}
+
+	for _, command := range commands {
+		c.RegisterCommand(command)
+	}
+}
+
+func (c *Console) withVar(name string, do func(v *Var) error) error {
+	v, exists := c.vars[name]
+	if !exists {
+		return fmt.Errorf("unknown variable '%s'", name)
+	}
+	return do(v)
+}
+
+func (c *Console) help(args []string) error {
+	if len(args) == 1 {
+		if command, exists := c.commands[args[0]]; exists {
+			c.Printf("%s - %s", command.usage(), command.Help)
+			return nil
+		}
+		if v, exists := c.vars[args[0]]; exists {
+			c.Printf("%s = %s (default %s) - %s", v.Name, v.Get(), v.Default, v.Help)
+			return nil
+		}
+		return fmt.Errorf("unknown command '%s'", args[0])
+	}
+
+	for _, name := range c.names() {
+		if command, exists := c.commands[name]; exists {
+			c.Printf("%s - %s", command.usage(), command.Help)
+		}
+	}
+	c.Print("Type vars to list variables")
+	return nil
+}
+
+func (c *Console) listVars(args []string) error {
+	for _, name := range c.varNames() {
+		if len(args) == 1 && !strings.HasPrefix(name, args[0]) {
+			continue
+		}
+		v := c.vars[name]
+		c.Printf("%s = %s - %s", v.Name, v.Get(), v.Help)
+	}
+	return nil
+}
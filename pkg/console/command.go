@@ -0,0 +1,54 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Command is something the console can run by name.
+type Command struct {
+	Name string
+	// Args describes the arguments after the name, like "<name> [value]":
+	// <required>, [optional], and a trailing ... for any number more. It is
+	// shown by help and used to check how many arguments were given.
+	Args string
+	Help string
+	Run  func(args []string) error
+	// Complete returns candidates for an argument being typed; nil means the
+	// arguments aren't completed.
+	Complete func(prefix string) []string
+
+	minArgs int
+	maxArgs int // -1 for no limit
+}
+
+// usage returns the name followed by the argument description.
+func (c *Command) usage() string {
+	if c.Args == "" {
+		return c.Name
+	}
+	return c.Name + " " + c.Args
+}
+
+// countArgs works out how many arguments Args allows.
+func (c *Command) countArgs() {
+	c.minArgs, c.maxArgs = 0, 0
+	for _, arg := range strings.Fields(c.Args) {
+		if strings.Contains(arg, "...") {
+			c.maxArgs = -1
+		}
+		if strings.HasPrefix(arg, "<") {
+			c.minArgs++
+		}
+		if c.maxArgs >= 0 {
+			c.maxArgs++
+		}
+	}
+}
+
+func (c *Command) checkArgs(args []string) error {
+	if len(args) < c.minArgs || (c.maxArgs >= 0 && len(args) > c.maxArgs) {
+		return fmt.Errorf("usage: %s", c.usage())
+	}
+	return nil
+}
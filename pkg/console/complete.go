@@ -0,0 +1,69 @@
+package console
+
+import (
+	"sort"
+	"strings"
+)
+
+// Complete returns the candidates for the word being typed at the end of a
+// line: command and variable names for the first word of a statement, or
+// whatever the command completes its arguments with.
+func (c *Console) Complete(line string) []string {
+	statement := line[strings.LastIndex(line, ";")+1:]
+	words := strings.Fields(statement)
+	if len(words) == 0 || strings.HasSuffix(statement, " ") {
+		words = append(words, "")
+	}
+	prefix := words[len(words)-1]
+
+	var candidates []string
+	if len(words) == 1 {
+		candidates = c.names()
+	} else if command, exists := c.commands[words[0]]; exists && command.Complete != nil {
+		candidates = command.Complete(prefix)
+	}
+
+	matches := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// AutoComplete extends the last word of a line as far as its candidates
+// agree, adding a space once there is only one. It returns the new line and
+// the candidates, so several can be listed.
+func (c *Console) AutoComplete(line string) (string, []string) {
+	matches := c.Complete(line)
+	if len(matches) == 0 {
+		return line, matches
+	}
+
+	start := strings.LastIndexAny(line, " ;") + 1
+	completed := line[:start] + commonPrefix(matches)
+	if len(matches) == 1 {
+		completed += " "
+	}
+	return completed, matches
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+func (c *Console) completeName(prefix string) []string {
+	return c.names()
+}
+
+func (c *Console) completeVar(prefix string) []string {
+	return c.varNames()
+}
@@ -0,0 +1,220 @@
+package console
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	MaxLines   = 500 // Output lines kept for the overlay
+	MaxHistory = 100 // Submitted lines kept for recall
+)
+
+// LineKind is what an output line is, for coloring it.
+type LineKind int
+
+const (
+	LineOutput LineKind = iota
+	LineInput           // A submitted line, echoed
+	LineError
+)
+
+type Line struct {
+	Text string
+	Kind LineKind
+}
+
+// Console runs commands and sets variables from lines of text, typed into
+// the overlay, read from stdin or run from script files.
+type Console struct {
+	commands map[string]*Command
+	vars     map[string]*Var
+	lines    []Line
+	history  []string
+	output   io.Writer
+	pending  chan string
+}
+
+// New creates a console with the built-in commands: help, vars, set, get,
+// reset, toggle, echo, exec, history and clear.
+func New() *Console {
+	c := &Console{
+		commands: make(map[string]*Command),
+		vars:     make(map[string]*Var),
+		lines:    make([]Line, 0),
+		history:  make([]string, 0),
+		pending:  make(chan string, 64),
+	}
+	c.registerBuiltins()
+	return c
+}
+
+// SetOutput also writes every output line to w, such as os.Stdout in
+// headless mode. Echoed input isn't written, since it came from there.
+func (c *Console) SetOutput(w io.Writer) {
+	c.output = w
+}
+
+func (c *Console) RegisterCommand(command Command) error {
+	if _, exists := c.commands[command.Name]; exists {
+		return fmt.Errorf("command '%s' already registered", command.Name)
+	}
+	if _, exists := c.vars[command.Name]; exists {
+		return fmt.Errorf("'%s' is already a variable", command.Name)
+	}
+	if command.Run == nil {
+		return fmt.Errorf("command '%s' has no Run function", command.Name)
+	}
+
+	command.countArgs()
+	c.commands[command.Name] = &command
+	return nil
+}
+
+// RegisterVar adds a variable, remembering its current value as the default.
+func (c *Console) RegisterVar(v *Var) error {
+	if _, exists := c.vars[v.Name]; exists {
+		return fmt.Errorf("variable '%s' already registered", v.Name)
+	}
+	if _, exists := c.commands[v.Name]; exists {
+		return fmt.Errorf("'%s' is already a command", v.Name)
+	}
+
+	v.Default = v.Get()
+	c.vars[v.Name] = v
+	return nil
+}
+
+func (c *Console) GetCommand(name string) (*Command, bool) {
+	command, exists := c.commands[name]
+	return command, exists
+}
+
+func (c *Console) GetVar(name string) (*Var, bool) {
+	v, exists := c.vars[name]
+	return v, exists
+}
+
+// Execute runs a line: commands separated by ';', each a command name or a
+// variable name followed by its arguments. A variable alone prints its
+// value; with an argument, it is set. It stops at the first error.
+func (c *Console) Execute(line string) error {
+	statements, err := parse(line)
+	if err != nil {
+		return err
+	}
+
+	for _, words := range statements {
+		if err := c.run(words[0], words[1:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Console) run(name string, args []string) error {
+	if command, exists := c.commands[name]; exists {
+		if err := command.checkArgs(args); err != nil {
+			return err
+		}
+		return command.Run(args)
+	}
+
+	if v, exists := c.vars[name]; exists {
+		switch len(args) {
+		case 0:
+			c.Printf("%s = %s", v.Name, v.Get())
+			return nil
+		case 1:
+			return v.Set(args[0])
+		default:
+			return fmt.Errorf("usage: %s [value]", v.Name)
+		}
+	}
+
+	return fmt.Errorf("unknown command '%s'", name)
+}
+
+// Submit runs a line typed by the user: it is echoed, added to the history
+// and any error is printed instead of returned.
+func (c *Console) Submit(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	c.addLine(Line{Text: "> " + line, Kind: LineInput})
+	if len(c.history) == 0 || c.history[len(c.history)-1] != line {
+		c.history = append(c.history, line)
+		if len(c.history) > MaxHistory {
+			c.history = c.history[len(c.history)-MaxHistory:]
+		}
+	}
+
+	if err := c.Execute(line); err != nil {
+		c.PrintError(err)
+	}
+}
+
+// GetHistory returns submitted lines, oldest first.
+func (c *Console) GetHistory() []string {
+	return c.history
+}
+
+func (c *Console) Print(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		c.addLine(Line{Text: line, Kind: LineOutput})
+	}
+}
+
+func (c *Console) Printf(format string, args ...any) {
+	c.Print(fmt.Sprintf(format, args...))
+}
+
+func (c *Console) PrintError(err error) {
+	c.addLine(Line{Text: err.Error(), Kind: LineError})
+}
+
+func (c *Console) addLine(line Line) {
+	c.lines = append(c.lines, line)
+	if len(c.lines) > MaxLines {
+		c.lines = c.lines[len(c.lines)-MaxLines:]
+	}
+
+	if c.output != nil && line.Kind != LineInput {
+		fmt.Fprintln(c.output, line.Text)
+	}
+}
+
+// GetLines returns the output, oldest first.
+func (c *Console) GetLines() []Line {
+	return c.lines
+}
+
+func (c *Console) Clear() {
+	c.lines = c.lines[:0]
+}
+
+// names returns the sorted names of every command and variable.
+func (c *Console) names() []string {
+	names := make([]string, 0, len(c.commands)+len(c.vars))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	for name := range c.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Console) varNames() []string {
+	names := make([]string, 0, len(c.vars))
+	for name := range c.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,285 @@
+package console
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+	"github.com/lunararch/helios/pkg/graphics/font"
+	"github.com/lunararch/helios/pkg/graphics/sprite"
+	"github.com/lunararch/helios/pkg/input"
+)
+
+const (
+	overlaySlideSpeed = 6 // Screen heights per second, as a fraction of Height
+	overlayPadding    = 6
+	overlayTextScale  = 2
+)
+
+var (
+	overlayBackground = mgl32.Vec4{0.02, 0.02, 0.05, 0.85}
+	overlayEdge       = mgl32.Vec4{0.45, 0.55, 1, 1}
+	overlayColors     = map[LineKind]mgl32.Vec4{
+		LineOutput: {0.85, 0.87, 0.95, 1},
+		LineInput:  {0.55, 0.65, 1, 1},
+		LineError:  {1, 0.4, 0.35, 1},
+	}
+)
+
+// Overlay is a console that drops down from the top of the screen. While it
+// is open it takes the keyboard: typed text, Enter to submit, Up and Down
+// for history, Tab to complete, Page Up and Page Down to scroll, and Escape
+// or the toggle key to close.
+type Overlay struct {
+	ToggleKey glfw.Key
+	Height    float32 // Fraction of the screen covered when open
+
+	console *Console
+	shapes  *sprite.ShapeBatch
+
+	open   bool
+	slide  float32 // 0 closed to 1 open
+	input  []rune
+	caret  int
+	recall int    // History index being shown; len(history) is the new line
+	draft  string // The new line, kept while browsing history
+	scroll int    // Output lines scrolled back from the newest
+	blink  float32
+
+	visibleLines int // Output lines that fit at the last render, for paging
+}
+
+func NewOverlay(console *Console, shapes *sprite.ShapeBatch) *Overlay {
+	return &Overlay{
+		ToggleKey: glfw.KeyGraveAccent,
+		Height:    0.45,
+		console:   console,
+		shapes:    shapes,
+		input:     make([]rune, 0),
+	}
+}
+
+func (o *Overlay) IsOpen() bool {
+	return o.open
+}
+
+// WantsKeyboard is true while the overlay is open, so the game's own key
+// bindings should be ignored.
+func (o *Overlay) WantsKeyboard() bool {
+	return o.open
+}
+
+func (o *Overlay) Open() {
+	o.open = true
+	o.recall = len(o.console.GetHistory())
+	o.blink = 0
+}
+
+func (o *Overlay) Close() {
+	o.open = false
+}
+
+func (o *Overlay) Toggle() {
+	if o.open {
+		o.Close()
+	} else {
+		o.Open()
+	}
+}
+
+// Update slides the overlay and, while it is open, edits the input line
+// with this frame's typing.
+func (o *Overlay) Update(deltaTime float32, inputManager *input.InputManager) {
+	if o.open {
+		o.slide = min(o.slide+deltaTime*overlaySlideSpeed, 1)
+		o.blink += deltaTime
+	} else {
+		o.slide = max(o.slide-deltaTime*overlaySlideSpeed, 0)
+	}
+
+	for _, key := range inputManager.GetKeyPresses() {
+		if key == o.ToggleKey {
+			// The toggle key's character is typed too; drop this frame's text
+			o.Toggle()
+			return
+		}
+		if o.open {
+			o.handleKey(key)
+		}
+	}
+
+	if o.open {
+		o.insert(inputManager.GetTypedText())
+	}
+}
+
+func (o *Overlay) insert(text string) {
+	for _, r := range text {
+		if unicode.IsControl(r) {
+			continue
+		}
+		o.input = append(o.input, 0)
+		copy(o.input[o.caret+1:], o.input[o.caret:])
+		o.input[o.caret] = r
+		o.caret++
+	}
+	if text != "" {
+		o.blink = 0
+	}
+}
+
+func (o *Overlay) setInput(text string) {
+	o.input = []rune(text)
+	o.caret = len(o.input)
+}
+
+func (o *Overlay) handleKey(key glfw.Key) {
+	o.blink = 0
+	history := o.console.GetHistory()
+
+	switch key {
+	case glfw.KeyEnter, glfw.KeyKPEnter:
+		line := string(o.input)
+		o.setInput("")
+		o.scroll = 0
+		o.console.Submit(line)
+		o.recall = len(o.console.GetHistory())
+
+	case glfw.KeyEscape:
+		o.Close()
+
+	case glfw.KeyBackspace:
+		if o.caret > 0 {
+			o.input = append(o.input[:o.caret-1], o.input[o.caret:]...)
+			o.caret--
+		}
+	case glfw.KeyDelete:
+		if o.caret < len(o.input) {
+			o.input = append(o.input[:o.caret], o.input[o.caret+1:]...)
+		}
+	case glfw.KeyLeft:
+		o.caret = max(o.caret-1, 0)
+	case glfw.KeyRight:
+		o.caret = min(o.caret+1, len(o.input))
+	case glfw.KeyHome:
+		o.caret = 0
+	case glfw.KeyEnd:
+		o.caret = len(o.input)
+
+	case glfw.KeyUp:
+		if o.recall > 0 {
+			if o.recall == len(history) {
+				o.draft = string(o.input)
+			}
+			o.recall--
+			o.setInput(history[o.recall])
+		}
+	case glfw.KeyDown:
+		if o.recall < len(history) {
+			o.recall++
+			if o.recall == len(history) {
+				o.setInput(o.draft)
+			} else {
+				o.setInput(history[o.recall])
+			}
+		}
+
+	case glfw.KeyTab:
+		// Complete up to the caret, keeping whatever follows it
+		before, after := string(o.input[:o.caret]), string(o.input[o.caret:])
+		completed, matches := o.console.AutoComplete(before)
+		if len(matches) > 1 && completed == before {
+			o.console.Print(strings.Join(matches, "  "))
+		}
+		o.setInput(completed + after)
+		o.caret = len([]rune(completed))
+
+	case glfw.KeyPageUp:
+		o.scroll = min(o.scroll+o.pageLines(), max(len(o.console.GetLines())-1, 0))
+	case glfw.KeyPageDown:
+		o.scroll = max(o.scroll-o.pageLines(), 0)
+	}
+}
+
+func (o *Overlay) pageLines() int {
+	return max(o.visibleLines/2, 1)
+}
+
+// Render draws the overlay over the whole camera, in its pixels.
+func (o *Overlay) Render(cam *camera.Camera) {
+	if o.slide <= 0 {
+		return
+	}
+
+	size := cam.Size
+	height := float32(int(size.Y() * o.Height))
+	top := float32(math.Round(float64(-height * (1 - easeOut(o.slide)))))
+	lineHeight := float32(font.LineAdvance * overlayTextScale)
+	charWidth := float32(font.GlyphAdvance * overlayTextScale)
+	columns := max(int((size.X()-2*overlayPadding)/charWidth), 4)
+
+	o.shapes.SetCamera(cam.GetProjectionMatrix(), mgl32.Ident4())
+	feather := o.shapes.GetFeather()
+	o.shapes.Begin()
+
+	o.shapes.FillRect(mgl32.Vec2{0, top}, mgl32.Vec2{size.X(), top + height}, sprite.SolidPaint(overlayBackground))
+	o.shapes.FillRect(mgl32.Vec2{0, top + height - 2}, mgl32.Vec2{size.X(), top + height}, sprite.SolidPaint(overlayEdge))
+
+	o.shapes.SetFeather(0)
+
+	// Input line at the bottom, scrolled so the caret stays in view
+	inputY := top + height - 2 - overlayPadding - font.GlyphHeight*overlayTextScale
+	prompt := "> "
+	first := max(o.caret+len(prompt)-columns+1, 0)
+	visible := o.input[first:]
+	if len(visible) > columns-len(prompt) {
+		visible = visible[:columns-len(prompt)]
+	}
+	o.text(mgl32.Vec2{overlayPadding, inputY}, prompt+string(visible), overlayColors[LineInput])
+	if int(o.blink*2)%2 == 0 {
+		caretX := overlayPadding + float32(len(prompt)+o.caret-first)*charWidth
+		// One font pixel wide, in the gap before the next character
+		o.shapes.FillRect(mgl32.Vec2{caretX - overlayTextScale, inputY},
+			mgl32.Vec2{caretX, inputY + font.GlyphHeight*overlayTextScale}, sprite.SolidPaint(overlayColors[LineOutput]))
+	}
+
+	// Output above it, newest at the bottom, long lines wrapped
+	lines := o.console.GetLines()
+	o.visibleLines = max(int((inputY-top-overlayPadding)/lineHeight), 0)
+	y := inputY - lineHeight
+	for i := len(lines) - 1 - o.scroll; i >= 0 && y >= top+overlayPadding; i-- {
+		rows := wrap(lines[i].Text, columns)
+		for row := len(rows) - 1; row >= 0 && y >= top+overlayPadding; row-- {
+			o.text(mgl32.Vec2{overlayPadding, y}, rows[row], overlayColors[lines[i].Kind])
+			y -= lineHeight
+		}
+	}
+
+	o.shapes.End()
+	o.shapes.SetFeather(feather)
+}
+
+func (o *Overlay) text(position mgl32.Vec2, text string, color mgl32.Vec4) {
+	paint := sprite.SolidPaint(color)
+	for _, run := range font.Layout(position, text, overlayTextScale) {
+		o.shapes.FillRect(run.Min, run.Max, paint)
+	}
+}
+
+// wrap breaks text into rows of at most columns characters.
+func wrap(text string, columns int) []string {
+	runes := []rune(text)
+	rows := make([]string, 0, len(runes)/columns+1)
+	for len(runes) > columns {
+		rows = append(rows, string(runes[:columns]))
+		runes = runes[columns:]
+	}
+	return append(rows, string(runes))
+}
+
+func easeOut(t float32) float32 {
+	return 1 - (1-t)*(1-t)
+}
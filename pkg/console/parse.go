@@ -0,0 +1,69 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parse splits a line into statements of words. Words are separated by
+// spaces, or quoted with "" to keep spaces and ';' in them, where \" and \\
+// escape; ';' ends a statement and // comments out the rest of the line.
+func parse(line string) ([][]string, error) {
+	statements := make([][]string, 0, 1)
+	words := make([]string, 0, 4)
+	var word strings.Builder
+	inWord, quoted := false, false
+
+	endWord := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+	endStatement := func() {
+		endWord()
+		if len(words) > 0 {
+			statements = append(statements, words)
+			words = make([]string, 0, 4)
+		}
+	}
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if quoted {
+			switch {
+			case r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\'):
+				i++
+				word.WriteRune(runes[i])
+			case r == '"':
+				quoted = false
+			default:
+				word.WriteRune(r)
+			}
+			continue
+		}
+
+		switch {
+		case r == '"':
+			quoted, inWord = true, true
+		case r == ';':
+			endStatement()
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			i = len(runes)
+		case r == ' ' || r == '\t':
+			endWord()
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	endStatement()
+	return statements, nil
+}
@@ -0,0 +1,49 @@
+package console
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want [][]string
+		err  bool
+	}{
+		{line: "", want: [][]string{}},
+		{line: "   ", want: [][]string{}},
+		{line: "help", want: [][]string{{"help"}}},
+		{line: "  set  volume\t0.5 ", want: [][]string{{"set", "volume", "0.5"}}},
+		{line: "echo one; echo two", want: [][]string{{"echo", "one"}, {"echo", "two"}}},
+		{line: ";; echo one ;;", want: [][]string{{"echo", "one"}}},
+		{line: `echo "hello world"`, want: [][]string{{"echo", "hello world"}}},
+		{line: `echo "a; b // c"`, want: [][]string{{"echo", "a; b // c"}}},
+		{line: `echo ""`, want: [][]string{{"echo", ""}}},
+		{line: `echo "say \"hi\"" "back\\slash" "\n"`, want: [][]string{{"echo", `say "hi"`, `back\slash`, `\n`}}},
+		{line: `echo pre"quoted part"post`, want: [][]string{{"echo", "prequoted partpost"}}},
+		{line: "echo one // echo two; echo three", want: [][]string{{"echo", "one"}}},
+		{line: "// all comment", want: [][]string{}},
+		{line: "bind / zoom", want: [][]string{{"bind", "/", "zoom"}}},
+		{line: "echo héllo wörld", want: [][]string{{"echo", "héllo", "wörld"}}},
+		{line: `echo "unterminated`, err: true},
+		{line: `echo "escaped end\"`, err: true},
+	}
+
+	for _, test := range tests {
+		got, err := parse(test.line)
+		if test.err {
+			if err == nil {
+				t.Errorf("parse(%q) = %q, want an error", test.line, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parse(%q): %v", test.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parse(%q) = %q, want %q", test.line, got, test.want)
+		}
+	}
+}
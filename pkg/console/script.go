@@ -0,0 +1,65 @@
+package console
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ExecFile runs a script file; see ExecReader.
+func (c *Console) ExecFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open script '%s': %w", path, err)
+	}
+	defer file.Close()
+
+	return c.ExecReader(file, path)
+}
+
+// ExecReader runs each line of a script, skipping blank lines and lines
+// starting with #. A failing line prints its error with the script's name
+// and line number, and the script carries on.
+func (c *Console) ExecReader(r io.Reader, name string) error {
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := c.Execute(line); err != nil {
+			c.PrintError(fmt.Errorf("%s:%d: %w", name, number, err))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read script '%s': %w", name, err)
+	}
+	return nil
+}
+
+// Feed reads lines from r in the background, such as os.Stdin for headless
+// mode, and queues them for Update to submit on the game's thread.
+func (c *Console) Feed(r io.Reader) {
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			c.pending <- scanner.Text()
+		}
+	}()
+}
+
+// Update submits the lines Feed has read since the last call. Call it once
+// a frame.
+func (c *Console) Update() {
+	for {
+		select {
+		case line := <-c.pending:
+			c.Submit(line)
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package console
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Var is a console variable: a setting read and written as text, usually
+// bound to a getter and setter of an engine setting so the two can't drift
+// apart.
+type Var struct {
+	Name    string
+	Help    string
+	Default string // Value when the variable was registered, restored by reset
+
+	get func() string
+	set func(value string) error
+}
+
+// NewVar creates a variable from text accessors; the typed constructors
+// below cover most settings.
+func NewVar(name, help string, get func() string, set func(value string) error) *Var {
+	return &Var{Name: name, Help: help, get: get, set: set}
+}
+
+func FloatVar(name, help string, get func() float64, set func(value float64)) *Var {
+	return NewVar(name, help,
+		func() string { return strconv.FormatFloat(get(), 'g', -1, 64) },
+		func(value string) error {
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("'%s' is not a number", value)
+			}
+			set(number)
+			return nil
+		})
+}
+
+func IntVar(name, help string, get func() int, set func(value int)) *Var {
+	return NewVar(name, help,
+		func() string { return strconv.Itoa(get()) },
+		func(value string) error {
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("'%s' is not a whole number", value)
+			}
+			set(number)
+			return nil
+		})
+}
+
+// BoolVar accepts 1/0, true/false and on/off.
+func BoolVar(name, help string, get func() bool, set func(value bool)) *Var {
+	return NewVar(name, help,
+		func() string {
+			if get() {
+				return "1"
+			}
+			return "0"
+		},
+		func(value string) error {
+			switch value {
+			case "1", "true", "on":
+				set(true)
+			case "0", "false", "off":
+				set(false)
+			default:
+				return fmt.Errorf("'%s' is not on or off", value)
+			}
+			return nil
+		})
+}
+
+func StringVar(name, help string, get func() string, set func(value string)) *Var {
+	return NewVar(name, help, get, func(value string) error {
+		set(value)
+		return nil
+	})
+}
+
+func (v *Var) Get() string {
+	return v.get()
+}
+
+func (v *Var) Set(value string) error {
+	if err := v.set(value); err != nil {
+		return fmt.Errorf("can't set '%s': %w", v.Name, err)
+	}
+	return nil
+}
+
+func (v *Var) Reset() error {
+	return v.Set(v.Default)
+}
@@ -0,0 +1,109 @@
+package debug
+
+import (
+	"embed"
+
+	"github.com/go-gl/gl/all-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/shader"
+)
+
+//go:embed shaders
+var shaderFiles embed.FS
+
+const vertexSize = 6 // 2 for position, 4 for color
+
+// lineBatch draws colored lines and triangles with one draw call each.
+type lineBatch struct {
+	shader    *shader.Shader
+	vao       uint32
+	vbo       uint32
+	lines     []float32
+	triangles []float32
+}
+
+func newLineBatch() (*lineBatch, error) {
+	vertexCode, err := shaderFiles.ReadFile("shaders/debug.vert")
+	if err != nil {
+		return nil, err
+	}
+	fragmentCode, err := shaderFiles.ReadFile("shaders/debug.frag")
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := shader.NewFromSource(string(vertexCode), string(fragmentCode))
+	if err != nil {
+		return nil, err
+	}
+
+	b := &lineBatch{
+		shader:    program,
+		lines:     make([]float32, 0),
+		triangles: make([]float32, 0),
+	}
+
+	gl.GenVertexArrays(1, &b.vao)
+	gl.GenBuffers(1, &b.vbo)
+	gl.BindVertexArray(b.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, vertexSize*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(1, 4, gl.FLOAT, false, vertexSize*4, gl.PtrOffset(2*4))
+	gl.EnableVertexAttribArray(1)
+
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	return b, nil
+}
+
+func appendVertex(vertices []float32, position mgl32.Vec2, color mgl32.Vec4) []float32 {
+	return append(vertices, position.X(), position.Y(), color.X(), color.Y(), color.Z(), color.W())
+}
+
+func (b *lineBatch) line(from, to mgl32.Vec2, color mgl32.Vec4) {
+	b.lines = appendVertex(b.lines, from, color)
+	b.lines = appendVertex(b.lines, to, color)
+}
+
+func (b *lineBatch) triangle(p1, p2, p3 mgl32.Vec2, color mgl32.Vec4) {
+	b.triangles = appendVertex(b.triangles, p1, color)
+	b.triangles = appendVertex(b.triangles, p2, color)
+	b.triangles = appendVertex(b.triangles, p3, color)
+}
+
+// flush draws everything queued with the given matrices.
+func (b *lineBatch) flush(projection, view mgl32.Mat4) {
+	if len(b.lines) == 0 && len(b.triangles) == 0 {
+		return
+	}
+
+	b.shader.Use()
+	b.shader.SetMat4("projection", projection)
+	b.shader.SetMat4("view", view)
+
+	gl.BindVertexArray(b.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+	for _, pass := range []struct {
+		vertices []float32
+		mode     uint32
+	}{{b.triangles, gl.TRIANGLES}, {b.lines, gl.LINES}} {
+		if len(pass.vertices) == 0 {
+			continue
+		}
+		gl.BufferData(gl.ARRAY_BUFFER, len(pass.vertices)*4, gl.Ptr(pass.vertices), gl.STREAM_DRAW)
+		gl.DrawArrays(pass.mode, 0, int32(len(pass.vertices)/vertexSize))
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindVertexArray(0)
+
+	b.lines = b.lines[:0]
+	b.triangles = b.triangles[:0]
+}
+
+func (b *lineBatch) delete() {
+	gl.DeleteVertexArrays(1, &b.vao)
+	gl.DeleteBuffers(1, &b.vbo)
+	b.shader.Delete()
+}
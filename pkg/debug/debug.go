@@ -0,0 +1,189 @@
+package debug
+
+import (
+	"github.com/go-gl/gl/all-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+)
+
+var (
+	Red     = mgl32.Vec4{1, 0.2, 0.2, 1}
+	Green   = mgl32.Vec4{0.2, 1, 0.2, 1}
+	Blue    = mgl32.Vec4{0.3, 0.5, 1, 1}
+	Yellow  = mgl32.Vec4{1, 1, 0.2, 1}
+	Cyan    = mgl32.Vec4{0.2, 1, 1, 1}
+	Magenta = mgl32.Vec4{1, 0.2, 1, 1}
+	White   = mgl32.Vec4{1, 1, 1, 1}
+)
+
+// Space decides what a shape's coordinates are relative to.
+type Space int
+
+const (
+	WorldSpace  Space = iota // World units, moving with the camera
+	ScreenSpace              // Camera pixels, (0, 0) to the camera size
+)
+
+type segment struct {
+	from, to mgl32.Vec2
+}
+
+type triangle [3]mgl32.Vec2
+
+// shape is one queued draw, already broken into lines and triangles.
+type shape struct {
+	space     Space
+	color     mgl32.Vec4
+	remaining float32 // Seconds left; shapes without a duration last one frame
+	scale     float32 // Text pixel size
+	segments  []segment
+	triangles []triangle
+}
+
+// Option changes how a shape is drawn.
+type Option func(*shape)
+
+// InScreenSpace places the shape in camera pixels instead of the world.
+func InScreenSpace() Option {
+	return func(s *shape) { s.space = ScreenSpace }
+}
+
+// For keeps the shape on screen for a number of seconds instead of one frame.
+func For(seconds float32) Option {
+	return func(s *shape) { s.remaining = seconds }
+}
+
+// Scale sets the size of text pixels, in world units or camera pixels.
+func Scale(scale float32) Option {
+	return func(s *shape) { s.scale = scale }
+}
+
+// Drawer collects debug shapes from anywhere in the game and draws them on
+// top of the scene. Shapes are queued during the update and drawn by Render;
+// Update drops the ones that have expired, so call it before updating the
+// game each frame.
+type Drawer struct {
+	Enabled bool
+
+	batch    *lineBatch
+	shapes   []*shape
+	overlays Overlay
+}
+
+var drawer *Drawer
+
+// Enable creates the drawer used by the package-level drawing functions,
+// which do nothing until it is called.
+func Enable() (*Drawer, error) {
+	if drawer != nil {
+		return drawer, nil
+	}
+
+	batch, err := newLineBatch()
+	if err != nil {
+		return nil, err
+	}
+
+	drawer = &Drawer{
+		Enabled: true,
+		batch:   batch,
+		shapes:  make([]*shape, 0),
+	}
+	return drawer, nil
+}
+
+// Get returns the drawer made by Enable, or nil.
+func Get() *Drawer {
+	return drawer
+}
+
+func (d *Drawer) add(s *shape, options []Option) {
+	if d == nil || !d.Enabled {
+		return
+	}
+
+	if s.scale == 0 {
+		s.scale = 1
+	}
+	for _, option := range options {
+		option(s)
+	}
+	d.shapes = append(d.shapes, s)
+}
+
+// Update ages shapes drawn with For and drops expired ones, including every
+// one-frame shape from the last frame.
+func (d *Drawer) Update(deltaTime float32) {
+	if d == nil {
+		return
+	}
+
+	kept := d.shapes[:0]
+	for _, s := range d.shapes {
+		s.remaining -= deltaTime
+		if s.remaining > 0 {
+			kept = append(kept, s)
+		}
+	}
+	clear(d.shapes[len(kept):])
+	d.shapes = kept
+}
+
+// Clear drops every queued shape.
+func (d *Drawer) Clear() {
+	clear(d.shapes)
+	d.shapes = d.shapes[:0]
+}
+
+// Render draws the queued shapes through a camera, without depth testing so
+// they stay on top. It can run once per camera; shapes stay queued until
+// Update.
+func (d *Drawer) Render(cam *camera.Camera) {
+	if d == nil || !d.Enabled {
+		return
+	}
+	d.renderShapes(d.shapes, cam)
+}
+
+func (d *Drawer) renderShapes(shapes []*shape, cam *camera.Camera) {
+	if len(shapes) == 0 {
+		return
+	}
+
+	depthTest := gl.IsEnabled(gl.DEPTH_TEST)
+	gl.Disable(gl.DEPTH_TEST)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+
+	projection := cam.GetProjectionMatrix()
+	for _, space := range []Space{WorldSpace, ScreenSpace} {
+		for _, s := range shapes {
+			if s.space != space {
+				continue
+			}
+			for _, seg := range s.segments {
+				d.batch.line(seg.from, seg.to, s.color)
+			}
+			for _, tri := range s.triangles {
+				d.batch.triangle(tri[0], tri[1], tri[2], s.color)
+			}
+		}
+
+		view := mgl32.Ident4()
+		if space == WorldSpace {
+			view = cam.GetViewMatrix()
+		}
+		d.batch.flush(projection, view)
+	}
+
+	if depthTest {
+		gl.Enable(gl.DEPTH_TEST)
+	}
+}
+
+func (d *Drawer) Delete() {
+	d.batch.delete()
+	if drawer == d {
+		drawer = nil
+	}
+}
@@ -0,0 +1,27 @@
+package debug
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/font"
+)
+
+// TextSize returns the size of a label at a text scale, for placing it.
+func TextSize(text string, scale float32) mgl32.Vec2 {
+	return font.Measure(text, scale)
+}
+
+// layoutText turns a label into triangles, two per horizontal run of lit
+// pixels.
+func layoutText(position mgl32.Vec2, text string, scale float32) []triangle {
+	runs := font.Layout(position, text, scale)
+	triangles := make([]triangle, 0, len(runs)*2)
+	for _, run := range runs {
+		topRight := mgl32.Vec2{run.Max.X(), run.Min.Y()}
+		bottomLeft := mgl32.Vec2{run.Min.X(), run.Max.Y()}
+		triangles = append(triangles,
+			triangle{run.Min, topRight, bottomLeft},
+			triangle{topRight, run.Max, bottomLeft},
+		)
+	}
+	return triangles
+}
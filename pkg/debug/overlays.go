@@ -0,0 +1,105 @@
+package debug
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/entity"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+)
+
+// Overlay is a built-in visualization drawn by RenderOverlays.
+type Overlay int
+
+const (
+	OverlayTransforms   Overlay = 1 << iota // Entity origins, axes and names
+	OverlayColliders                        // Shapes of components implementing Outlined
+	OverlayCameraBounds                     // Limits from camera.SetBounds, follow targets and dead zone
+	OverlaySpriteBounds                     // Culling bounds of sprites
+
+	OverlayNone Overlay = 0
+	OverlayAll          = OverlayTransforms | OverlayColliders | OverlayCameraBounds | OverlaySpriteBounds
+)
+
+// Outlined is implemented by components with a world-space shape worth
+// showing under OverlayColliders, such as occluders.
+type Outlined interface {
+	GetOutline() []mgl32.Vec2
+}
+
+func (d *Drawer) SetOverlay(overlay Overlay, enabled bool) {
+	if enabled {
+		d.overlays |= overlay
+	} else {
+		d.overlays &^= overlay
+	}
+}
+
+func (d *Drawer) ToggleOverlay(overlay Overlay) {
+	d.overlays ^= overlay
+}
+
+func (d *Drawer) IsOverlayEnabled(overlay Overlay) bool {
+	return d != nil && d.overlays&overlay != 0
+}
+
+// RenderOverlays draws the enabled overlays for a world seen through a
+// camera. Unlike queued shapes they are rebuilt every time, so it belongs
+// in the scene's render.
+func (d *Drawer) RenderOverlays(world *entity.World, cam *camera.Camera) {
+	if !d.active() || d.overlays == OverlayNone {
+		return
+	}
+
+	overlay := &Drawer{Enabled: true, shapes: make([]*shape, 0)}
+
+	if d.overlays&OverlayCameraBounds != 0 {
+		overlay.cameraOverlay(cam)
+	}
+
+	for _, e := range world.GetActiveEntities() {
+		if !cam.SeesLayer(e.GetLayer()) {
+			continue
+		}
+
+		for _, component := range e.GetComponents() {
+			if !component.IsActive() {
+				continue
+			}
+			if cullable, ok := component.(entity.CullableComponent); ok && d.overlays&OverlaySpriteBounds != 0 {
+				overlay.Bounds(cullable.GetBounds(), Cyan)
+			}
+			if outlined, ok := component.(Outlined); ok && d.overlays&OverlayColliders != 0 {
+				overlay.Polygon(outlined.GetOutline(), Yellow)
+			}
+		}
+
+		if d.overlays&OverlayTransforms != 0 {
+			overlay.transformOverlay(e)
+		}
+	}
+
+	d.renderShapes(overlay.shapes, cam)
+}
+
+func (d *Drawer) transformOverlay(e *entity.Entity) {
+	position := e.GetWorldPosition().Vec2()
+	rotation := mgl32.Rotate2D(e.GetWorldRotation())
+
+	d.Arrow(position, position.Add(rotation.Mul2x1(mgl32.Vec2{20, 0})), Red)
+	d.Arrow(position, position.Add(rotation.Mul2x1(mgl32.Vec2{0, 20})), Green)
+	d.Text(position.Add(mgl32.Vec2{4, 4}), e.GetName(), White)
+}
+
+func (d *Drawer) cameraOverlay(cam *camera.Camera) {
+	if cam.BoundsEnabled {
+		d.Rect(cam.MinBounds, cam.MaxBounds, Magenta)
+	}
+
+	for _, target := range cam.GetTargets() {
+		d.Cross(*target, 12, Magenta)
+	}
+
+	if deadZone := cam.Follow.DeadZone; deadZone.X() > 0 || deadZone.Y() > 0 {
+		center := cam.Size.Mul(0.5)
+		d.Rect(center.Sub(deadZone.Mul(0.5)), center.Add(deadZone.Mul(0.5)), Magenta, InScreenSpace())
+	}
+}
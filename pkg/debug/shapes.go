@@ -0,0 +1,157 @@
+package debug
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+)
+
+func (d *Drawer) active() bool {
+	return d != nil && d.Enabled
+}
+
+func (d *Drawer) Line(from, to mgl32.Vec2, color mgl32.Vec4, options ...Option) {
+	if !d.active() {
+		return
+	}
+	d.add(&shape{color: color, segments: []segment{{from, to}}}, options)
+}
+
+// Polyline connects the points in order without closing the shape.
+func (d *Drawer) Polyline(points []mgl32.Vec2, color mgl32.Vec4, options ...Option) {
+	if !d.active() || len(points) < 2 {
+		return
+	}
+
+	segments := make([]segment, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		segments = append(segments, segment{points[i-1], points[i]})
+	}
+	d.add(&shape{color: color, segments: segments}, options)
+}
+
+// Polygon outlines a closed shape.
+func (d *Drawer) Polygon(points []mgl32.Vec2, color mgl32.Vec4, options ...Option) {
+	if !d.active() || len(points) < 2 {
+		return
+	}
+
+	segments := make([]segment, 0, len(points))
+	for i := range points {
+		segments = append(segments, segment{points[i], points[(i+1)%len(points)]})
+	}
+	d.add(&shape{color: color, segments: segments}, options)
+}
+
+// Rect outlines the box between two corners.
+func (d *Drawer) Rect(minPoint, maxPoint mgl32.Vec2, color mgl32.Vec4, options ...Option) {
+	d.Polygon([]mgl32.Vec2{
+		minPoint, {maxPoint.X(), minPoint.Y()}, maxPoint, {minPoint.X(), maxPoint.Y()},
+	}, color, options...)
+}
+
+func (d *Drawer) Bounds(bounds camera.Bounds, color mgl32.Vec4, options ...Option) {
+	d.Rect(bounds.Min, bounds.Max, color, options...)
+}
+
+func (d *Drawer) Circle(center mgl32.Vec2, radius float32, color mgl32.Vec4, options ...Option) {
+	if !d.active() || radius <= 0 {
+		return
+	}
+
+	// More segments for bigger circles, so they stay round
+	count := int(mgl32.Clamp(radius/4, 12, 64))
+	points := make([]mgl32.Vec2, count)
+	for i := range points {
+		angle := float64(i) / float64(count) * 2 * math.Pi
+		points[i] = center.Add(mgl32.Vec2{float32(math.Cos(angle)), float32(math.Sin(angle))}.Mul(radius))
+	}
+	d.Polygon(points, color, options...)
+}
+
+// Arrow draws a line with a head at to, sized to the arrow's length.
+func (d *Drawer) Arrow(from, to mgl32.Vec2, color mgl32.Vec4, options ...Option) {
+	if !d.active() {
+		return
+	}
+
+	direction := to.Sub(from)
+	length := direction.Len()
+	if length == 0 {
+		return
+	}
+	direction = direction.Mul(1 / length)
+	side := mgl32.Vec2{-direction.Y(), direction.X()}
+
+	headLength := min(length*0.3, 12)
+	base := to.Sub(direction.Mul(headLength))
+	d.add(&shape{color: color, segments: []segment{
+		{from, to},
+		{to, base.Add(side.Mul(headLength * 0.5))},
+		{to, base.Sub(side.Mul(headLength * 0.5))},
+	}}, options)
+}
+
+// Cross marks a point, such as an entity origin.
+func (d *Drawer) Cross(center mgl32.Vec2, size float32, color mgl32.Vec4, options ...Option) {
+	if !d.active() {
+		return
+	}
+
+	half := size / 2
+	d.add(&shape{color: color, segments: []segment{
+		{center.Sub(mgl32.Vec2{half, 0}), center.Add(mgl32.Vec2{half, 0})},
+		{center.Sub(mgl32.Vec2{0, half}), center.Add(mgl32.Vec2{0, half})},
+	}}, options)
+}
+
+// Text writes a label with its top-left corner at position, in a built-in
+// 5x7 pixel font; see the font package.
+func (d *Drawer) Text(position mgl32.Vec2, text string, color mgl32.Vec4, options ...Option) {
+	if !d.active() || text == "" {
+		return
+	}
+
+	s := &shape{color: color}
+	d.add(s, options)
+	s.triangles = layoutText(position, text, s.scale)
+}
+
+// The package-level functions draw with the drawer made by Enable.
+
+func Line(from, to mgl32.Vec2, color mgl32.Vec4, options ...Option) {
+	drawer.Line(from, to, color, options...)
+}
+
+func Polyline(points []mgl32.Vec2, color mgl32.Vec4, options ...Option) {
+	drawer.Polyline(points, color, options...)
+}
+
+func Polygon(points []mgl32.Vec2, color mgl32.Vec4, options ...Option) {
+	drawer.Polygon(points, color, options...)
+}
+
+func Rect(minPoint, maxPoint mgl32.Vec2, color mgl32.Vec4, options ...Option) {
+	drawer.Rect(minPoint, maxPoint, color, options...)
+}
+
+func Bounds(bounds camera.Bounds, color mgl32.Vec4, options ...Option) {
+	drawer.Bounds(bounds, color, options...)
+}
+
+func Circle(center mgl32.Vec2, radius float32, color mgl32.Vec4, options ...Option) {
+	drawer.Circle(center, radius, color, options...)
+}
+
+func Arrow(from, to mgl32.Vec2, color mgl32.Vec4, options ...Option) {
+	drawer.Arrow(from, to, color, options...)
+}
+
+func Cross(center mgl32.Vec2, size float32, color mgl32.Vec4, options ...Option) {
+	drawer.Cross(center, size, color, options...)
+}
+
+func Text(position mgl32.Vec2, text string, color mgl32.Vec4, options ...Option) {
+	drawer.Text(position, text, color, options...)
+}
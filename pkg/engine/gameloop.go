@@ -2,6 +2,7 @@ package engine
 
 import (
 	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/lunararch/helios/pkg/profiler"
 )
 
 const (
@@ -12,22 +13,33 @@ const (
 type UpdateFunc func(deltaTime float32)
 type RenderFunc func(alpha float32)
 
+// Driver decides the updates of each frame in place of the clock, as replay
+// playback does.
+type Driver interface {
+	// Ticks returns the delta time of every update to run this frame, given
+	// the real seconds since the last one.
+	Ticks(frameTime float32) []float32
+}
+
 type GameLoop struct {
-	window        *glfw.Window
-	timeManager   *TimeManager
-	fixedTimestep bool
-	running       bool
-	accumulator   float64
-	updateFunc    UpdateFunc
-	renderFunc    RenderFunc
+	window         *glfw.Window
+	timeManager    *TimeManager
+	fixedTimestep  bool
+	fixedDeltaTime float64
+	running        bool
+	accumulator    float64
+	updateFunc     UpdateFunc
+	renderFunc     RenderFunc
+	driver         Driver
 }
 
 func NewGameLoop(window *glfw.Window) *GameLoop {
 	return &GameLoop{
-		window:        window,
-		timeManager:   NewTimeManager(),
-		fixedTimestep: true,
-		running:       false,
+		window:         window,
+		timeManager:    NewTimeManager(),
+		fixedTimestep:  true,
+		fixedDeltaTime: DefaultFixedDeltaTime,
+		running:        false,
 	}
 }
 
@@ -39,14 +51,49 @@ func (gl *GameLoop) SetRenderFunc(render RenderFunc) {
 	gl.renderFunc = render
 }
 
+// UseFixedTimestep switches between fixed and variable updates, taking
+// effect from the next frame even while the loop is running.
 func (gl *GameLoop) UseFixedTimestep(fixed bool) {
+	if fixed != gl.fixedTimestep {
+		gl.accumulator = 0
+	}
 	gl.fixedTimestep = fixed
 }
 
+func (gl *GameLoop) IsFixedTimestep() bool {
+	return gl.fixedTimestep
+}
+
+// SetFixedDeltaTime sets the seconds each fixed update advances the game.
+func (gl *GameLoop) SetFixedDeltaTime(seconds float64) {
+	if seconds > 0 {
+		gl.fixedDeltaTime = seconds
+	}
+}
+
+func (gl *GameLoop) GetFixedDeltaTime() float64 {
+	return gl.fixedDeltaTime
+}
+
+// SetDriver hands the timing of updates to a driver, or back to the clock
+// for nil.
+func (gl *GameLoop) SetDriver(driver Driver) {
+	gl.driver = driver
+	gl.accumulator = 0
+}
+
+func (gl *GameLoop) GetDriver() Driver {
+	return gl.driver
+}
+
 func (gl *GameLoop) SetTargetFPS(fps int) {
 	gl.timeManager.SetTargetFPS(fps)
 }
 
+func (gl *GameLoop) GetTargetFPS() int {
+	return gl.timeManager.TargetFPS()
+}
+
 func (gl *GameLoop) TimeManager() *TimeManager {
 	return gl.timeManager
 }
@@ -71,6 +118,10 @@ func (gl *GameLoop) SetTimeScale(scale float64) {
 	gl.timeManager.SetTimeScale(scale)
 }
 
+func (gl *GameLoop) GetTimeScale() float64 {
+	return gl.timeManager.TimeScale()
+}
+
 func (gl *GameLoop) Start() {
 	if gl.updateFunc == nil || gl.renderFunc == nil {
 		panic("Update and Render functions must be set before starting the game loop")
@@ -79,10 +130,31 @@ func (gl *GameLoop) Start() {
 	gl.running = true
 	gl.accumulator = 0
 
-	if gl.fixedTimestep {
-		gl.fixedTimeStepLoop()
-	} else {
-		gl.variableTimestepLoop()
+	for gl.running && !gl.window.ShouldClose() {
+		profiler.BeginFrame()
+		gl.timeManager.Update()
+
+		if gl.driver != nil {
+			gl.drivenFrame()
+		} else if gl.fixedTimestep {
+			gl.fixedTimestepFrame()
+		} else {
+			gl.variableTimestepFrame()
+		}
+
+		scope := profiler.Begin("swap")
+		gl.window.SwapBuffers()
+		scope.End()
+
+		scope = profiler.Begin("events")
+		glfw.PollEvents()
+		scope.End()
+
+		scope = profiler.Begin("sleep")
+		gl.timeManager.SleepForFrameLimit()
+		scope.End()
+
+		profiler.EndFrame()
 	}
 }
 
@@ -90,49 +162,59 @@ func (gl *GameLoop) Stop() {
 	gl.running = false
 }
 
-func (gl *GameLoop) fixedTimeStepLoop() {
-	for gl.running && !gl.window.ShouldClose() {
-		gl.timeManager.Update()
+func (gl *GameLoop) fixedTimestepFrame() {
+	deltaTime := gl.timeManager.UnscaledDeltaTime()
 
-		deltaTime := gl.timeManager.UnscaledDeltaTime()
+	if deltaTime > MaxDeltaTime {
+		deltaTime = MaxDeltaTime
+	}
 
-		if deltaTime > MaxDeltaTime {
-			deltaTime = MaxDeltaTime
-		}
+	gl.accumulator += float64(deltaTime)
 
-		gl.accumulator += float64(deltaTime)
+	// Every step advances the game by the same scaled amount, whatever the frame took
+	timeScale := gl.timeManager.TimeScale()
+	if gl.timeManager.IsPaused() {
+		timeScale = 0
+	}
+	stepTime := float32(gl.fixedDeltaTime * timeScale)
 
-		for gl.accumulator >= DefaultFixedDeltaTime {
-			gl.updateFunc(gl.timeManager.DeltaTime())
-			gl.accumulator -= DefaultFixedDeltaTime
-		}
+	for gl.accumulator >= gl.fixedDeltaTime {
+		gl.update(stepTime)
+		gl.accumulator -= gl.fixedDeltaTime
+	}
 
-		alpha := float32(gl.accumulator / DefaultFixedDeltaTime)
-		gl.renderFunc(alpha)
+	alpha := float32(gl.accumulator / gl.fixedDeltaTime)
+	gl.render(alpha)
+}
 
-		gl.window.SwapBuffers()
-		glfw.PollEvents()
+func (gl *GameLoop) variableTimestepFrame() {
+	deltaTime := gl.timeManager.DeltaTime()
 
-		gl.timeManager.SleepForFrameLimit()
+	if deltaTime > MaxDeltaTime {
+		deltaTime = MaxDeltaTime
 	}
-}
-
-func (gl *GameLoop) variableTimestepLoop() {
-	for gl.running && !gl.window.ShouldClose() {
-		gl.timeManager.Update()
 
-		deltaTime := gl.timeManager.DeltaTime()
+	gl.update(deltaTime)
+	gl.render(1.0)
+}
 
-		if deltaTime > MaxDeltaTime {
-			deltaTime = MaxDeltaTime
+func (gl *GameLoop) drivenFrame() {
+	for _, deltaTime := range gl.driver.Ticks(gl.timeManager.UnscaledDeltaTime()) {
+		gl.update(deltaTime)
+		// An update can hand timing back to the clock
+		if gl.driver == nil {
+			break
 		}
+	}
+	gl.render(1.0)
+}
 
-		gl.updateFunc(deltaTime)
-		gl.renderFunc(1.0)
-
-		gl.window.SwapBuffers()
-		glfw.PollEvents()
+func (gl *GameLoop) update(deltaTime float32) {
+	defer profiler.Begin("update").End()
+	gl.updateFunc(deltaTime)
+}
 
-		gl.timeManager.SleepForFrameLimit()
-	}
+func (gl *GameLoop) render(alpha float32) {
+	defer profiler.Begin("render").End()
+	gl.renderFunc(alpha)
 }
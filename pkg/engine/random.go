@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Random is a seeded random number generator. Game code should draw from
+// the engine's, GetRandom, rather than math/rand, so that a replay can seed
+// it the same way and get the same numbers back.
+type Random struct {
+	seed int64
+	rng  *rand.Rand
+}
+
+var random = NewRandom(time.Now().UnixNano())
+
+func NewRandom(seed int64) *Random {
+	return &Random{seed: seed, rng: rand.New(rand.NewSource(seed))}
+}
+
+// GetRandom returns the engine's generator, seeded from the clock at start.
+func GetRandom() *Random {
+	return random
+}
+
+// Seed restarts the sequence from a seed.
+func (r *Random) Seed(seed int64) {
+	r.seed = seed
+	r.rng.Seed(seed)
+}
+
+// GetSeed returns the seed the sequence was last started from.
+func (r *Random) GetSeed() int64 {
+	return r.seed
+}
+
+// Float32 returns a number in [0, 1).
+func (r *Random) Float32() float32 {
+	return r.rng.Float32()
+}
+
+// Range returns a number in [minValue, maxValue).
+func (r *Random) Range(minValue, maxValue float32) float32 {
+	return minValue + r.rng.Float32()*(maxValue-minValue)
+}
+
+// Intn returns a number in [0, n); n must be positive.
+func (r *Random) Intn(n int) int {
+	return r.rng.Intn(n)
+}
+
+// Chance returns true with the given probability, from 0 to 1.
+func (r *Random) Chance(probability float32) bool {
+	return r.rng.Float32() < probability
+}
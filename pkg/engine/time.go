@@ -168,6 +168,7 @@ func (tm *TimeManager) FrameCount() uint64         { return tm.frameCount }
 func (tm *TimeManager) FPS() float32               { return tm.fps }
 func (tm *TimeManager) TimeScale() float64         { return tm.timeScale }
 func (tm *TimeManager) IsPaused() bool             { return tm.isPaused }
+func (tm *TimeManager) TargetFPS() int             { return tm.targetFPS }
 
 func (tm *TimeManager) MinDeltaTime() float32        { return float32(tm.minDeltaTime) }
 func (tm *TimeManager) MaxDeltaTime() float32        { return float32(tm.maxDeltaTime) }
@@ -1,6 +1,9 @@
 package entity
 
 import (
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
 	"github.com/lunararch/helios/pkg/graphics/animation"
 	"github.com/lunararch/helios/pkg/graphics/texture"
 )
@@ -11,6 +14,7 @@ type AnimationComponent struct {
 	spriteComp    *SpriteComponent
 	currentFrame  *animation.Frame
 	defaultRegion *texture.TextureRegion
+	trackValues   map[string]float32
 }
 
 func NewAnimationComponent(spriteComponent *SpriteComponent) *AnimationComponent {
@@ -18,6 +22,7 @@ func NewAnimationComponent(spriteComponent *SpriteComponent) *AnimationComponent
 		BaseComponent: NewBaseComponent(ComponentTypeAnimation),
 		stateMachine:  animation.NewAnimationStateMachine(),
 		spriteComp:    spriteComponent,
+		trackValues:   make(map[string]float32),
 	}
 
 	if spriteComponent != nil && spriteComponent.GetSprite() != nil {
@@ -44,6 +49,7 @@ func (ac *AnimationComponent) Update(deltaTime float32) {
 
 	ac.stateMachine.Update(deltaTime)
 	ac.updateCurrentFrame()
+	ac.applyTracks()
 }
 
 func (ac *AnimationComponent) updateCurrentFrame() {
@@ -56,12 +62,86 @@ func (ac *AnimationComponent) updateCurrentFrame() {
 	if frame, err := ac.stateMachine.GetCurrentFrame(); err == nil {
 		ac.currentFrame = frame
 		sprite.Region = frame.TextureRegion
+		ac.spriteComp.SetOffset(frame.Offset)
 
 		if frame.TextureRegion != nil && frame.TextureRegion.Texture != nil {
 			sprite.Texture = frame.TextureRegion.Texture
 		}
 	} else {
 		sprite.Region = ac.defaultRegion
+		ac.spriteComp.SetOffset(mgl32.Vec2{0, 0})
+	}
+}
+
+// applyTracks writes the current clip's keyframed properties onto the entity.
+// Offset tracks add to the offset of the current sprite frame.
+func (ac *AnimationComponent) applyTracks() {
+	clip := ac.stateMachine.GetCurrentClip()
+	if clip == nil || !clip.HasTracks() || ac.entity == nil {
+		return
+	}
+
+	for property := range ac.trackValues {
+		delete(ac.trackValues, property)
+	}
+	clip.SampleTracks(ac.stateMachine.CurrentTime, ac.trackValues)
+
+	transform := ac.entity.GetTransform()
+	var color mgl32.Vec4
+	var offset mgl32.Vec2
+	if ac.spriteComp != nil {
+		color = ac.spriteComp.GetColor()
+		offset = ac.spriteComp.GetOffset()
+	}
+
+	for property, value := range ac.trackValues {
+		switch property {
+		case animation.PropertyPositionX:
+			transform.Position[0] = value
+		case animation.PropertyPositionY:
+			transform.Position[1] = value
+		case animation.PropertyPositionZ:
+			transform.Position[2] = value
+		case animation.PropertyRotation:
+			transform.Rotation = value
+		case animation.PropertyScaleX:
+			transform.Scale[0] = value
+		case animation.PropertyScaleY:
+			transform.Scale[1] = value
+		case animation.PropertyColorR:
+			color[0] = value
+		case animation.PropertyColorG:
+			color[1] = value
+		case animation.PropertyColorB:
+			color[2] = value
+		case animation.PropertyColorA:
+			color[3] = value
+		case animation.PropertyOffsetX:
+			offset[0] += value
+		case animation.PropertyOffsetY:
+			offset[1] += value
+		default:
+			if name, ok := strings.CutPrefix(property, animation.PropertyEnabledPrefix); ok {
+				ac.setComponentEnabled(name, value > 0.5)
+			}
+		}
+	}
+
+	if ac.spriteComp != nil {
+		ac.spriteComp.SetColor(color)
+		ac.spriteComp.SetOffset(offset)
+	}
+}
+
+func (ac *AnimationComponent) setComponentEnabled(name string, enabled bool) {
+	componentType, exists := ComponentTypeFromName(name)
+	// Disabling this component would stop the track that re-enables it
+	if !exists || componentType == ComponentTypeAnimation {
+		return
+	}
+
+	if component, ok := ac.entity.GetComponent(componentType); ok {
+		component.SetActive(enabled)
 	}
 }
 
@@ -69,6 +149,11 @@ func (ac *AnimationComponent) GetStateMachine() *animation.AnimationStateMachine
 	return ac.stateMachine
 }
 
+func (ac *AnimationComponent) SetStateMachine(stateMachine *animation.AnimationStateMachine) {
+	ac.stateMachine = stateMachine
+	ac.updateCurrentFrame()
+}
+
 func (ac *AnimationComponent) AddState(state *animation.AnimationState) {
 	ac.stateMachine.AddState(state)
 }
@@ -123,5 +208,6 @@ func (ac *AnimationComponent) Cleanup() {
 	ac.spriteComp = nil
 	ac.currentFrame = nil
 	ac.defaultRegion = nil
+	ac.trackValues = nil
 	ac.BaseComponent.Cleanup()
 }
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+)
+
+// CameraComponent makes a camera follow the entity. Add it to several
+// entities sharing a camera to keep them all in frame; how the camera moves
+// is set by its Follow and Framing settings. The scene still updates the
+// camera itself.
+type CameraComponent struct {
+	*BaseComponent
+	camera   *camera.Camera
+	offset   mgl32.Vec2
+	position mgl32.Vec2 // The camera follows this
+}
+
+func NewCameraComponent(cam *camera.Camera) *CameraComponent {
+	return &CameraComponent{
+		BaseComponent: NewBaseComponent(ComponentTypeCamera),
+		camera:        cam,
+	}
+}
+
+func (cc *CameraComponent) Initialize() error {
+	if err := cc.BaseComponent.Initialize(); err != nil {
+		return err
+	}
+
+	cc.updatePosition()
+	if cc.active {
+		cc.camera.AddTarget(&cc.position)
+	}
+	return nil
+}
+
+func (cc *CameraComponent) Update(deltaTime float32) {
+	if !cc.active {
+		return
+	}
+	cc.updatePosition()
+}
+
+func (cc *CameraComponent) updatePosition() {
+	if cc.entity == nil {
+		return
+	}
+	cc.position = cc.entity.GetWorldPosition().Vec2().Add(cc.offset)
+}
+
+// SetActive also stops or starts following the entity.
+func (cc *CameraComponent) SetActive(active bool) {
+	if active == cc.active {
+		return
+	}
+	cc.BaseComponent.SetActive(active)
+
+	if !cc.initialized {
+		return
+	}
+	if active {
+		cc.updatePosition()
+		cc.camera.AddTarget(&cc.position)
+	} else {
+		cc.camera.RemoveTarget(&cc.position)
+	}
+}
+
+func (cc *CameraComponent) Cleanup() {
+	cc.camera.RemoveTarget(&cc.position)
+	cc.BaseComponent.Cleanup()
+}
+
+func (cc *CameraComponent) GetCamera() *camera.Camera {
+	return cc.camera
+}
+
+// SetOffset moves the followed point away from the entity position, for
+// example to the middle of its sprite.
+func (cc *CameraComponent) SetOffset(offset mgl32.Vec2) {
+	cc.offset = offset
+}
+
+func (cc *CameraComponent) GetOffset() mgl32.Vec2 {
+	return cc.offset
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"slices"
+)
+
+// Checksum hashes the simulated state of every entity: its place in the
+// hierarchy, transform, components and their enabled state, and animation
+// playback. Two runs that stay in step give the same checksums, which is how
+// replays check they haven't drifted.
+func (w *World) Checksum() uint64 {
+	ids := make([]EntityID, 0, len(w.entities))
+	for id := range w.entities {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	h := fnv.New64a()
+	writeUint(h, uint64(len(ids)))
+	for _, id := range ids {
+		w.entities[id].writeState(h)
+	}
+	return h.Sum64()
+}
+
+func (e *Entity) writeState(h hash.Hash64) {
+	writeUint(h, uint64(e.ID))
+	h.Write([]byte(e.name))
+	writeBool(h, e.active)
+	writeUint(h, uint64(e.layer))
+	if e.parent != nil {
+		writeUint(h, uint64(e.parent.ID))
+	} else {
+		writeUint(h, 0)
+	}
+
+	types := make([]ComponentType, 0, len(e.components))
+	for componentType := range e.components {
+		types = append(types, componentType)
+	}
+	slices.Sort(types)
+
+	for _, componentType := range types {
+		component := e.components[componentType]
+		writeUint(h, uint64(componentType))
+		writeBool(h, component.IsActive())
+
+		switch c := component.(type) {
+		case *Transform:
+			writeFloats(h, c.Position[:]...)
+			writeFloats(h, c.Rotation)
+			writeFloats(h, c.Scale[:]...)
+		case *SpriteComponent:
+			writeBool(h, c.visible)
+			writeFloats(h, c.color[:]...)
+			writeFloats(h, c.offset[:]...)
+		case *AnimationComponent:
+			if c.stateMachine != nil {
+				h.Write([]byte(c.stateMachine.GetCurrentStateName()))
+				writeFloats(h, c.stateMachine.CurrentTime)
+				writeUint(h, uint64(c.stateMachine.LoopsPlayed))
+				writeBool(h, c.stateMachine.Playing)
+			}
+		}
+	}
+}
+
+func writeUint(h hash.Hash64, value uint64) {
+	var buffer [8]byte
+	binary.LittleEndian.PutUint64(buffer[:], value)
+	h.Write(buffer[:])
+}
+
+func writeBool(h hash.Hash64, value bool) {
+	if value {
+		h.Write([]byte{1})
+	} else {
+		h.Write([]byte{0})
+	}
+}
+
+// writeFloats hashes the exact bits, so even the smallest drift shows.
+func writeFloats(h hash.Hash64, values ...float32) {
+	for _, value := range values {
+		writeUint(h, uint64(math.Float32bits(value)))
+	}
+}
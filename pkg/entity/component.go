@@ -1,5 +1,11 @@
 package entity
 
+import (
+	"fmt"
+
+	"github.com/lunararch/helios/pkg/graphics/camera"
+)
+
 type ComponentType int
 
 const (
@@ -10,8 +16,41 @@ const (
 	ComponentTypeScript
 	ComponentTypeAnimation
 	ComponentTypeAudio
+	ComponentTypeSkeleton
+	ComponentTypeLight
+	ComponentTypeOccluder
+	ComponentTypeCamera
 )
 
+var componentTypeNames = map[string]ComponentType{
+	"transform": ComponentTypeTransform,
+	"sprite":    ComponentTypeSprite,
+	"rigidbody": ComponentTypeRigidbody,
+	"collider":  ComponentTypeCollider,
+	"script":    ComponentTypeScript,
+	"animation": ComponentTypeAnimation,
+	"audio":     ComponentTypeAudio,
+	"skeleton":  ComponentTypeSkeleton,
+	"light":     ComponentTypeLight,
+	"occluder":  ComponentTypeOccluder,
+	"camera":    ComponentTypeCamera,
+}
+
+func ComponentTypeFromName(name string) (ComponentType, bool) {
+	componentType, exists := componentTypeNames[name]
+	return componentType, exists
+}
+
+// String returns the name ComponentTypeFromName takes.
+func (t ComponentType) String() string {
+	for name, componentType := range componentTypeNames {
+		if componentType == t {
+			return name
+		}
+	}
+	return fmt.Sprintf("component(%d)", int(t))
+}
+
 type Component interface {
 	GetType() ComponentType
 	IsActive() bool
@@ -29,6 +68,14 @@ type RenderableComponent interface {
 	Render(alpha float32)
 }
 
+// CullableComponent is a renderable that knows its world-space bounds, so
+// World.RenderCamera can skip it when it is outside the camera's view.
+// Renderables without bounds are always drawn.
+type CullableComponent interface {
+	RenderableComponent
+	GetBounds() camera.Bounds
+}
+
 type BaseComponent struct {
 	componentType ComponentType
 	active        bool
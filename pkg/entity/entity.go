@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/camera"
 )
 
 type EntityID uint64
@@ -17,6 +18,8 @@ type Entity struct {
 	parent     *Entity
 	children   []*Entity
 	world      *World
+	layer      int  // Which cameras see the entity, 0 to 31; see camera.LayerMask
+	persistent bool // Kept in save games; see the save package
 	destroying bool // Add this flag to prevent circular destruction
 }
 
@@ -53,6 +56,26 @@ func (e *Entity) SetActive(active bool) {
 	e.active = active
 }
 
+// SetLayer puts the entity on a layer from 0 to 31, clamping others. Cameras
+// only draw the layers in their LayerMask. Draw order is set by the sprite's
+// layer instead.
+func (e *Entity) SetLayer(layer int) {
+	e.layer = min(max(layer, 0), camera.MaxLayer)
+}
+
+func (e *Entity) GetLayer() int {
+	return e.layer
+}
+
+// SetPersistent marks the entity to be written into save games.
+func (e *Entity) SetPersistent(persistent bool) {
+	e.persistent = persistent
+}
+
+func (e *Entity) IsPersistent() bool {
+	return e.persistent
+}
+
 func (e *Entity) GetTransform() *Transform {
 	return e.transform
 }
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/lighting"
+)
+
+// LightComponent keeps a light at the entity's position. Spot lights turn
+// with the entity, relative to the light's direction when the component was
+// created.
+type LightComponent struct {
+	*BaseComponent
+	light     *lighting.Light
+	renderer  *lighting.Renderer
+	offset    mgl32.Vec2
+	direction float32
+}
+
+func NewLightComponent(light *lighting.Light, renderer *lighting.Renderer) *LightComponent {
+	return &LightComponent{
+		BaseComponent: NewBaseComponent(ComponentTypeLight),
+		light:         light,
+		renderer:      renderer,
+		direction:     light.Direction,
+	}
+}
+
+func (lc *LightComponent) Initialize() error {
+	if err := lc.BaseComponent.Initialize(); err != nil {
+		return err
+	}
+
+	if lc.renderer != nil {
+		lc.renderer.AddLight(lc.light)
+	}
+	return nil
+}
+
+func (lc *LightComponent) Update(deltaTime float32) {
+	if !lc.active || lc.entity == nil {
+		return
+	}
+
+	lc.light.Position = lc.entity.GetWorldPosition().Vec2().Add(lc.offset)
+	lc.light.Direction = lc.direction + lc.entity.GetWorldRotation()
+}
+
+// SetActive also switches the light, since inactive components are not updated.
+func (lc *LightComponent) SetActive(active bool) {
+	lc.BaseComponent.SetActive(active)
+	lc.light.Enabled = active
+}
+
+func (lc *LightComponent) Cleanup() {
+	if lc.renderer != nil {
+		lc.renderer.RemoveLight(lc.light)
+	}
+	lc.BaseComponent.Cleanup()
+}
+
+func (lc *LightComponent) GetLight() *lighting.Light {
+	return lc.light
+}
+
+func (lc *LightComponent) SetOffset(offset mgl32.Vec2) {
+	lc.offset = offset
+}
+
+func (lc *LightComponent) GetOffset() mgl32.Vec2 {
+	return lc.offset
+}
+
+// SetDirection sets the spot direction in radians, relative to the entity.
+func (lc *LightComponent) SetDirection(direction float32) {
+	lc.direction = direction
+}
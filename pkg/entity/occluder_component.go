@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/lighting"
+)
+
+// OccluderComponent casts shadows from a polygon that moves, rotates and
+// scales with the entity.
+type OccluderComponent struct {
+	*BaseComponent
+	polygon  []mgl32.Vec2 // Relative to the entity position
+	occluder *lighting.Occluder
+	renderer *lighting.Renderer
+}
+
+func NewOccluderComponent(polygon []mgl32.Vec2, renderer *lighting.Renderer) *OccluderComponent {
+	return &OccluderComponent{
+		BaseComponent: NewBaseComponent(ComponentTypeOccluder),
+		polygon:       polygon,
+		occluder:      lighting.NewOccluder(make([]mgl32.Vec2, len(polygon))),
+		renderer:      renderer,
+	}
+}
+
+// NewBoxOccluderComponent occludes a width x height rectangle centered on
+// the entity, such as a sprite pivoted at its center.
+func NewBoxOccluderComponent(width, height float32, renderer *lighting.Renderer) *OccluderComponent {
+	return NewOccluderComponent(lighting.BoxPolygon(width, height), renderer)
+}
+
+func (oc *OccluderComponent) Initialize() error {
+	if err := oc.BaseComponent.Initialize(); err != nil {
+		return err
+	}
+
+	if oc.renderer != nil {
+		oc.renderer.AddOccluder(oc.occluder)
+	}
+	oc.updatePoints()
+	return nil
+}
+
+func (oc *OccluderComponent) Update(deltaTime float32) {
+	if !oc.active || oc.entity == nil {
+		return
+	}
+	oc.updatePoints()
+}
+
+func (oc *OccluderComponent) updatePoints() {
+	if oc.entity == nil {
+		return
+	}
+
+	position := oc.entity.GetWorldPosition()
+	scale := oc.entity.GetWorldScale()
+	transform := mgl32.Translate2D(position.X(), position.Y()).
+		Mul3(mgl32.HomogRotate2D(oc.entity.GetWorldRotation())).
+		Mul3(mgl32.Scale2D(scale.X(), scale.Y()))
+
+	for i, point := range oc.polygon {
+		oc.occluder.Points[i] = transform.Mul3x1(point.Vec3(1)).Vec2()
+	}
+}
+
+func (oc *OccluderComponent) SetActive(active bool) {
+	oc.BaseComponent.SetActive(active)
+	oc.occluder.Enabled = active
+}
+
+func (oc *OccluderComponent) Cleanup() {
+	if oc.renderer != nil {
+		oc.renderer.RemoveOccluder(oc.occluder)
+	}
+	oc.BaseComponent.Cleanup()
+}
+
+// SetPolygon replaces the shape, relative to the entity position.
+func (oc *OccluderComponent) SetPolygon(polygon []mgl32.Vec2) {
+	oc.polygon = polygon
+	oc.occluder.Points = make([]mgl32.Vec2, len(polygon))
+	oc.updatePoints()
+}
+
+func (oc *OccluderComponent) GetOccluder() *lighting.Occluder {
+	return oc.occluder
+}
+
+// GetOutline returns the occluder's polygon in world space.
+func (oc *OccluderComponent) GetOutline() []mgl32.Vec2 {
+	return oc.occluder.Points
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/lunararch/helios/pkg/graphics/skeleton"
+	"github.com/lunararch/helios/pkg/graphics/sprite"
+)
+
+// SkeletonComponent poses and draws a skeletal rig at the entity's transform.
+type SkeletonComponent struct {
+	*BaseComponent
+	skeleton    *skeleton.Skeleton
+	player      *skeleton.Player
+	visible     bool
+	spriteBatch *sprite.SpriteBatch
+}
+
+func NewSkeletonComponent(data *skeleton.SkeletonData, spriteBatch *sprite.SpriteBatch) *SkeletonComponent {
+	rig := skeleton.NewSkeleton(data)
+
+	return &SkeletonComponent{
+		BaseComponent: NewBaseComponent(ComponentTypeSkeleton),
+		skeleton:      rig,
+		player:        skeleton.NewPlayer(rig),
+		visible:       true,
+		spriteBatch:   spriteBatch,
+	}
+}
+
+func (sc *SkeletonComponent) Update(deltaTime float32) {
+	if !sc.active || sc.entity == nil {
+		return
+	}
+
+	transform := sc.entity.GetTransform()
+	sc.skeleton.Position = transform.Position.Vec2()
+	sc.skeleton.Rotation = transform.Rotation
+	sc.skeleton.Scale = transform.Scale
+
+	if sc.player.GetCurrent() != nil {
+		sc.player.Update(deltaTime)
+	} else {
+		sc.skeleton.UpdateWorldTransform()
+	}
+}
+
+func (sc *SkeletonComponent) Render(alpha float32) {
+	if !sc.active || !sc.visible || sc.spriteBatch == nil || sc.entity == nil {
+		return
+	}
+
+	skeleton.Draw(sc.spriteBatch, sc.skeleton, sc.entity.GetTransform().Position.Z())
+}
+
+// Play starts the named animation, crossfading from the current one over
+// mixDuration seconds.
+func (sc *SkeletonComponent) Play(name string, loop bool, mixDuration float32) error {
+	anim := sc.skeleton.Data.FindAnimation(name)
+	if anim == nil {
+		return fmt.Errorf("skeleton animation '%s' not found", name)
+	}
+
+	sc.player.Play(anim, loop, mixDuration)
+	return nil
+}
+
+func (sc *SkeletonComponent) GetSkeleton() *skeleton.Skeleton {
+	return sc.skeleton
+}
+
+func (sc *SkeletonComponent) GetPlayer() *skeleton.Player {
+	return sc.player
+}
+
+func (sc *SkeletonComponent) SetVisible(visible bool) {
+	sc.visible = visible
+}
+
+func (sc *SkeletonComponent) IsVisible() bool {
+	return sc.visible
+}
@@ -1,7 +1,11 @@
 package entity
 
 import (
+	"fmt"
+
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+	"github.com/lunararch/helios/pkg/graphics/material"
 	"github.com/lunararch/helios/pkg/graphics/sprite"
 	"github.com/lunararch/helios/pkg/graphics/texture"
 )
@@ -10,9 +14,21 @@ type SpriteComponent struct {
 	*BaseComponent
 	sprite      *sprite.Sprite
 	texture     *texture.Texture
+	normalMap   *texture.Texture
+	material    *material.Material
 	color       mgl32.Vec4
+	offset      mgl32.Vec2 // Drawn offset from the transform position, e.g. from animation frames
+	baseSize    mgl32.Vec2 // Unscaled size, so transform scale is not applied repeatedly
+	customSize  bool       // baseSize was set with SetSize rather than taken from the texture
+	pivot       mgl32.Vec2 // Point of the sprite, as a fraction of its size, placed at the transform position
+	origin      mgl32.Vec2 // Rotation pivot as a fraction of the size
+	flipX       bool
+	flipY       bool
+	drawMode    sprite.DrawMode
+	tileSize    mgl32.Vec2
 	visible     bool
 	layer       int
+	order       int
 	spriteBatch *sprite.SpriteBatch
 }
 
@@ -21,6 +37,7 @@ func NewSpriteComponent(tex *texture.Texture, spriteBatch *sprite.SpriteBatch) *
 		BaseComponent: NewBaseComponent(ComponentTypeSprite),
 		texture:       tex,
 		color:         mgl32.Vec4{1.0, 1.0, 1.0, 1.0},
+		origin:        mgl32.Vec2{0.5, 0.5},
 		visible:       true,
 		layer:         0,
 		spriteBatch:   spriteBatch,
@@ -34,9 +51,11 @@ func (sc *SpriteComponent) Initialize() error {
 
 	if sc.entity != nil && sc.texture != nil {
 		transform := sc.entity.GetTransform()
-		size := mgl32.Vec2{float32(sc.texture.Width), float32(sc.texture.Height)}
+		if !sc.customSize {
+			sc.baseSize = mgl32.Vec2{float32(sc.texture.Width), float32(sc.texture.Height)}
+		}
 
-		sc.sprite = sprite.NewSprite(sc.texture, transform.Position, size)
+		sc.sprite = sprite.NewSprite(sc.texture, transform.Position, sc.baseSize)
 		sc.sprite.Color = sc.color
 	}
 
@@ -49,13 +68,25 @@ func (sc *SpriteComponent) Update(deltaTime float32) {
 	}
 
 	transform := sc.entity.GetTransform()
-	sc.sprite.Position = transform.Position
-	sc.sprite.Rotation = transform.Rotation
-	sc.sprite.Size = mgl32.Vec2{
-		sc.sprite.Size.X() * transform.Scale.X(),
-		sc.sprite.Size.Y() * transform.Scale.Y(),
+	size := mgl32.Vec2{
+		sc.baseSize.X() * transform.Scale.X(),
+		sc.baseSize.Y() * transform.Scale.Y(),
 	}
+	pivot := mgl32.Vec2{sc.pivot.X() * size.X(), sc.pivot.Y() * size.Y()}
+
+	sc.sprite.Position = transform.Position.Add(sc.offset.Sub(pivot).Vec3(0))
+	sc.sprite.Rotation = transform.Rotation
+	sc.sprite.Size = size
+	sc.sprite.Origin = sc.origin
+	sc.sprite.FlipX = sc.flipX
+	sc.sprite.FlipY = sc.flipY
+	sc.sprite.Mode = sc.drawMode
+	sc.sprite.TileSize = sc.tileSize
 	sc.sprite.Color = sc.color
+	sc.sprite.Layer = sc.layer
+	sc.sprite.Order = sc.order
+	sc.sprite.NormalMap = sc.normalMap
+	sc.sprite.Material = sc.material
 }
 
 func (sc *SpriteComponent) Render(alpha float32) {
@@ -66,14 +97,108 @@ func (sc *SpriteComponent) Render(alpha float32) {
 	sc.spriteBatch.Draw(sc.sprite)
 }
 
+// GetBounds returns the world-space box the sprite covers, for culling.
+func (sc *SpriteComponent) GetBounds() camera.Bounds {
+	if sc.sprite == nil {
+		return camera.Bounds{}
+	}
+	minPoint, maxPoint := sc.sprite.GetBounds()
+	return camera.Bounds{Min: minPoint, Max: maxPoint}
+}
+
 func (sc *SpriteComponent) SetTexture(tex *texture.Texture) {
 	sc.texture = tex
+	if !sc.customSize {
+		sc.baseSize = mgl32.Vec2{float32(tex.Width), float32(tex.Height)}
+	}
 	if sc.sprite != nil {
 		sc.sprite.Texture = tex
-		sc.sprite.Size = mgl32.Vec2{float32(tex.Width), float32(tex.Height)}
+		sc.sprite.Size = sc.baseSize
 	}
 }
 
+// SetMaterial draws the sprite with a custom shader, uniforms and blend
+// mode; nil returns to the batch's default shader.
+func (sc *SpriteComponent) SetMaterial(mat *material.Material) {
+	sc.material = mat
+}
+
+func (sc *SpriteComponent) GetMaterial() *material.Material {
+	return sc.material
+}
+
+// SetNormalMap gives the sprite per-pixel lighting; the map must use the
+// same layout as the texture, including any sprite sheet frames.
+func (sc *SpriteComponent) SetNormalMap(normalMap *texture.Texture) {
+	sc.normalMap = normalMap
+}
+
+func (sc *SpriteComponent) GetNormalMap() *texture.Texture {
+	return sc.normalMap
+}
+
+// SetSize overrides the texture size, e.g. for nine-slice panels or tiled
+// sprites. The transform scale still applies on top.
+func (sc *SpriteComponent) SetSize(size mgl32.Vec2) {
+	sc.baseSize = size
+	sc.customSize = true
+}
+
+func (sc *SpriteComponent) GetSize() mgl32.Vec2 {
+	return sc.baseSize
+}
+
+// SetPivot chooses which point of the sprite, as a fraction of its size,
+// sits at the entity position; (0.5, 1) puts a character's feet there. The
+// sprite also rotates around its pivot.
+func (sc *SpriteComponent) SetPivot(pivot mgl32.Vec2) {
+	sc.pivot = pivot
+	sc.origin = pivot
+}
+
+func (sc *SpriteComponent) GetPivot() mgl32.Vec2 {
+	return sc.pivot
+}
+
+// SetOrigin sets only the rotation pivot, leaving the sprite's placement alone.
+func (sc *SpriteComponent) SetOrigin(origin mgl32.Vec2) {
+	sc.origin = origin
+}
+
+func (sc *SpriteComponent) GetOrigin() mgl32.Vec2 {
+	return sc.origin
+}
+
+func (sc *SpriteComponent) SetFlipX(flip bool) {
+	sc.flipX = flip
+}
+
+func (sc *SpriteComponent) IsFlippedX() bool {
+	return sc.flipX
+}
+
+func (sc *SpriteComponent) SetFlipY(flip bool) {
+	sc.flipY = flip
+}
+
+func (sc *SpriteComponent) IsFlippedY() bool {
+	return sc.flipY
+}
+
+func (sc *SpriteComponent) SetDrawMode(mode sprite.DrawMode) {
+	sc.drawMode = mode
+}
+
+func (sc *SpriteComponent) GetDrawMode() sprite.DrawMode {
+	return sc.drawMode
+}
+
+// SetTileSize sets the size of one tile in sprite.DrawTiled mode; zero uses
+// the region's pixel size.
+func (sc *SpriteComponent) SetTileSize(tileSize mgl32.Vec2) {
+	sc.tileSize = tileSize
+}
+
 func (sc *SpriteComponent) GetTexture() *texture.Texture {
 	return sc.texture
 }
@@ -89,6 +214,14 @@ func (sc *SpriteComponent) GetColor() mgl32.Vec4 {
 	return sc.color
 }
 
+func (sc *SpriteComponent) SetOffset(offset mgl32.Vec2) {
+	sc.offset = offset
+}
+
+func (sc *SpriteComponent) GetOffset() mgl32.Vec2 {
+	return sc.offset
+}
+
 func (sc *SpriteComponent) SetVisible(visible bool) {
 	sc.visible = visible
 }
@@ -97,6 +230,7 @@ func (sc *SpriteComponent) IsVisible() bool {
 	return sc.visible
 }
 
+// SetLayer sets the sorting layer; lower layers draw first.
 func (sc *SpriteComponent) SetLayer(layer int) {
 	sc.layer = layer
 }
@@ -105,6 +239,34 @@ func (sc *SpriteComponent) GetLayer() int {
 	return sc.layer
 }
 
+// SetSortingLayer sets the sorting layer by name, such as "background" or
+// "foreground"; see sprite.AddSortingLayer.
+func (sc *SpriteComponent) SetSortingLayer(name string) error {
+	layer, exists := sprite.SortingLayerFromName(name)
+	if !exists {
+		return fmt.Errorf("unknown sorting layer '%s'", name)
+	}
+	sc.layer = layer
+	return nil
+}
+
+// SetOrder sets the order within the sorting layer; higher draws on top.
+func (sc *SpriteComponent) SetOrder(order int) {
+	sc.order = order
+}
+
+func (sc *SpriteComponent) GetOrder() int {
+	return sc.order
+}
+
+// GetSortKey returns what World and SpriteBatch sort the sprite by.
+func (sc *SpriteComponent) GetSortKey() sprite.SortKey {
+	if sc.sprite == nil {
+		return sprite.SortKey{Layer: sc.layer, Order: sc.order}
+	}
+	return sc.sprite.GetSortKey()
+}
+
 func (sc *SpriteComponent) GetSprite() *sprite.Sprite {
 	return sc.sprite
 }
@@ -0,0 +1,145 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SaveState and LoadState let the built-in components take part in save
+// games. Only what changes while playing is kept: textures, batches and
+// state machines come from the scene that rebuilds the entity.
+
+type transformState struct {
+	Position mgl32.Vec3 `json:"position"`
+	Rotation float32    `json:"rotation"`
+	Scale    mgl32.Vec2 `json:"scale"`
+}
+
+func (t *Transform) SaveState() (json.RawMessage, error) {
+	return json.Marshal(transformState{Position: t.Position, Rotation: t.Rotation, Scale: t.Scale})
+}
+
+func (t *Transform) LoadState(data json.RawMessage) error {
+	state := transformState{Position: t.Position, Rotation: t.Rotation, Scale: t.Scale}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	t.Position, t.Rotation, t.Scale = state.Position, state.Rotation, state.Scale
+	return nil
+}
+
+type spriteState struct {
+	Color   mgl32.Vec4 `json:"color"`
+	Offset  mgl32.Vec2 `json:"offset"`
+	Visible bool       `json:"visible"`
+	Layer   int        `json:"layer"`
+	Order   int        `json:"order"`
+	FlipX   bool       `json:"flip_x"`
+	FlipY   bool       `json:"flip_y"`
+}
+
+func (sc *SpriteComponent) SaveState() (json.RawMessage, error) {
+	return json.Marshal(sc.state())
+}
+
+func (sc *SpriteComponent) LoadState(data json.RawMessage) error {
+	state := sc.state()
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	sc.SetColor(state.Color)
+	sc.SetOffset(state.Offset)
+	sc.SetVisible(state.Visible)
+	sc.SetLayer(state.Layer)
+	sc.SetOrder(state.Order)
+	sc.SetFlipX(state.FlipX)
+	sc.SetFlipY(state.FlipY)
+	return nil
+}
+
+func (sc *SpriteComponent) state() spriteState {
+	return spriteState{
+		Color:   sc.color,
+		Offset:  sc.offset,
+		Visible: sc.visible,
+		Layer:   sc.layer,
+		Order:   sc.order,
+		FlipX:   sc.IsFlippedX(),
+		FlipY:   sc.IsFlippedY(),
+	}
+}
+
+type animationState struct {
+	State      string                    `json:"state"`
+	Time       float32                   `json:"time"`
+	Loops      int                       `json:"loops,omitempty"`
+	Playing    bool                      `json:"playing"`
+	Parameters map[string]parameterState `json:"parameters,omitempty"`
+}
+
+// parameterState keeps a parameter's type, which plain JSON numbers lose.
+type parameterState struct {
+	Bool   *bool    `json:"bool,omitempty"`
+	Float  *float32 `json:"float,omitempty"`
+	Int    *int     `json:"int,omitempty"`
+	String *string  `json:"string,omitempty"`
+}
+
+func (ac *AnimationComponent) SaveState() (json.RawMessage, error) {
+	sm := ac.stateMachine
+	state := animationState{
+		State:      sm.GetCurrentStateName(),
+		Time:       sm.CurrentTime,
+		Loops:      sm.LoopsPlayed,
+		Playing:    sm.Playing,
+		Parameters: make(map[string]parameterState, len(sm.Parameters)),
+	}
+	for name, value := range sm.Parameters {
+		switch v := value.(type) {
+		case bool:
+			state.Parameters[name] = parameterState{Bool: &v}
+		case float32:
+			state.Parameters[name] = parameterState{Float: &v}
+		case int:
+			state.Parameters[name] = parameterState{Int: &v}
+		case string:
+			state.Parameters[name] = parameterState{String: &v}
+		default:
+			return nil, fmt.Errorf("animation parameter '%s' has unsupported type %T", name, value)
+		}
+	}
+	return json.Marshal(state)
+}
+
+func (ac *AnimationComponent) LoadState(data json.RawMessage) error {
+	var state animationState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	sm := ac.stateMachine
+	if state.State != "" {
+		if err := sm.SetState(state.State); err != nil {
+			return err
+		}
+	}
+	sm.CurrentTime = state.Time
+	sm.LoopsPlayed = state.Loops
+	sm.Playing = state.Playing
+	for name, parameter := range state.Parameters {
+		switch {
+		case parameter.Bool != nil:
+			sm.SetParameter(name, *parameter.Bool)
+		case parameter.Float != nil:
+			sm.SetParameter(name, *parameter.Float)
+		case parameter.Int != nil:
+			sm.SetParameter(name, *parameter.Int)
+		case parameter.String != nil:
+			sm.SetParameter(name, *parameter.String)
+		}
+	}
+	ac.updateCurrentFrame()
+	return nil
+}
@@ -2,13 +2,23 @@ package entity
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+	"github.com/lunararch/helios/pkg/graphics/sprite"
+	"github.com/lunararch/helios/pkg/profiler"
 )
 
 type World struct {
 	entities     map[EntityID]*Entity
 	nextID       EntityID
 	rootEntities []*Entity
+	stats        RenderStats
+
+	systems     map[ComponentType][]Component // Reused by Update
+	systemOrder []ComponentType
 }
 
 func NewWorld() *World {
@@ -16,6 +26,7 @@ func NewWorld() *World {
 		entities:     make(map[EntityID]*Entity),
 		nextID:       1,
 		rootEntities: make([]*Entity, 0),
+		systems:      make(map[ComponentType][]Component),
 	}
 }
 
@@ -113,6 +124,20 @@ func (w *World) GetActiveEntities() []*Entity {
 	return entities
 }
 
+// GetPersistentEntities returns the entities marked for save games, by ID.
+func (w *World) GetPersistentEntities() []*Entity {
+	var entities []*Entity
+	for _, entity := range w.entities {
+		if entity.IsPersistent() {
+			entities = append(entities, entity)
+		}
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].ID < entities[j].ID
+	})
+	return entities
+}
+
 func (w *World) GetRootEntities() []*Entity {
 	return w.rootEntities
 }
@@ -121,44 +146,178 @@ func (w *World) GetEntityCount() int {
 	return len(w.entities)
 }
 
+// Update runs the components like systems, one component type at a time in
+// type order, each in its own profiler scope. Within a type, parents update
+// before their children.
 func (w *World) Update(deltaTime float32) {
+	for componentType, components := range w.systems {
+		clear(components)
+		w.systems[componentType] = components[:0]
+	}
 	for _, entity := range w.rootEntities {
-		entity.Update(deltaTime)
+		w.collectSystems(entity)
+	}
+
+	for _, componentType := range w.systemOrder {
+		components := w.systems[componentType]
+		if len(components) == 0 {
+			continue
+		}
+
+		scope := profiler.Begin(componentType.String())
+		for _, component := range components {
+			// An earlier system may have switched it off this frame
+			if component.IsActive() {
+				component.Update(deltaTime)
+			}
+		}
+		scope.End()
+	}
+}
+
+func (w *World) collectSystems(entity *Entity) {
+	if !entity.active {
+		return
+	}
+
+	for componentType, component := range entity.components {
+		if !component.IsActive() {
+			continue
+		}
+		if _, exists := w.systems[componentType]; !exists {
+			index, _ := slices.BinarySearch(w.systemOrder, componentType)
+			w.systemOrder = slices.Insert(w.systemOrder, index, componentType)
+		}
+		w.systems[componentType] = append(w.systems[componentType], component)
+	}
+
+	for _, child := range entity.children {
+		w.collectSystems(child)
 	}
 }
 
 func (w *World) Render(alpha float32) {
-	var renderableEntities []*Entity
+	w.render(alpha, camera.AllLayers, nil)
+}
+
+// RenderLayers renders the entities on the layers in mask, usually a
+// camera's LayerMask.
+func (w *World) RenderLayers(alpha float32, mask uint32) {
+	w.render(alpha, mask, nil)
+}
+
+// RenderCamera renders what a camera can see: entities on its layers, minus
+// components whose bounds fall outside its view.
+func (w *World) RenderCamera(alpha float32, cam *camera.Camera) {
+	view := cam.GetVisibleBounds()
+	w.render(alpha, cam.LayerMask, &view)
+}
+
+// RenderStats counts the components submitted by the last render call.
+type RenderStats struct {
+	Drawn  int
+	Culled int // Outside the camera's view
+}
+
+func (w *World) GetRenderStats() RenderStats {
+	return w.stats
+}
+
+type renderItem struct {
+	entity     *Entity
+	components []RenderableComponent
+	sortKey    sprite.SortKey
+}
+
+// sortKey orders entities by their sprite; entities without one sort as
+// the default layer at their position.
+func sortKey(entity *Entity) sprite.SortKey {
+	if component, ok := entity.GetComponent(ComponentTypeSprite); ok {
+		return component.(*SpriteComponent).GetSortKey()
+	}
+	position := entity.GetWorldPosition()
+	return sprite.SortKey{Y: position.Y(), Z: position.Z()}
+}
+
+func (w *World) render(alpha float32, mask uint32, view *camera.Bounds) {
+	w.stats = RenderStats{}
+
+	// Culling first keeps off-screen entities out of the sort
+	var items []renderItem
 	for _, entity := range w.entities {
-		if entity.IsActive() {
-			for _, component := range entity.GetComponents() {
-				if _, ok := component.(RenderableComponent); ok {
-					renderableEntities = append(renderableEntities, entity)
-					break
-				}
+		if !entity.IsActive() || mask&(1<<uint(entity.GetLayer())) == 0 {
+			continue
+		}
+
+		var visible []RenderableComponent
+		for _, component := range entity.GetComponents() {
+			renderable, ok := component.(RenderableComponent)
+			if !ok || !component.IsActive() {
+				continue
+			}
+			if cullable, ok := component.(CullableComponent); ok && view != nil && !view.Intersects(cullable.GetBounds()) {
+				w.stats.Culled++
+				continue
 			}
+			visible = append(visible, renderable)
+		}
+
+		if len(visible) > 0 {
+			items = append(items, renderItem{entity: entity, components: visible, sortKey: sortKey(entity)})
+			w.stats.Drawn += len(visible)
 		}
 	}
 
-	sort.Slice(renderableEntities, func(i, j int) bool {
-		entityA := renderableEntities[i]
-		entityB := renderableEntities[j]
+	// Entities come out of a map in random order, so the ID breaks ties to
+	// keep equal sprites from swapping between frames
+	sort.Slice(items, func(i, j int) bool {
+		keyA, keyB := items[i].sortKey, items[j].sortKey
+		if keyA.Less(keyB) {
+			return true
+		}
+		if keyB.Less(keyA) {
+			return false
+		}
+		return items[i].entity.ID < items[j].entity.ID
+	})
 
-		spriteA, hasA := entityA.GetComponent(ComponentTypeSprite)
-		spriteB, hasB := entityB.GetComponent(ComponentTypeSprite)
+	// World holds every entity, children included, so each renders only its
+	// own components here
+	for _, item := range items {
+		for _, component := range item.components {
+			component.Render(alpha)
+		}
+	}
+}
 
-		if hasA && hasB {
-			layerA := spriteA.(*SpriteComponent).GetLayer()
-			layerB := spriteB.(*SpriteComponent).GetLayer()
-			return layerA < layerB
+// PickEntity returns the active entity drawn topmost at a world point, going
+// by the bounds of its cullable components, or nil. Entities without bounds
+// can't be picked.
+func (w *World) PickEntity(point mgl32.Vec2) *Entity {
+	var picked *Entity
+	var pickedKey sprite.SortKey
+
+	for _, entity := range w.entities {
+		if !entity.IsActive() || !entityContains(entity, point) {
+			continue
 		}
 
-		return false
-	})
+		// Same order as render, so the last one drawn wins
+		key := sortKey(entity)
+		if picked == nil || pickedKey.Less(key) || (!key.Less(pickedKey) && entity.ID > picked.ID) {
+			picked, pickedKey = entity, key
+		}
+	}
+	return picked
+}
 
-	for _, entity := range renderableEntities {
-		entity.Render(alpha)
+func entityContains(entity *Entity, point mgl32.Vec2) bool {
+	for _, component := range entity.GetComponents() {
+		if cullable, ok := component.(CullableComponent); ok && component.IsActive() && cullable.GetBounds().Contains(point) {
+			return true
+		}
 	}
+	return false
 }
 
 func (w *World) Clear() {
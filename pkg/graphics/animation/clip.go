@@ -2,6 +2,8 @@ package animation
 
 import (
 	"fmt"
+	"math"
+	"sort"
 
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/lunararch/helios/pkg/graphics/texture"
@@ -29,99 +31,247 @@ func NewFrameWithOffset(region *texture.TextureRegion, duration float32, offset
 	}
 }
 
+// PlaybackMode decides how playback time maps onto the clip.
+type PlaybackMode int
+
+const (
+	PlaybackOnce         PlaybackMode = iota // Play forward once and hold the last frame; defers to Loop when set
+	PlaybackLoop                             // Wrap back to the start
+	PlaybackPingPong                         // Play forward then backward; one cycle is there and back
+	PlaybackReverse                          // Play backward, wrapping to the end
+	PlaybackClampForever                     // Play forward once and hold the last frame without ever completing
+)
+
+var playbackModeNames = map[string]PlaybackMode{
+	"once":         PlaybackOnce,
+	"loop":         PlaybackLoop,
+	"pingpong":     PlaybackPingPong,
+	"reverse":      PlaybackReverse,
+	"clampforever": PlaybackClampForever,
+}
+
+func PlaybackModeFromName(name string) (PlaybackMode, bool) {
+	mode, exists := playbackModeNames[name]
+	return mode, exists
+}
+
 type AnimationClip struct {
-	Name       string
-	Frames     []*Frame
-	Loop       bool
-	TotalTime  float32
-	FrameCount int
+	Name        string
+	Frames      []*Frame
+	Tracks      []*PropertyTrack // Keyframed properties played alongside the frames
+	Loop        bool             // Kept for existing callers; SetPlaybackMode keeps it in sync
+	Mode        PlaybackMode
+	LoopCount   int     // Cycles played by loop, ping-pong and reverse clips; 0 repeats forever
+	StartOffset float32 // Seconds into the clip where playback starts
+	TotalTime   float32 // Length of the longer of the frame sequence and the tracks
+	FrameCount  int
+	frameTime   float32
+	frameEnds   []float32 // Cumulative end time of each frame, for binary search
 }
 
 func NewAnimationClip(name string, loop bool) *AnimationClip {
 	return &AnimationClip{
 		Name:       name,
 		Frames:     make([]*Frame, 0),
+		Tracks:     make([]*PropertyTrack, 0),
 		Loop:       loop,
 		TotalTime:  0,
 		FrameCount: 0,
+		frameEnds:  make([]float32, 0),
 	}
 }
 
 func (ac *AnimationClip) AddFrame(frame *Frame) {
 	ac.Frames = append(ac.Frames, frame)
-	ac.TotalTime += frame.Duration
+	ac.frameTime += frame.Duration
+	ac.frameEnds = append(ac.frameEnds, ac.frameTime)
 	ac.FrameCount++
+	ac.updateTotalTime()
 }
 
-func (ac *AnimationClip) AddFrames(frames []*Frame) {
-	for _, frame := range frames {
-		ac.AddFrame(frame)
+// SetPlaybackMode changes how the clip plays and how many cycles it runs
+// (0 for forever; ignored by once and clamp-forever).
+func (ac *AnimationClip) SetPlaybackMode(mode PlaybackMode, loopCount int) {
+	ac.Mode = mode
+	ac.LoopCount = loopCount
+	ac.Loop = mode == PlaybackLoop || mode == PlaybackPingPong || mode == PlaybackReverse
+}
+
+func (ac *AnimationClip) playbackMode() PlaybackMode {
+	if ac.Mode == PlaybackOnce && ac.Loop {
+		return PlaybackLoop
 	}
+	return ac.Mode
 }
 
-func (ac *AnimationClip) GetFrameAt(time float32) (*Frame, error) {
-	if ac.FrameCount == 0 {
-		return nil, fmt.Errorf("animation clip '%s' has no frames", ac.Name)
+// cycleLength is the playback time of one cycle.
+func (ac *AnimationClip) cycleLength() float32 {
+	if ac.playbackMode() == PlaybackPingPong {
+		return ac.TotalTime * 2
 	}
+	return ac.TotalTime
+}
 
-	if ac.Loop && time >= ac.TotalTime {
-		time = float32(int(time) % int(ac.TotalTime))
-		if time < 0 {
-			time += ac.TotalTime
+// EndTime is the playback time at which the clip reaches its final pose, or
+// +Inf when it repeats forever.
+func (ac *AnimationClip) EndTime() float32 {
+	switch ac.playbackMode() {
+	case PlaybackOnce, PlaybackClampForever:
+		return ac.TotalTime - ac.StartOffset
+	default:
+		if ac.LoopCount <= 0 {
+			return float32(math.Inf(1))
 		}
+		return ac.cycleLength()*float32(ac.LoopCount) - ac.StartOffset
 	}
+}
 
-	if time < 0 {
-		time = 0
-	} else if time >= ac.TotalTime {
-		time = ac.TotalTime - 0.001 // Just before the end
+// ClipTime maps playback time (seconds since the clip started) to a time
+// within [0, TotalTime], applying the start offset and playback mode.
+func (ac *AnimationClip) ClipTime(time float32) float32 {
+	length := ac.TotalTime
+	if length <= 0 {
+		return 0
 	}
 
-	currentTime := float32(0)
-	for _, frame := range ac.Frames {
-		if time >= currentTime && time < currentTime+frame.Duration {
-			return frame, nil
+	mode := ac.playbackMode()
+	time += ac.StartOffset
+
+	if time >= ac.EndTime()+ac.StartOffset {
+		switch mode {
+		case PlaybackPingPong, PlaybackReverse:
+			return 0
+		default:
+			return length
 		}
-		currentTime += frame.Duration
 	}
 
-	return ac.Frames[ac.FrameCount-1], nil
+	switch mode {
+	case PlaybackLoop:
+		return wrapTime(time, length)
+	case PlaybackReverse:
+		return length - wrapTime(time, length)
+	case PlaybackPingPong:
+		time = wrapTime(time, length*2)
+		if time > length {
+			return length*2 - time
+		}
+		return time
+	default:
+		return mgl32.Clamp(time, 0, length)
+	}
 }
 
-func (ac *AnimationClip) GetFrameIndex(time float32) int {
-	if ac.FrameCount == 0 {
+// Loops returns how many full cycles have been played at the given time.
+func (ac *AnimationClip) Loops(time float32) int {
+	cycle := ac.cycleLength()
+	if cycle <= 0 || time+ac.StartOffset < 0 {
 		return 0
 	}
 
-	if ac.Loop && time >= ac.TotalTime {
-		time = float32(int(time) % int(ac.TotalTime))
-		if time < 0 {
-			time += ac.TotalTime
+	loops := int((time + ac.StartOffset) / cycle)
+	if ac.LoopCount > 0 && loops > ac.LoopCount {
+		loops = ac.LoopCount
+	}
+	return loops
+}
+
+func wrapTime(time, length float32) float32 {
+	wrapped := float32(math.Mod(float64(time), float64(length)))
+	if wrapped < 0 {
+		wrapped += length
+	}
+	return wrapped
+}
+
+// AddTrack keyframes a property for the length of the clip. Adding a second
+// track for the same property replaces the first.
+func (ac *AnimationClip) AddTrack(property string, curve *Curve) {
+	for _, track := range ac.Tracks {
+		if track.Property == property {
+			track.Curve = curve
+			ac.updateTotalTime()
+			return
+		}
+	}
+
+	ac.Tracks = append(ac.Tracks, NewPropertyTrack(property, curve))
+	ac.updateTotalTime()
+}
+
+func (ac *AnimationClip) updateTotalTime() {
+	ac.TotalTime = ac.frameTime
+	for _, track := range ac.Tracks {
+		if length := track.Curve.Length(); length > ac.TotalTime {
+			ac.TotalTime = length
 		}
 	}
+}
+
+func (ac *AnimationClip) HasTracks() bool {
+	return len(ac.Tracks) > 0
+}
+
+// SampleTracks writes the value of every track at the given playback time
+// into values, keyed by property name.
+func (ac *AnimationClip) SampleTracks(time float32, values map[string]float32) {
+	time = ac.ClipTime(time)
+
+	for _, track := range ac.Tracks {
+		values[track.Property] = track.Curve.Evaluate(time)
+	}
+}
 
-	if time < 0 {
+func (ac *AnimationClip) AddFrames(frames []*Frame) {
+	for _, frame := range frames {
+		ac.AddFrame(frame)
+	}
+}
+
+func (ac *AnimationClip) GetFrameAt(time float32) (*Frame, error) {
+	if ac.FrameCount == 0 {
+		return nil, fmt.Errorf("animation clip '%s' has no frames", ac.Name)
+	}
+
+	return ac.Frames[ac.GetFrameIndex(time)], nil
+}
+
+// GetFrameIndex returns the frame shown at the given playback time.
+func (ac *AnimationClip) GetFrameIndex(time float32) int {
+	if ac.FrameCount == 0 {
 		return 0
-	} else if time >= ac.TotalTime {
-		return ac.FrameCount - 1
 	}
 
-	currentTime := float32(0)
-	for i, frame := range ac.Frames {
-		if time >= currentTime && time < currentTime+frame.Duration {
-			return i
-		}
-		currentTime += frame.Duration
+	if len(ac.frameEnds) != ac.FrameCount {
+		ac.rebuildFrameEnds()
+	}
+
+	time = ac.ClipTime(time)
+	index := sort.Search(ac.FrameCount, func(i int) bool { return ac.frameEnds[i] > time })
+	if index >= ac.FrameCount {
+		return ac.FrameCount - 1
 	}
+	return index
+}
 
-	return ac.FrameCount - 1
+// rebuildFrameEnds recovers from Frames being edited directly instead of
+// through AddFrame.
+func (ac *AnimationClip) rebuildFrameEnds() {
+	ac.frameEnds = ac.frameEnds[:0]
+	ac.frameTime = 0
+	for _, frame := range ac.Frames {
+		ac.frameTime += frame.Duration
+		ac.frameEnds = append(ac.frameEnds, ac.frameTime)
+	}
+	ac.FrameCount = len(ac.Frames)
+	ac.updateTotalTime()
 }
 
 func (ac *AnimationClip) IsComplete(time float32) bool {
-	if ac.Loop {
+	if ac.playbackMode() == PlaybackClampForever {
 		return false
 	}
-	return time >= ac.TotalTime
+	return time >= ac.EndTime()
 }
 
 func (ac *AnimationClip) Reset() float32 {
@@ -130,6 +280,9 @@ func (ac *AnimationClip) Reset() float32 {
 
 func (ac *AnimationClip) Clone(newName string) *AnimationClip {
 	clone := NewAnimationClip(newName, ac.Loop)
+	clone.Mode = ac.Mode
+	clone.LoopCount = ac.LoopCount
+	clone.StartOffset = ac.StartOffset
 	for _, frame := range ac.Frames {
 		clone.AddFrame(&Frame{
 			TextureRegion: frame.TextureRegion,
@@ -137,5 +290,10 @@ func (ac *AnimationClip) Clone(newName string) *AnimationClip {
 			Offset:        frame.Offset,
 		})
 	}
+	for _, track := range ac.Tracks {
+		curve := NewCurve()
+		curve.Keys = append(curve.Keys, track.Curve.Keys...)
+		clone.AddTrack(track.Property, curve)
+	}
 	return clone
 }
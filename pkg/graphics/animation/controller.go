@@ -0,0 +1,1175 @@
+package animation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/texture"
+)
+
+// An AnimationController is a parsed controller file. Clips are built once and
+// shared; every call to NewStateMachine produces independent playback state, so
+// one controller can drive any number of entities.
+//
+// Controller files are JSON:
+//
+//	{
+//	  "parameters": {"isWalking": "bool", "speed": "float"},
+//	  "sheets": {"hero": {"texture": "knight", "frameWidth": 32, "frameHeight": 32}},
+//	  "clips": {
+//	    "idle": {"sheet": "hero", "frames": [0, 1], "frameDuration": 0.5, "loop": true},
+//	    "run":  {"regions": ["hero_run_0", "hero_run_1"], "durations": [0.1, 0.1], "loop": true},
+//	    "wave": {"sheet": "hero", "frames": [4, 5, 6], "frameDuration": 0.1, "mode": "pingpong", "loopCount": 2, "startOffset": 0.1},
+//	    "hurt": {"tracks": {"color.a": [{"time": 0, "value": 1}, {"time": 0.1, "value": 0.2}, {"time": 0.2, "value": 1}]}}
+//	  },
+//	  "defaultState": "idle",
+//	  "states": {
+//	    "idle": {"clip": "idle", "transitions": [{"to": "run", "conditions": ["isWalking == true", "speed > 0.1"]}]},
+//	    "run":  {"clip": "run", "speed": 1.5, "transitions": [{"to": "idle", "conditions": ["!isWalking"], "exitTime": 0.2}]}
+//	  }
+//	}
+type AnimationController struct {
+	Path         string
+	Parameters   map[string]ParameterType
+	Clips        map[string]*AnimationClip
+	DefaultState string
+	states       []*stateDefinition
+}
+
+type ParameterType string
+
+const (
+	ParameterBool  ParameterType = "bool"
+	ParameterFloat ParameterType = "float"
+	ParameterInt   ParameterType = "int"
+)
+
+// ControllerResources resolves the names a controller file refers to. Sheets
+// look up Textures, atlas clips look up Regions.
+type ControllerResources struct {
+	Textures map[string]*texture.Texture
+	Regions  map[string]*texture.TextureRegion
+}
+
+type ControllerError struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (e *ControllerError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+}
+
+type ControllerErrors []*ControllerError
+
+func (e ControllerErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+type stateDefinition struct {
+	name        string
+	clip        *AnimationClip
+	speed       float32
+	transitions []*transitionDefinition
+}
+
+type transitionDefinition struct {
+	to          string
+	trigger     string
+	conditions  []*conditionDefinition
+	hasExitTime bool
+	exitTime    float32
+}
+
+type conditionDefinition struct {
+	parameter string
+	operator  string
+	value     float64 // Booleans are stored as 0 or 1
+}
+
+func LoadAnimationController(path string, resources *ControllerResources) (*AnimationController, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read animation controller: %w", err)
+	}
+	return ParseAnimationController(path, data, resources)
+}
+
+// ParseAnimationController validates the whole document and reports every
+// problem it finds, each tagged with the file and line it came from.
+func ParseAnimationController(path string, data []byte, resources *ControllerResources) (*AnimationController, error) {
+	if resources == nil {
+		resources = &ControllerResources{}
+	}
+
+	p := &controllerParser{
+		path:      path,
+		resources: resources,
+		lines:     lineOffsets(data),
+		sheets:    make(map[string]*SpriteSheet),
+		controller: &AnimationController{
+			Path:       path,
+			Parameters: make(map[string]ParameterType),
+			Clips:      make(map[string]*AnimationClip),
+		},
+	}
+
+	root, err := p.decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	p.parseRoot(root)
+
+	if len(p.errors) > 0 {
+		return nil, p.errors
+	}
+	return p.controller, nil
+}
+
+// NewStateMachine creates a state machine for one entity, sharing this
+// controller's clips.
+func (c *AnimationController) NewStateMachine() *AnimationStateMachine {
+	stateMachine := NewAnimationStateMachine()
+	c.Apply(stateMachine)
+	return stateMachine
+}
+
+// Apply adds the controller's states to an existing state machine, declares
+// its parameters with zero values and enters the default state.
+func (c *AnimationController) Apply(stateMachine *AnimationStateMachine) {
+	for name, paramType := range c.Parameters {
+		if _, exists := stateMachine.Parameters[name]; exists {
+			continue
+		}
+		switch paramType {
+		case ParameterBool:
+			stateMachine.SetParameter(name, false)
+		case ParameterFloat:
+			stateMachine.SetParameter(name, float32(0))
+		case ParameterInt:
+			stateMachine.SetParameter(name, 0)
+		}
+	}
+
+	for _, def := range c.states {
+		state := NewAnimationState(def.name, def.clip)
+		state.SetSpeed(def.speed)
+
+		for _, transition := range def.transitions {
+			condition := transition.condition()
+			switch {
+			case transition.trigger != "" && transition.hasExitTime:
+				state.AddTimedTransition(transition.trigger, transition.to, transition.exitTime, condition)
+			case transition.trigger != "":
+				state.AddTransition(transition.trigger, transition.to, condition)
+			case transition.hasExitTime:
+				state.AddTimedConditionalTransition(transition.to, transition.exitTime, condition)
+			default:
+				state.AddConditionalTransition(transition.to, condition)
+			}
+		}
+
+		stateMachine.AddState(state)
+	}
+
+	if c.DefaultState != "" {
+		stateMachine.SetState(c.DefaultState)
+	}
+}
+
+func (td *transitionDefinition) condition() TransitionCondition {
+	if len(td.conditions) == 0 {
+		return nil
+	}
+
+	conditions := td.conditions
+	return func(stateMachine *AnimationStateMachine) bool {
+		for _, condition := range conditions {
+			if !condition.evaluate(stateMachine) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func (cd *conditionDefinition) evaluate(stateMachine *AnimationStateMachine) bool {
+	raw, _ := stateMachine.GetParameter(cd.parameter)
+	value, ok := numericParameter(raw)
+	if !ok {
+		return false
+	}
+
+	switch cd.operator {
+	case "==":
+		return value == cd.value
+	case "!=":
+		return value != cd.value
+	case ">":
+		return value > cd.value
+	case ">=":
+		return value >= cd.value
+	case "<":
+		return value < cd.value
+	case "<=":
+		return value <= cd.value
+	}
+	return false
+}
+
+func numericParameter(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case nil:
+		return 0, true
+	}
+	return 0, false
+}
+
+type controllerParser struct {
+	path       string
+	resources  *ControllerResources
+	lines      []int
+	sheets     map[string]*SpriteSheet
+	controller *AnimationController
+	errors     ControllerErrors
+}
+
+func (p *controllerParser) errorf(line int, format string, args ...interface{}) {
+	p.errors = append(p.errors, &ControllerError{
+		File:    p.path,
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (p *controllerParser) parseRoot(root *jsonNode) {
+	if !p.expectKind(root, jsonObject, "controller") {
+		return
+	}
+	p.checkKeys(root, "parameters", "sheets", "clips", "states", "defaultState")
+
+	if node := root.field("parameters"); node != nil && p.expectKind(node, jsonObject, "parameters") {
+		for _, name := range node.keys {
+			p.parseParameter(name, node.fields[name])
+		}
+	}
+
+	if node := root.field("sheets"); node != nil && p.expectKind(node, jsonObject, "sheets") {
+		for _, name := range node.keys {
+			p.parseSheet(name, node.fields[name])
+		}
+	}
+
+	if node := root.field("clips"); node != nil && p.expectKind(node, jsonObject, "clips") {
+		for _, name := range node.keys {
+			p.parseClip(name, node.fields[name])
+		}
+	}
+
+	states := root.field("states")
+	if states == nil {
+		p.errorf(root.line, "controller has no states")
+		return
+	}
+	if !p.expectKind(states, jsonObject, "states") {
+		return
+	}
+
+	for _, name := range states.keys {
+		p.parseState(name, states.fields[name])
+	}
+
+	// Transition targets can only be checked once every state is known
+	for _, name := range states.keys {
+		p.checkTransitionTargets(name, states.fields[name])
+	}
+
+	if node := root.field("defaultState"); node != nil {
+		if p.expectKind(node, jsonString, "defaultState") {
+			name := node.value.(string)
+			if _, exists := states.fields[name]; !exists {
+				p.errorf(node.line, "default state '%s' is not defined", name)
+			}
+			p.controller.DefaultState = name
+		}
+	} else if len(states.keys) > 0 {
+		p.controller.DefaultState = states.keys[0]
+	}
+}
+
+func (p *controllerParser) parseParameter(name string, node *jsonNode) {
+	if !p.expectKind(node, jsonString, fmt.Sprintf("parameter '%s'", name)) {
+		return
+	}
+
+	paramType := ParameterType(node.value.(string))
+	switch paramType {
+	case ParameterBool, ParameterFloat, ParameterInt:
+		p.controller.Parameters[name] = paramType
+	default:
+		p.errorf(node.line, "parameter '%s' has unknown type '%s' (expected bool, float or int)", name, paramType)
+	}
+}
+
+func (p *controllerParser) parseSheet(name string, node *jsonNode) {
+	context := fmt.Sprintf("sheet '%s'", name)
+	if !p.expectKind(node, jsonObject, context) {
+		return
+	}
+	p.checkKeys(node, "texture", "frameWidth", "frameHeight")
+
+	textureName, okTexture := p.requiredString(node, "texture", context)
+	frameWidth, okWidth := p.requiredPositiveInt(node, "frameWidth", context)
+	frameHeight, okHeight := p.requiredPositiveInt(node, "frameHeight", context)
+
+	tex := p.resources.Textures[textureName]
+	if okTexture && tex == nil {
+		p.errorf(node.field("texture").line, "%s references unknown texture '%s'", context, textureName)
+	}
+	if tex == nil || !okWidth || !okHeight {
+		return
+	}
+
+	sheet := NewSpriteSheet(tex, int32(frameWidth), int32(frameHeight))
+	if sheet.TotalFrames == 0 {
+		p.errorf(node.line, "%s frame size %dx%d is larger than texture '%s' (%dx%d)",
+			context, frameWidth, frameHeight, textureName, tex.Width, tex.Height)
+		return
+	}
+	p.sheets[name] = sheet
+}
+
+func (p *controllerParser) parseClip(name string, node *jsonNode) {
+	context := fmt.Sprintf("clip '%s'", name)
+	if !p.expectKind(node, jsonObject, context) {
+		return
+	}
+	p.checkKeys(node, "sheet", "frames", "regions", "frameDuration", "durations", "tracks", "loop",
+		"mode", "loopCount", "startOffset")
+
+	loop := false
+	if loopNode := node.field("loop"); loopNode != nil && p.expectKind(loopNode, jsonBool, context+" loop") {
+		loop = loopNode.value.(bool)
+	}
+
+	clip := NewAnimationClip(name, loop)
+	valid := p.parsePlayback(clip, node, context)
+
+	if tracksNode := node.field("tracks"); tracksNode != nil {
+		valid = p.parseTracks(clip, tracksNode, context) && valid
+	}
+
+	var regions []*texture.TextureRegion
+	var framesNode *jsonNode
+	sheetNode, regionsNode := node.field("sheet"), node.field("regions")
+
+	switch {
+	case sheetNode != nil && regionsNode != nil:
+		p.errorf(node.line, "%s must use either 'sheet' or 'regions', not both", context)
+		return
+	case sheetNode != nil:
+		regions, framesNode = p.sheetRegions(node, context)
+	case regionsNode != nil:
+		regions, framesNode = p.atlasRegions(regionsNode, context), regionsNode
+	case node.field("tracks") != nil:
+		// Property-only clip
+		if valid {
+			p.addClip(name, clip, node, context)
+		}
+		return
+	default:
+		p.errorf(node.line, "%s needs a 'sheet' with 'frames', a list of 'regions' or 'tracks'", context)
+		return
+	}
+	if framesNode == nil {
+		return
+	}
+
+	durations := p.clipDurations(node, framesNode, context)
+	if regions == nil || durations == nil || !valid {
+		return
+	}
+
+	for i, region := range regions {
+		clip.AddFrame(NewFrame(region, durations[i]))
+	}
+	p.addClip(name, clip, node, context)
+}
+
+// addClip registers a parsed clip, checking its start offset now that the
+// frames and tracks have given it a length.
+func (p *controllerParser) addClip(name string, clip *AnimationClip, node *jsonNode, context string) {
+	if clip.StartOffset > clip.TotalTime {
+		p.errorf(node.field("startOffset").line, "%s startOffset %g is past the end of the clip (%g seconds)",
+			context, clip.StartOffset, clip.TotalTime)
+		return
+	}
+	p.controller.Clips[name] = clip
+}
+
+// parsePlayback reads the optional "mode" (once, loop, pingpong, reverse or
+// clampforever), "loopCount" and "startOffset" of a clip.
+func (p *controllerParser) parsePlayback(clip *AnimationClip, node *jsonNode, context string) bool {
+	valid := true
+
+	if modeNode := node.field("mode"); modeNode != nil {
+		if !p.expectKind(modeNode, jsonString, context+" mode") {
+			return false
+		}
+		mode, exists := PlaybackModeFromName(modeNode.value.(string))
+		if !exists {
+			p.errorf(modeNode.line, "%s has unknown mode '%s' (expected once, loop, pingpong, reverse or clampforever)",
+				context, modeNode.value.(string))
+			return false
+		}
+		if loopNode := node.field("loop"); loopNode != nil {
+			p.errorf(loopNode.line, "%s must use either 'loop' or 'mode', not both", context)
+			valid = false
+		}
+		clip.SetPlaybackMode(mode, 0)
+	}
+
+	if countNode := node.field("loopCount"); countNode != nil {
+		count, ok := p.intValue(countNode, context+" loopCount")
+		if ok && count < 0 {
+			p.errorf(countNode.line, "%s loopCount must not be negative", context)
+			ok = false
+		}
+		clip.LoopCount = count
+		valid = valid && ok
+	}
+
+	if offsetNode := node.field("startOffset"); offsetNode != nil {
+		offset, ok := p.floatValue(offsetNode, context+" startOffset")
+		if ok && offset < 0 {
+			p.errorf(offsetNode.line, "%s startOffset must not be negative", context)
+			ok = false
+		}
+		clip.StartOffset = offset
+		valid = valid && ok
+	}
+
+	return valid
+}
+
+// parseTracks reads "tracks": {"rotation": [{"time": 0, "value": 0, "interpolation": "bezier",
+// "inHandle": [-0.1, 0], "outHandle": [0.1, 0]}, ...]}.
+func (p *controllerParser) parseTracks(clip *AnimationClip, node *jsonNode, context string) bool {
+	if !p.expectKind(node, jsonObject, context+" tracks") {
+		return false
+	}
+
+	valid := true
+	for _, property := range node.keys {
+		trackContext := fmt.Sprintf("%s track '%s'", context, property)
+		keysNode := node.fields[property]
+		if !p.expectKind(keysNode, jsonArray, trackContext) {
+			valid = false
+			continue
+		}
+		if len(keysNode.items) == 0 {
+			p.errorf(keysNode.line, "%s has no keys", trackContext)
+			valid = false
+			continue
+		}
+
+		curve := NewCurve()
+		for _, keyNode := range keysNode.items {
+			key, ok := p.parseKeyframe(keyNode, trackContext)
+			if !ok {
+				valid = false
+				continue
+			}
+			curve.AddKeyframe(key)
+		}
+		clip.AddTrack(property, curve)
+	}
+	return valid
+}
+
+var interpolationNames = map[string]Interpolation{
+	"linear": InterpolationLinear,
+	"step":   InterpolationStep,
+	"bezier": InterpolationBezier,
+}
+
+func (p *controllerParser) parseKeyframe(node *jsonNode, context string) (Keyframe, bool) {
+	key := Keyframe{Interpolation: InterpolationLinear}
+	if !p.expectKind(node, jsonObject, context+" key") {
+		return key, false
+	}
+	p.checkKeys(node, "time", "value", "interpolation", "inHandle", "outHandle")
+
+	valid := true
+	for _, field := range []string{"time", "value"} {
+		fieldNode := node.field(field)
+		if fieldNode == nil {
+			p.errorf(node.line, "%s key is missing '%s'", context, field)
+			valid = false
+			continue
+		}
+		value, ok := p.floatValue(fieldNode, context+" "+field)
+		valid = valid && ok
+		if field == "time" {
+			key.Time = value
+		} else {
+			key.Value = value
+		}
+	}
+
+	if interpolationNode := node.field("interpolation"); interpolationNode != nil {
+		if p.expectKind(interpolationNode, jsonString, context+" interpolation") {
+			interpolation, exists := interpolationNames[interpolationNode.value.(string)]
+			if !exists {
+				p.errorf(interpolationNode.line, "%s has unknown interpolation '%s' (expected linear, step or bezier)",
+					context, interpolationNode.value.(string))
+				valid = false
+			}
+			key.Interpolation = interpolation
+		} else {
+			valid = false
+		}
+	}
+
+	for _, field := range []string{"inHandle", "outHandle"} {
+		handleNode := node.field(field)
+		if handleNode == nil {
+			continue
+		}
+		if !p.expectKind(handleNode, jsonArray, context+" "+field) {
+			valid = false
+			continue
+		}
+		if len(handleNode.items) != 2 {
+			p.errorf(handleNode.line, "%s %s must be [time, value]", context, field)
+			valid = false
+			continue
+		}
+		handleTime, okTime := p.floatValue(handleNode.items[0], context+" "+field)
+		handleValue, okValue := p.floatValue(handleNode.items[1], context+" "+field)
+		if !okTime || !okValue {
+			valid = false
+			continue
+		}
+		if field == "inHandle" {
+			key.InHandle = mgl32.Vec2{handleTime, handleValue}
+		} else {
+			key.OutHandle = mgl32.Vec2{handleTime, handleValue}
+		}
+	}
+
+	return key, valid
+}
+
+func (p *controllerParser) sheetRegions(node *jsonNode, context string) ([]*texture.TextureRegion, *jsonNode) {
+	sheetName, ok := p.requiredString(node, "sheet", context)
+	if !ok {
+		return nil, nil
+	}
+
+	framesNode := node.field("frames")
+	if framesNode == nil {
+		p.errorf(node.line, "%s uses sheet '%s' but has no 'frames'", context, sheetName)
+		return nil, nil
+	}
+	if !p.expectKind(framesNode, jsonArray, context+" frames") {
+		return nil, nil
+	}
+	if len(framesNode.items) == 0 {
+		p.errorf(framesNode.line, "%s has no frames", context)
+		return nil, nil
+	}
+
+	sheet, exists := p.sheets[sheetName]
+	if !exists {
+		p.errorf(node.field("sheet").line, "%s references unknown sheet '%s'", context, sheetName)
+		return nil, framesNode
+	}
+
+	regions := make([]*texture.TextureRegion, 0, len(framesNode.items))
+	valid := true
+	for _, item := range framesNode.items {
+		index, ok := p.intValue(item, context+" frame")
+		if !ok {
+			valid = false
+			continue
+		}
+
+		region, err := sheet.GetFrameRegion(int32(index))
+		if err != nil {
+			p.errorf(item.line, "%s: %v", context, err)
+			valid = false
+			continue
+		}
+		regions = append(regions, region)
+	}
+
+	if !valid {
+		return nil, framesNode
+	}
+	return regions, framesNode
+}
+
+func (p *controllerParser) atlasRegions(node *jsonNode, context string) []*texture.TextureRegion {
+	if !p.expectKind(node, jsonArray, context+" regions") {
+		return nil
+	}
+	if len(node.items) == 0 {
+		p.errorf(node.line, "%s has no regions", context)
+		return nil
+	}
+
+	regions := make([]*texture.TextureRegion, 0, len(node.items))
+	valid := true
+	for _, item := range node.items {
+		if !p.expectKind(item, jsonString, context+" region") {
+			valid = false
+			continue
+		}
+
+		name := item.value.(string)
+		region, exists := p.resources.Regions[name]
+		if !exists || region == nil {
+			p.errorf(item.line, "%s references unknown region '%s'", context, name)
+			valid = false
+			continue
+		}
+		regions = append(regions, region)
+	}
+
+	if !valid {
+		return nil
+	}
+	return regions
+}
+
+func (p *controllerParser) clipDurations(node, framesNode *jsonNode, context string) []float32 {
+	frameCount := len(framesNode.items)
+	durationNode, durationsNode := node.field("frameDuration"), node.field("durations")
+
+	switch {
+	case durationNode != nil && durationsNode != nil:
+		p.errorf(node.line, "%s must use either 'frameDuration' or 'durations', not both", context)
+	case durationNode != nil:
+		duration, ok := p.positiveFloat(durationNode, context+" frameDuration")
+		if !ok {
+			return nil
+		}
+		durations := make([]float32, frameCount)
+		for i := range durations {
+			durations[i] = duration
+		}
+		return durations
+	case durationsNode != nil:
+		if !p.expectKind(durationsNode, jsonArray, context+" durations") {
+			return nil
+		}
+		if len(durationsNode.items) != frameCount {
+			p.errorf(durationsNode.line, "%s has %d durations for %d frames", context, len(durationsNode.items), frameCount)
+			return nil
+		}
+		durations := make([]float32, 0, frameCount)
+		valid := true
+		for _, item := range durationsNode.items {
+			duration, ok := p.positiveFloat(item, context+" duration")
+			if !ok {
+				valid = false
+			}
+			durations = append(durations, duration)
+		}
+		if valid {
+			return durations
+		}
+	default:
+		p.errorf(node.line, "%s needs 'frameDuration' or 'durations'", context)
+	}
+	return nil
+}
+
+func (p *controllerParser) parseState(name string, node *jsonNode) {
+	context := fmt.Sprintf("state '%s'", name)
+	if !p.expectKind(node, jsonObject, context) {
+		return
+	}
+	p.checkKeys(node, "clip", "speed", "transitions")
+
+	def := &stateDefinition{name: name, speed: 1.0}
+
+	if clipName, ok := p.requiredString(node, "clip", context); ok {
+		clip, exists := p.controller.Clips[clipName]
+		if !exists {
+			p.errorf(node.field("clip").line, "%s references unknown clip '%s'", context, clipName)
+		}
+		def.clip = clip
+	}
+
+	if speedNode := node.field("speed"); speedNode != nil {
+		if speed, ok := p.floatValue(speedNode, context+" speed"); ok {
+			def.speed = speed
+		}
+	}
+
+	if transitionsNode := node.field("transitions"); transitionsNode != nil && p.expectKind(transitionsNode, jsonArray, context+" transitions") {
+		triggers := make(map[string]int)
+		for _, item := range transitionsNode.items {
+			transition := p.parseTransition(item, context)
+			if transition == nil {
+				continue
+			}
+			if transition.trigger != "" {
+				if line, exists := triggers[transition.trigger]; exists {
+					p.errorf(item.line, "%s already has a transition for trigger '%s' (line %d)", context, transition.trigger, line)
+					continue
+				}
+				triggers[transition.trigger] = item.line
+			}
+			def.transitions = append(def.transitions, transition)
+		}
+	}
+
+	p.controller.states = append(p.controller.states, def)
+}
+
+func (p *controllerParser) parseTransition(node *jsonNode, stateContext string) *transitionDefinition {
+	context := stateContext + " transition"
+	if !p.expectKind(node, jsonObject, context) {
+		return nil
+	}
+	p.checkKeys(node, "to", "trigger", "conditions", "exitTime")
+
+	to, ok := p.requiredString(node, "to", context)
+	if !ok {
+		return nil
+	}
+
+	transition := &transitionDefinition{to: to}
+	valid := true
+
+	if triggerNode := node.field("trigger"); triggerNode != nil {
+		if p.expectKind(triggerNode, jsonString, context+" trigger") {
+			transition.trigger = triggerNode.value.(string)
+		} else {
+			valid = false
+		}
+	}
+
+	if exitNode := node.field("exitTime"); exitNode != nil {
+		exitTime, ok := p.floatValue(exitNode, context+" exitTime")
+		if ok && exitTime < 0 {
+			p.errorf(exitNode.line, "%s exitTime must not be negative", context)
+			ok = false
+		}
+		transition.hasExitTime = true
+		transition.exitTime = exitTime
+		valid = valid && ok
+	}
+
+	if conditionsNode := node.field("conditions"); conditionsNode != nil {
+		if p.expectKind(conditionsNode, jsonArray, context+" conditions") {
+			for _, item := range conditionsNode.items {
+				condition := p.parseCondition(item, context)
+				if condition == nil {
+					valid = false
+					continue
+				}
+				transition.conditions = append(transition.conditions, condition)
+			}
+		} else {
+			valid = false
+		}
+	}
+
+	if !valid {
+		return nil
+	}
+	return transition
+}
+
+// conditionOperators lists two-character operators first, so "<=" isn't
+// read as "<" followed by "=".
+var conditionOperators = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// findOperator returns the leftmost operator in an expression, its index and
+// how many operators the expression holds in all.
+func findOperator(expression string) (int, string, int) {
+	index, found, count := -1, "", 0
+	for i := 0; i < len(expression); {
+		matched := ""
+		for _, op := range conditionOperators {
+			if strings.HasPrefix(expression[i:], op) {
+				matched = op
+				break
+			}
+		}
+		if matched == "" {
+			i++
+			continue
+		}
+		if count == 0 {
+			index, found = i, matched
+		}
+		count++
+		i += len(matched)
+	}
+	return index, found, count
+}
+
+func (p *controllerParser) parseCondition(node *jsonNode, context string) *conditionDefinition {
+	if !p.expectKind(node, jsonString, context+" condition") {
+		return nil
+	}
+
+	expression := strings.TrimSpace(node.value.(string))
+	condition := &conditionDefinition{}
+	var literal string
+
+	index, op, count := findOperator(expression)
+	if count > 1 {
+		p.errorf(node.line, "%s condition '%s' has more than one operator", context, expression)
+		return nil
+	}
+	if count == 1 {
+		condition.parameter = strings.TrimSpace(expression[:index])
+		condition.operator = op
+		literal = strings.TrimSpace(expression[index+len(op):])
+	}
+
+	// A bare "name" or "!name" is shorthand for a bool comparison
+	if condition.operator == "" {
+		condition.operator = "=="
+		literal = "true"
+		condition.parameter = expression
+		if strings.HasPrefix(expression, "!") {
+			literal = "false"
+			condition.parameter = strings.TrimSpace(expression[1:])
+		}
+	}
+
+	if condition.parameter == "" || literal == "" {
+		p.errorf(node.line, "%s condition '%s' is not of the form 'parameter op value'", context, expression)
+		return nil
+	}
+
+	paramType, declared := p.controller.Parameters[condition.parameter]
+	if !declared {
+		p.errorf(node.line, "%s condition '%s' uses undeclared parameter '%s'", context, expression, condition.parameter)
+		return nil
+	}
+
+	switch paramType {
+	case ParameterBool:
+		if condition.operator != "==" && condition.operator != "!=" {
+			p.errorf(node.line, "%s condition '%s': bool parameter '%s' only supports == and !=", context, expression, condition.parameter)
+			return nil
+		}
+		value, err := strconv.ParseBool(literal)
+		if err != nil {
+			p.errorf(node.line, "%s condition '%s': '%s' is not a bool", context, expression, literal)
+			return nil
+		}
+		if value {
+			condition.value = 1
+		}
+	case ParameterFloat, ParameterInt:
+		value, err := strconv.ParseFloat(literal, 64)
+		if err != nil {
+			p.errorf(node.line, "%s condition '%s': '%s' is not a number", context, expression, literal)
+			return nil
+		}
+		if paramType == ParameterInt && value != math.Trunc(value) {
+			p.errorf(node.line, "%s condition '%s': int parameter '%s' compared with %s", context, expression, condition.parameter, literal)
+			return nil
+		}
+		condition.value = value
+	}
+
+	return condition
+}
+
+func (p *controllerParser) checkTransitionTargets(name string, node *jsonNode) {
+	if node == nil || node.kind != jsonObject {
+		return
+	}
+	transitions := node.field("transitions")
+	if transitions == nil || transitions.kind != jsonArray {
+		return
+	}
+
+	states := p.controller.states
+	for _, item := range transitions.items {
+		to := item.field("to")
+		if to == nil || to.kind != jsonString {
+			continue
+		}
+		target := to.value.(string)
+		found := false
+		for _, state := range states {
+			if state.name == target {
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.errorf(to.line, "state '%s' transitions to unknown state '%s'", name, target)
+		}
+	}
+}
+
+func (p *controllerParser) checkKeys(node *jsonNode, allowed ...string) {
+	for _, key := range node.keys {
+		known := false
+		for _, name := range allowed {
+			if key == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			p.errorf(node.fields[key].line, "unknown field '%s'", key)
+		}
+	}
+}
+
+func (p *controllerParser) expectKind(node *jsonNode, kind jsonKind, context string) bool {
+	if node.kind != kind {
+		p.errorf(node.line, "%s must be %s, got %s", context, kind, node.kind)
+		return false
+	}
+	return true
+}
+
+func (p *controllerParser) requiredString(node *jsonNode, key, context string) (string, bool) {
+	field := node.field(key)
+	if field == nil {
+		p.errorf(node.line, "%s is missing '%s'", context, key)
+		return "", false
+	}
+	if !p.expectKind(field, jsonString, context+" "+key) {
+		return "", false
+	}
+	return field.value.(string), true
+}
+
+func (p *controllerParser) requiredPositiveInt(node *jsonNode, key, context string) (int, bool) {
+	field := node.field(key)
+	if field == nil {
+		p.errorf(node.line, "%s is missing '%s'", context, key)
+		return 0, false
+	}
+	value, ok := p.intValue(field, context+" "+key)
+	if ok && value <= 0 {
+		p.errorf(field.line, "%s %s must be positive", context, key)
+		return 0, false
+	}
+	return value, ok
+}
+
+func (p *controllerParser) intValue(node *jsonNode, context string) (int, bool) {
+	if !p.expectKind(node, jsonNumber, context) {
+		return 0, false
+	}
+	value := node.value.(float64)
+	if value != math.Trunc(value) {
+		p.errorf(node.line, "%s must be a whole number, got %v", context, value)
+		return 0, false
+	}
+	return int(value), true
+}
+
+func (p *controllerParser) floatValue(node *jsonNode, context string) (float32, bool) {
+	if !p.expectKind(node, jsonNumber, context) {
+		return 0, false
+	}
+	return float32(node.value.(float64)), true
+}
+
+func (p *controllerParser) positiveFloat(node *jsonNode, context string) (float32, bool) {
+	value, ok := p.floatValue(node, context)
+	if ok && value <= 0 {
+		p.errorf(node.line, "%s must be positive", context)
+		return 0, false
+	}
+	return value, ok
+}
+
+// The standard decoder does not report positions, so controller files are read
+// token by token into a small tree that remembers the line of every value.
+
+type jsonKind int
+
+const (
+	jsonNull jsonKind = iota
+	jsonBool
+	jsonNumber
+	jsonString
+	jsonArray
+	jsonObject
+)
+
+func (k jsonKind) String() string {
+	switch k {
+	case jsonBool:
+		return "a bool"
+	case jsonNumber:
+		return "a number"
+	case jsonString:
+		return "a string"
+	case jsonArray:
+		return "an array"
+	case jsonObject:
+		return "an object"
+	}
+	return "null"
+}
+
+type jsonNode struct {
+	kind   jsonKind
+	line   int
+	value  interface{} // bool, float64 or string for scalar kinds
+	keys   []string    // Object keys in document order
+	fields map[string]*jsonNode
+	items  []*jsonNode
+}
+
+func (n *jsonNode) field(key string) *jsonNode {
+	if n.fields == nil {
+		return nil
+	}
+	return n.fields[key]
+}
+
+func lineOffsets(data []byte) []int {
+	offsets := []int{0}
+	for i, b := range data {
+		if b == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+func (p *controllerParser) lineAt(offset int64) int {
+	return sort.Search(len(p.lines), func(i int) bool { return int64(p.lines[i]) > offset })
+}
+
+func (p *controllerParser) decode(data []byte) (*jsonNode, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	root, err := p.decodeValue(decoder)
+	if err == nil {
+		if _, err = decoder.Token(); err == io.EOF {
+			return root, nil
+		} else if err == nil {
+			err = fmt.Errorf("unexpected data after the top-level value")
+		}
+	}
+
+	line := p.lineAt(decoder.InputOffset())
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line = p.lineAt(syntaxErr.Offset - 1)
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = fmt.Errorf("unexpected end of file")
+	}
+
+	return nil, ControllerErrors{{File: p.path, Line: line, Message: err.Error()}}
+}
+
+func (p *controllerParser) decodeValue(decoder *json.Decoder) (*jsonNode, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	node := &jsonNode{line: p.lineAt(decoder.InputOffset() - 1)}
+
+	switch value := token.(type) {
+	case json.Delim:
+		switch value {
+		case '{':
+			node.kind = jsonObject
+			node.fields = make(map[string]*jsonNode)
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := keyToken.(string)
+
+				child, err := p.decodeValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				if _, exists := node.fields[key]; exists {
+					p.errorf(child.line, "duplicate field '%s'", key)
+					continue
+				}
+				node.keys = append(node.keys, key)
+				node.fields[key] = child
+			}
+		case '[':
+			node.kind = jsonArray
+			for decoder.More() {
+				child, err := p.decodeValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				node.items = append(node.items, child)
+			}
+		}
+
+		// Closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	case json.Number:
+		number, err := value.Float64()
+		if err != nil {
+			return nil, err
+		}
+		node.kind = jsonNumber
+		node.value = number
+	case string:
+		node.kind = jsonString
+		node.value = value
+	case bool:
+		node.kind = jsonBool
+		node.value = value
+	case nil:
+		node.kind = jsonNull
+	}
+
+	return node, nil
+}
@@ -0,0 +1,107 @@
+package animation
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// conditionController is a controller whose only transition has the given
+// condition, with a property-only clip so no textures are needed.
+func conditionController(condition string) string {
+	return fmt.Sprintf(`{
+	"parameters": {"speed": "float", "grounded": "bool", "jumps": "int", "a<b": "float"},
+	"clips": {"idle": {"tracks": {"rotation": [{"time": 0, "value": 0}, {"time": 1, "value": 1}]}}},
+	"states": {
+		"idle": {
+			"clip": "idle",
+			"transitions": [{"to": "idle", "conditions": [%q]}]
+		}
+	}
+}`, condition)
+}
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		expression string
+		parameter  string
+		operator   string
+		value      float64
+		err        string // Expected in the error, if any
+	}{
+		{expression: "speed > 0.5", parameter: "speed", operator: ">", value: 0.5},
+		{expression: "speed>=2", parameter: "speed", operator: ">=", value: 2},
+		{expression: "speed <= -1", parameter: "speed", operator: "<=", value: -1},
+		{expression: "jumps != 3", parameter: "jumps", operator: "!=", value: 3},
+		{expression: "jumps == 0", parameter: "jumps", operator: "==", value: 0},
+		{expression: "grounded", parameter: "grounded", operator: "==", value: 1},
+		{expression: "!grounded", parameter: "grounded", operator: "==", value: 0},
+		{expression: "grounded == false", parameter: "grounded", operator: "==", value: 0},
+		{expression: "a<b == 1", err: "more than one operator"},
+		{expression: "speed < 1 < 2", err: "more than one operator"},
+		{expression: "speed >", err: "not of the form"},
+		{expression: "> 1", err: "not of the form"},
+		{expression: "height > 1", err: "undeclared parameter 'height'"},
+		{expression: "grounded > 0", err: "only supports == and !="},
+		{expression: "grounded == maybe", err: "is not a bool"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			controller, err := ParseAnimationController("test.json", []byte(conditionController(test.expression)), nil)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("got error %v, want one containing %q", err, test.err)
+				}
+				if !strings.HasPrefix(err.Error(), "test.json:7: ") {
+					t.Errorf("error %q doesn't point at the condition's line", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			condition := controller.states[0].transitions[0].conditions[0]
+			if condition.parameter != test.parameter || condition.operator != test.operator || condition.value != test.value {
+				t.Errorf("got %s %s %g, want %s %s %g", condition.parameter, condition.operator, condition.value,
+					test.parameter, test.operator, test.value)
+			}
+		})
+	}
+}
+
+func TestStartOffsetPastTracks(t *testing.T) {
+	data := `{
+	"clips": {"idle": {"startOffset": 2, "tracks": {"rotation": [{"time": 0, "value": 0}, {"time": 1, "value": 1}]}}},
+	"states": {"idle": {"clip": "idle"}}
+}`
+	_, err := ParseAnimationController("test.json", []byte(data), nil)
+	if err == nil || !strings.Contains(err.Error(), "test.json:2: clip 'idle' startOffset 2 is past the end") {
+		t.Errorf("got error %v, want the start offset past the end of the tracks", err)
+	}
+}
+
+func TestFindOperator(t *testing.T) {
+	tests := []struct {
+		expression string
+		index      int
+		operator   string
+		count      int
+	}{
+		{"speed", -1, "", 0},
+		{"a<=b", 1, "<=", 1},
+		{"a<b", 1, "<", 1},
+		{"a==b<c", 1, "==", 2},
+		{"a<b==c", 1, "<", 2},
+		{"a!=b", 1, "!=", 1},
+	}
+
+	for _, test := range tests {
+		index, operator, count := findOperator(test.expression)
+		if index != test.index || operator != test.operator || count != test.count {
+			t.Errorf("findOperator(%q) = %d, %q, %d, want %d, %q, %d", test.expression,
+				index, operator, count, test.index, test.operator, test.count)
+		}
+	}
+}
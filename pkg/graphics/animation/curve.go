@@ -0,0 +1,196 @@
+package animation
+
+import (
+	"math"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type Interpolation int
+
+const (
+	InterpolationLinear Interpolation = iota
+	InterpolationStep
+	InterpolationBezier
+)
+
+// Property names understood by AnimationComponent when it applies tracks.
+const (
+	PropertyPositionX = "position.x"
+	PropertyPositionY = "position.y"
+	PropertyPositionZ = "position.z"
+	PropertyRotation  = "rotation"
+	PropertyScaleX    = "scale.x"
+	PropertyScaleY    = "scale.y"
+	PropertyColorR    = "color.r"
+	PropertyColorG    = "color.g"
+	PropertyColorB    = "color.b"
+	PropertyColorA    = "color.a"
+	PropertyOffsetX   = "offset.x"
+	PropertyOffsetY   = "offset.y"
+
+	// PropertyEnabledPrefix followed by a component name ("collider", "sprite",
+	// ...) toggles that component; values above 0.5 mean enabled.
+	PropertyEnabledPrefix = "enabled."
+)
+
+type Keyframe struct {
+	Time          float32
+	Value         float32
+	Interpolation Interpolation // How the curve travels from this key to the next
+	InHandle      mgl32.Vec2    // Bezier control point (time, value) relative to this key, for the incoming segment
+	OutHandle     mgl32.Vec2    // Bezier control point (time, value) relative to this key, for the outgoing segment
+}
+
+type Curve struct {
+	Keys []Keyframe
+}
+
+func NewCurve() *Curve {
+	return &Curve{
+		Keys: make([]Keyframe, 0),
+	}
+}
+
+func (c *Curve) AddKey(time, value float32, interpolation Interpolation) *Curve {
+	return c.AddKeyframe(Keyframe{
+		Time:          time,
+		Value:         value,
+		Interpolation: interpolation,
+	})
+}
+
+func (c *Curve) AddBezierKey(time, value float32, inHandle, outHandle mgl32.Vec2) *Curve {
+	return c.AddKeyframe(Keyframe{
+		Time:          time,
+		Value:         value,
+		Interpolation: InterpolationBezier,
+		InHandle:      inHandle,
+		OutHandle:     outHandle,
+	})
+}
+
+// AddKeyframe inserts a key in time order, replacing any key at the same time.
+func (c *Curve) AddKeyframe(key Keyframe) *Curve {
+	index := sort.Search(len(c.Keys), func(i int) bool { return c.Keys[i].Time >= key.Time })
+
+	if index < len(c.Keys) && c.Keys[index].Time == key.Time {
+		c.Keys[index] = key
+		return c
+	}
+
+	c.Keys = append(c.Keys, Keyframe{})
+	copy(c.Keys[index+1:], c.Keys[index:])
+	c.Keys[index] = key
+	return c
+}
+
+func (c *Curve) Length() float32 {
+	if len(c.Keys) == 0 {
+		return 0
+	}
+	return c.Keys[len(c.Keys)-1].Time
+}
+
+// Evaluate samples the curve, holding the first and last values outside the
+// keyed range.
+func (c *Curve) Evaluate(time float32) float32 {
+	count := len(c.Keys)
+	if count == 0 {
+		return 0
+	}
+	if time <= c.Keys[0].Time {
+		return c.Keys[0].Value
+	}
+	if time >= c.Keys[count-1].Time {
+		return c.Keys[count-1].Value
+	}
+
+	next := sort.Search(count, func(i int) bool { return c.Keys[i].Time > time })
+	from, to := c.Keys[next-1], c.Keys[next]
+
+	span := to.Time - from.Time
+	if span <= 0 {
+		return to.Value
+	}
+
+	switch from.Interpolation {
+	case InterpolationStep:
+		return from.Value
+	case InterpolationBezier:
+		return evaluateBezier(from, to, time)
+	default:
+		t := (time - from.Time) / span
+		return from.Value + (to.Value-from.Value)*t
+	}
+}
+
+func evaluateBezier(from, to Keyframe, time float32) float32 {
+	p0 := mgl32.Vec2{from.Time, from.Value}
+	p1 := p0.Add(from.OutHandle)
+	p3 := mgl32.Vec2{to.Time, to.Value}
+	p2 := p3.Add(to.InHandle)
+
+	// Keep the handles inside the segment so time stays monotonic
+	p1[0] = mgl32.Clamp(p1[0], p0[0], p3[0])
+	p2[0] = mgl32.Clamp(p2[0], p0[0], p3[0])
+
+	s := solveBezierParameter(p0[0], p1[0], p2[0], p3[0], time)
+	return cubicBezier(p0[1], p1[1], p2[1], p3[1], s)
+}
+
+func cubicBezier(a, b, c, d, s float32) float32 {
+	inv := 1 - s
+	return inv*inv*inv*a + 3*inv*inv*s*b + 3*inv*s*s*c + s*s*s*d
+}
+
+func cubicBezierDerivative(a, b, c, d, s float32) float32 {
+	inv := 1 - s
+	return 3*inv*inv*(b-a) + 6*inv*s*(c-b) + 3*s*s*(d-c)
+}
+
+// solveBezierParameter finds s with x(s) == x using Newton's method, falling
+// back to bisection when the slope is too flat.
+func solveBezierParameter(x0, x1, x2, x3, x float32) float32 {
+	s := (x - x0) / (x3 - x0)
+
+	for i := 0; i < 8; i++ {
+		err := cubicBezier(x0, x1, x2, x3, s) - x
+		if float32(math.Abs(float64(err))) < 1e-5 {
+			return s
+		}
+		slope := cubicBezierDerivative(x0, x1, x2, x3, s)
+		if float32(math.Abs(float64(slope))) < 1e-6 {
+			break
+		}
+		s -= err / slope
+		if s < 0 || s > 1 {
+			break
+		}
+	}
+
+	low, high := float32(0), float32(1)
+	s = (low + high) / 2
+	for i := 0; i < 32; i++ {
+		if cubicBezier(x0, x1, x2, x3, s) < x {
+			low = s
+		} else {
+			high = s
+		}
+		s = (low + high) / 2
+	}
+	return s
+}
+
+type PropertyTrack struct {
+	Property string
+	Curve    *Curve
+}
+
+func NewPropertyTrack(property string, curve *Curve) *PropertyTrack {
+	return &PropertyTrack{
+		Property: property,
+		Curve:    curve,
+	}
+}
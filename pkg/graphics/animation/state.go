@@ -2,21 +2,25 @@ package animation
 
 import (
 	"fmt"
+	"math"
+	"slices"
 )
 
 type AnimationState struct {
-	Name     string
-	Clip     *AnimationClip
-	Speed    float32
-	Triggers map[string]*AnimationTransition
+	Name        string
+	Clip        *AnimationClip
+	Speed       float32
+	Triggers    map[string]*AnimationTransition
+	Transitions []*AnimationTransition // Evaluated every update, in order, without a trigger
 }
 
 func NewAnimationState(name string, clip *AnimationClip) *AnimationState {
 	return &AnimationState{
-		Name:     name,
-		Clip:     clip,
-		Speed:    1.0,
-		Triggers: make(map[string]*AnimationTransition),
+		Name:        name,
+		Clip:        clip,
+		Speed:       1.0,
+		Triggers:    make(map[string]*AnimationTransition),
+		Transitions: make([]*AnimationTransition, 0),
 	}
 }
 
@@ -44,6 +48,26 @@ func (as *AnimationState) AddTimedTransition(triggerName string, targetState str
 	as.Triggers[triggerName] = transition
 }
 
+func (as *AnimationState) AddConditionalTransition(targetState string, condition TransitionCondition) {
+	as.Transitions = append(as.Transitions, &AnimationTransition{
+		FromState:   as.Name,
+		ToState:     targetState,
+		Condition:   condition,
+		HasExitTime: false,
+		ExitTime:    0,
+	})
+}
+
+func (as *AnimationState) AddTimedConditionalTransition(targetState string, exitTime float32, condition TransitionCondition) {
+	as.Transitions = append(as.Transitions, &AnimationTransition{
+		FromState:   as.Name,
+		ToState:     targetState,
+		Condition:   condition,
+		HasExitTime: true,
+		ExitTime:    exitTime,
+	})
+}
+
 func (as *AnimationState) SetSpeed(speed float32) {
 	as.Speed = speed
 }
@@ -75,6 +99,7 @@ type AnimationStateMachine struct {
 	States       map[string]*AnimationState
 	CurrentState *AnimationState
 	CurrentTime  float32
+	LoopsPlayed  int // Cycles wrapped out of CurrentTime by clips that loop forever
 	Triggers     map[string]bool
 	Parameters   map[string]interface{}
 	Playing      bool
@@ -105,6 +130,7 @@ func (asm *AnimationStateMachine) SetState(stateName string) error {
 
 	asm.CurrentState = state
 	asm.CurrentTime = 0
+	asm.LoopsPlayed = 0
 	return nil
 }
 
@@ -159,30 +185,78 @@ func (asm *AnimationStateMachine) Update(deltaTime float32) {
 
 	asm.CurrentTime += deltaTime * asm.CurrentState.Speed
 
+	// Triggers are tried in name order, so when several are set at once the
+	// same one wins on every run
+	triggerNames := make([]string, 0, len(asm.Triggers))
 	for triggerName, isSet := range asm.Triggers {
-		if !isSet {
-			continue
+		if isSet {
+			triggerNames = append(triggerNames, triggerName)
 		}
+	}
+	slices.Sort(triggerNames)
 
+	transitioned := false
+	for _, triggerName := range triggerNames {
 		if transition, exists := asm.CurrentState.Triggers[triggerName]; exists {
-			if transition.CanTransition(asm, asm.CurrentTime) {
+			if transition.CanTransition(asm, asm.GetStateTime()) {
 				if targetState, exists := asm.States[transition.ToState]; exists {
 					asm.CurrentState = targetState
 					asm.CurrentTime = 0
+					asm.LoopsPlayed = 0
 					asm.ResetTrigger(triggerName) // Reset trigger after use
+					transitioned = true
+					break
+				}
+			}
+		}
+	}
+
+	if !transitioned {
+		for _, transition := range asm.CurrentState.Transitions {
+			if transition.CanTransition(asm, asm.GetStateTime()) {
+				if targetState, exists := asm.States[transition.ToState]; exists {
+					asm.CurrentState = targetState
+					asm.CurrentTime = 0
+					asm.LoopsPlayed = 0
 					break
 				}
 			}
 		}
 	}
 
-	if asm.CurrentState.Clip != nil && !asm.CurrentState.Clip.Loop {
-		if asm.CurrentTime >= asm.CurrentState.Clip.TotalTime {
-			asm.CurrentTime = asm.CurrentState.Clip.TotalTime
+	// Hold finished clips at their end and wrap the ones that loop forever,
+	// so time doesn't grow without bound and lose precision
+	if clip := asm.CurrentState.Clip; clip != nil {
+		if endTime := clip.EndTime(); asm.CurrentTime >= endTime {
+			asm.CurrentTime = endTime
+		} else if cycle := clip.cycleLength(); math.IsInf(float64(endTime), 1) && cycle > 0 {
+			// Whole cycles of playback time, which keeps CurrentTime in [0, cycle);
+			// ClipTime still adds the offset and wraps it
+			if cycles := int(asm.CurrentTime / cycle); cycles > 0 {
+				asm.CurrentTime -= float32(cycles) * cycle
+				asm.LoopsPlayed += cycles
+			}
 		}
 	}
 }
 
+// GetStateTime returns the seconds spent in the current state, including the
+// cycles wrapped out of CurrentTime.
+func (asm *AnimationStateMachine) GetStateTime() float32 {
+	if asm.LoopsPlayed == 0 || asm.CurrentState == nil || asm.CurrentState.Clip == nil {
+		return asm.CurrentTime
+	}
+	return float32(asm.LoopsPlayed)*asm.CurrentState.Clip.cycleLength() + asm.CurrentTime
+}
+
+// GetLoops returns how many full cycles the current clip has played.
+func (asm *AnimationStateMachine) GetLoops() int {
+	if asm.CurrentState == nil || asm.CurrentState.Clip == nil {
+		return 0
+	}
+	return asm.LoopsPlayed + asm.CurrentState.Clip.Loops(asm.CurrentTime)
+}
+
 func (asm *AnimationStateMachine) GetCurrentFrame() (*Frame, error) {
 	if asm.CurrentState == nil || asm.CurrentState.Clip == nil {
 		return nil, fmt.Errorf("no current state or clip")
@@ -220,4 +294,5 @@ func (asm *AnimationStateMachine) Pause() {
 func (asm *AnimationStateMachine) Stop() {
 	asm.Playing = false
 	asm.CurrentTime = 0
+	asm.LoopsPlayed = 0
 }
@@ -0,0 +1,40 @@
+package animation
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLoopingClipTimeWraps(t *testing.T) {
+	for _, offset := range []float32{0, 0.25, 0.9} {
+		clip := NewAnimationClip("spin", true)
+		clip.AddFrame(NewFrame(nil, 0.5))
+		clip.AddFrame(NewFrame(nil, 0.5))
+		clip.StartOffset = offset
+
+		stateMachine := NewAnimationStateMachine()
+		stateMachine.AddState(NewAnimationState("spin", clip))
+		if err := stateMachine.SetState("spin"); err != nil {
+			t.Fatal(err)
+		}
+		stateMachine.Play()
+
+		var elapsed float32
+		for range 200 {
+			stateMachine.Update(0.07)
+			elapsed += 0.07
+
+			if stateMachine.CurrentTime < 0 || stateMachine.CurrentTime >= clip.cycleLength() {
+				t.Fatalf("offset %g: CurrentTime %g is outside [0, %g)", offset, stateMachine.CurrentTime, clip.cycleLength())
+			}
+			if got := stateMachine.GetStateTime(); math.Abs(float64(got-elapsed)) > 1e-3 {
+				t.Fatalf("offset %g: state time %g, want %g", offset, got, elapsed)
+			}
+			// Either side of a cycle boundary, as the sums round differently
+			loops := stateMachine.GetLoops()
+			if loops != int(elapsed+offset+1e-4) && loops != int(elapsed+offset-1e-4) {
+				t.Fatalf("offset %g: %d loops at %g, want %d", offset, loops, elapsed, int(elapsed+offset))
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package camera
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Bounds is an axis-aligned box in world space.
+type Bounds struct {
+	Min mgl32.Vec2
+	Max mgl32.Vec2
+}
+
+// BoundsOf returns the smallest box holding every point.
+func BoundsOf(points ...mgl32.Vec2) Bounds {
+	if len(points) == 0 {
+		return Bounds{}
+	}
+
+	bounds := Bounds{Min: points[0], Max: points[0]}
+	for _, point := range points[1:] {
+		bounds.Min = mgl32.Vec2{min(bounds.Min.X(), point.X()), min(bounds.Min.Y(), point.Y())}
+		bounds.Max = mgl32.Vec2{max(bounds.Max.X(), point.X()), max(bounds.Max.Y(), point.Y())}
+	}
+	return bounds
+}
+
+func (b Bounds) Intersects(other Bounds) bool {
+	return b.Min.X() <= other.Max.X() && b.Max.X() >= other.Min.X() &&
+		b.Min.Y() <= other.Max.Y() && b.Max.Y() >= other.Min.Y()
+}
+
+func (b Bounds) Contains(point mgl32.Vec2) bool {
+	return point.X() >= b.Min.X() && point.X() <= b.Max.X() &&
+		point.Y() >= b.Min.Y() && point.Y() <= b.Max.Y()
+}
+
+// Expand grows the box by margin on every side.
+func (b Bounds) Expand(margin float32) Bounds {
+	offset := mgl32.Vec2{margin, margin}
+	return Bounds{Min: b.Min.Sub(offset), Max: b.Max.Add(offset)}
+}
+
+// GetVisibleBounds returns the world area the camera shows. With rotation
+// this is the box around the rotated view, so it is slightly generous at
+// the corners.
+func (c *Camera) GetVisibleBounds() Bounds {
+	inverse := c.GetViewMatrix().Inv()
+
+	corners := [4]mgl32.Vec2{{0, 0}, {c.Size.X(), 0}, {c.Size.X(), c.Size.Y()}, {0, c.Size.Y()}}
+	for i, corner := range corners {
+		world := inverse.Mul4x1(mgl32.Vec4{corner.X(), corner.Y(), 0, 1})
+		corners[i] = mgl32.Vec2{world.X(), world.Y()}
+	}
+	return BoundsOf(corners[:]...)
+}
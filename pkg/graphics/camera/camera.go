@@ -3,7 +3,6 @@ package camera
 import "C"
 import (
 	"github.com/go-gl/mathgl/mgl32"
-	"math"
 )
 
 type Camera struct {
@@ -14,7 +13,24 @@ type Camera struct {
 	MinBounds     mgl32.Vec2
 	MaxBounds     mgl32.Vec2
 	BoundsEnabled bool
-	target        *mgl32.Vec2
+	Follow        FollowSettings
+	Framing       FramingSettings
+	Shake         ShakeSettings
+
+	// How the camera draws when a scene renders through several; see Begin
+	Enabled     bool
+	Rect        mgl32.Vec4 // X, Y, width, height as fractions of the output, origin top-left
+	Order       int        // Lower orders draw first, so higher ones draw on top
+	LayerMask   uint32     // Entity layers the camera sees
+	Clear       ClearFlags
+	Background  mgl32.Vec4 // Color used by ClearColorBuffer
+	ScreenSpace bool       // Ignore position, zoom, rotation and shake, for UI drawn in camera pixels
+
+	targets  []*mgl32.Vec2
+	viewport *Viewport
+	follow   followState
+	shake    shakeState
+	output   [4]int32 // GL viewport saved by Begin
 }
 
 func New(width, height float32) *Camera {
@@ -24,18 +40,28 @@ func New(width, height float32) *Camera {
 		Zoom:          1.0,
 		Rotation:      0.0,
 		BoundsEnabled: false,
+		Follow:        DefaultFollowSettings(),
+		Framing:       DefaultFramingSettings(),
+		Shake:         DefaultShakeSettings(),
+		Enabled:       true,
+		Rect:          mgl32.Vec4{0, 0, 1, 1},
+		LayerMask:     AllLayers,
 	}
 }
 
 func (c *Camera) GetViewMatrix() mgl32.Mat4 {
 	view := mgl32.Ident4()
+	if c.ScreenSpace {
+		return view
+	}
 
-	view = view.Mul4(mgl32.Translate3D(c.Size[0]/2.0, c.Size[1]/2.0, 0))
+	// Shake moves the picture in screen space, so it looks the same at any zoom
+	view = view.Mul4(mgl32.Translate3D(c.Size[0]/2.0+c.shake.offset[0], c.Size[1]/2.0+c.shake.offset[1], 0))
 
 	view = view.Mul4(mgl32.Scale3D(c.Zoom, c.Zoom, 1.0))
 
-	if c.Rotation != 0 {
-		view = view.Mul4(mgl32.HomogRotate3DZ(c.Rotation))
+	if rotation := c.Rotation + c.shake.angle; rotation != 0 {
+		view = view.Mul4(mgl32.HomogRotate3DZ(rotation))
 	}
 
 	view = view.Mul4(mgl32.Translate3D(-c.Position[0], -c.Position[1], 0))
@@ -87,59 +113,91 @@ func (c *Camera) ClampToBounds() {
 	}
 }
 
+// SetTarget follows a single position, replacing any other targets.
 func (c *Camera) SetTarget(position *mgl32.Vec2) {
-	c.target = position
+	c.targets = c.targets[:0]
+	c.AddTarget(position)
+}
+
+// AddTarget adds a position to follow. With several targets the camera
+// follows the middle of their bounds and, with Framing enabled, zooms to
+// keep them all in view.
+func (c *Camera) AddTarget(position *mgl32.Vec2) {
+	if position == nil {
+		return
+	}
+	c.targets = append(c.targets, position)
+	c.follow.reset()
+}
+
+func (c *Camera) RemoveTarget(position *mgl32.Vec2) {
+	for i, target := range c.targets {
+		if target == position {
+			c.targets = append(c.targets[:i], c.targets[i+1:]...)
+			c.follow.reset()
+			return
+		}
+	}
 }
 
 func (c *Camera) ClearTarget() {
-	c.target = nil
+	c.targets = c.targets[:0]
+	c.follow.reset()
+}
+
+func (c *Camera) GetTargets() []*mgl32.Vec2 {
+	return c.targets
 }
 
 func (c *Camera) Update(deltaTime float32) {
-	if c.target != nil {
-		c.Position = *c.target
-		c.ClampToBounds()
+	if len(c.targets) > 0 {
+		c.updateFollow(deltaTime)
+		c.updateFraming(deltaTime)
 	}
+	c.updateShake(deltaTime)
+	c.ClampToBounds()
 }
 
-func (c *Camera) ScreenToWorld(screenPos mgl32.Vec2) mgl32.Vec2 {
-	worldX := screenPos[0]/c.Zoom + c.Position[0] - c.Size[0]/(2.0*c.Zoom)
-	worldY := screenPos[1]/c.Zoom + c.Position[1] - c.Size[1]/(2.0*c.Zoom)
+// GetProjectionMatrix is the y-down orthographic projection covering the
+// camera size; scenes set it each frame so resizes take effect.
+func (c *Camera) GetProjectionMatrix() mgl32.Mat4 {
+	return mgl32.Ortho(0, c.Size[0], c.Size[1], 0, -1, 1)
+}
 
-	if c.Rotation != 0 {
-		x := worldX - c.Position[0]
-		y := worldY - c.Position[1]
+// GetViewport returns the viewport driving this camera, or nil.
+func (c *Camera) GetViewport() *Viewport {
+	return c.viewport
+}
 
-		cos := float32(math.Cos(float64(c.Rotation)))
-		sin := float32(math.Sin(float64(c.Rotation)))
-		rotatedX := x*cos - y*sin
-		rotatedY := x*sin + y*cos
+// ScreenToCamera converts a cursor position to camera screen coordinates,
+// (0, 0) to Size. The result is false outside the camera's part of the
+// screen, which tells split-screen views apart.
+func (c *Camera) ScreenToCamera(screenPos mgl32.Vec2) (mgl32.Vec2, bool) {
+	if c.viewport == nil {
+		inside := screenPos.X() >= 0 && screenPos.Y() >= 0 && screenPos.X() <= c.Size.X() && screenPos.Y() <= c.Size.Y()
+		return screenPos, inside
+	}
+	return c.viewport.screenToCamera(c, screenPos)
+}
 
-		worldX = rotatedX + c.Position[0]
-		worldY = rotatedY + c.Position[1]
+// ScreenToWorld converts a cursor position to world coordinates. With a
+// viewport attached, letterbox offsets and high-DPI scaling are accounted for.
+func (c *Camera) ScreenToWorld(screenPos mgl32.Vec2) mgl32.Vec2 {
+	if c.viewport != nil {
+		screenPos, _ = c.viewport.screenToCamera(c, screenPos)
 	}
 
-	return mgl32.Vec2{worldX, worldY}
+	world := c.GetViewMatrix().Inv().Mul4x1(mgl32.Vec4{screenPos[0], screenPos[1], 0, 1})
+	return mgl32.Vec2{world[0], world[1]}
 }
 
+// WorldToScreen is the inverse of ScreenToWorld.
 func (c *Camera) WorldToScreen(worldPos mgl32.Vec2) mgl32.Vec2 {
-	var screenX, screenY float32
-
-	if c.Rotation != 0 {
-		x := worldPos[0] - c.Position[0]
-		y := worldPos[1] - c.Position[1]
-
-		cos := float32(math.Cos(float64(-c.Rotation)))
-		sin := float32(math.Sin(float64(-c.Rotation)))
-		rotatedX := x*cos - y*sin
-		rotatedY := x*sin + y*cos
-
-		screenX = (rotatedX + c.Size[0]/(2.0*c.Zoom)) * c.Zoom
-		screenY = (rotatedY + c.Size[1]/(2.0*c.Zoom)) * c.Zoom
-	} else {
-		screenX = (worldPos[0] - c.Position[0] + c.Size[0]/(2.0*c.Zoom)) * c.Zoom
-		screenY = (worldPos[1] - c.Position[1] + c.Size[1]/(2.0*c.Zoom)) * c.Zoom
-	}
+	screen := c.GetViewMatrix().Mul4x1(mgl32.Vec4{worldPos[0], worldPos[1], 0, 1})
+	screenPos := mgl32.Vec2{screen[0], screen[1]}
 
-	return mgl32.Vec2{screenX, screenY}
+	if c.viewport != nil {
+		screenPos = c.viewport.cameraToScreen(c, screenPos)
+	}
+	return screenPos
 }
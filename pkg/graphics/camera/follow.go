@@ -0,0 +1,175 @@
+package camera
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// FollowMode decides how the camera catches up with its targets.
+type FollowMode int
+
+const (
+	FollowSnap       FollowMode = iota // Jump to the target every frame
+	FollowLerp                         // Close a fixed fraction of the distance per second
+	FollowSmoothDamp                   // Spring-like easing that accelerates and settles smoothly
+)
+
+type FollowSettings struct {
+	Mode           FollowMode
+	Speed          float32    // FollowLerp rate; higher catches up faster
+	SmoothTime     float32    // FollowSmoothDamp: roughly the seconds taken to reach the target
+	MaxSpeed       float32    // FollowSmoothDamp speed limit in world units per second; 0 is unlimited
+	DeadZone       mgl32.Vec2 // Screen pixels the target can move around the view center without moving the camera
+	LookAhead      float32    // Seconds of target movement to lead by
+	LookAheadSpeed float32    // Rate the look-ahead settles, so it doesn't jitter with the target
+	Offset         mgl32.Vec2 // Added to the target position
+}
+
+func DefaultFollowSettings() FollowSettings {
+	return FollowSettings{
+		Mode:           FollowSnap,
+		Speed:          5.0,
+		SmoothTime:     0.3,
+		LookAheadSpeed: 3.0,
+	}
+}
+
+// FramingSettings zooms the camera to fit every target when following
+// more than one.
+type FramingSettings struct {
+	Enabled   bool
+	Padding   float32 // World units kept around the targets' bounds
+	MinZoom   float32
+	MaxZoom   float32
+	ZoomSpeed float32 // Lerp rate towards the fitting zoom
+}
+
+func DefaultFramingSettings() FramingSettings {
+	return FramingSettings{
+		Padding:   64,
+		MinZoom:   0.25,
+		MaxZoom:   2.0,
+		ZoomSpeed: 3.0,
+	}
+}
+
+type followState struct {
+	velocity       mgl32.Vec2 // FollowSmoothDamp velocity
+	lookAhead      mgl32.Vec2
+	previousCenter mgl32.Vec2
+	tracking       bool // previousCenter is valid
+}
+
+func (f *followState) reset() {
+	f.lookAhead = mgl32.Vec2{}
+	f.tracking = false
+}
+
+// targetBounds returns the box around every target.
+func (c *Camera) targetBounds() (minPoint, maxPoint mgl32.Vec2) {
+	minPoint, maxPoint = *c.targets[0], *c.targets[0]
+	for _, target := range c.targets[1:] {
+		minPoint = mgl32.Vec2{min(minPoint.X(), target.X()), min(minPoint.Y(), target.Y())}
+		maxPoint = mgl32.Vec2{max(maxPoint.X(), target.X()), max(maxPoint.Y(), target.Y())}
+	}
+	return minPoint, maxPoint
+}
+
+func (c *Camera) updateFollow(deltaTime float32) {
+	minPoint, maxPoint := c.targetBounds()
+	center := minPoint.Add(maxPoint).Mul(0.5)
+
+	if c.Follow.LookAhead > 0 && deltaTime > 0 {
+		var velocity mgl32.Vec2
+		if c.follow.tracking {
+			velocity = center.Sub(c.follow.previousCenter).Mul(1 / deltaTime)
+		}
+		desired := velocity.Mul(c.Follow.LookAhead)
+		c.follow.lookAhead = c.follow.lookAhead.Add(desired.Sub(c.follow.lookAhead).Mul(damp(c.Follow.LookAheadSpeed, deltaTime)))
+	} else {
+		c.follow.lookAhead = mgl32.Vec2{}
+	}
+	c.follow.previousCenter = center
+	c.follow.tracking = true
+
+	goal := c.applyDeadZone(center.Add(c.follow.lookAhead).Add(c.Follow.Offset))
+
+	switch c.Follow.Mode {
+	case FollowLerp:
+		c.Position = c.Position.Add(goal.Sub(c.Position).Mul(damp(c.Follow.Speed, deltaTime)))
+	case FollowSmoothDamp:
+		c.Position = c.smoothDamp(goal, deltaTime)
+	default:
+		c.Position = goal
+	}
+}
+
+// applyDeadZone returns where the camera has to be for goal to sit on the
+// edge of the dead zone, or the current position while goal is inside it.
+func (c *Camera) applyDeadZone(goal mgl32.Vec2) mgl32.Vec2 {
+	halfZone := c.Follow.DeadZone.Mul(0.5 / c.Zoom)
+	result := c.Position
+
+	for axis := 0; axis < 2; axis++ {
+		difference := goal[axis] - c.Position[axis]
+		if difference > halfZone[axis] {
+			result[axis] = goal[axis] - halfZone[axis]
+		} else if difference < -halfZone[axis] {
+			result[axis] = goal[axis] + halfZone[axis]
+		}
+	}
+	return result
+}
+
+// smoothDamp is a critically damped spring towards goal, as popularised by
+// Game Programming Gems 4, chapter 1.10.
+func (c *Camera) smoothDamp(goal mgl32.Vec2, deltaTime float32) mgl32.Vec2 {
+	smoothTime := max(c.Follow.SmoothTime, 0.0001)
+	omega := 2 / smoothTime
+	x := omega * deltaTime
+	decay := 1 / (1 + x + 0.48*x*x + 0.235*x*x*x)
+
+	change := c.Position.Sub(goal)
+	if maxChange := c.Follow.MaxSpeed * smoothTime; c.Follow.MaxSpeed > 0 && change.Len() > maxChange {
+		change = change.Normalize().Mul(maxChange)
+	}
+	target := c.Position.Sub(change)
+
+	temp := c.follow.velocity.Add(change.Mul(omega)).Mul(deltaTime)
+	c.follow.velocity = c.follow.velocity.Sub(temp.Mul(omega)).Mul(decay)
+	position := target.Add(change.Add(temp).Mul(decay))
+
+	// Don't overshoot
+	if goal.Sub(c.Position).Dot(position.Sub(goal)) > 0 {
+		position = goal
+		c.follow.velocity = mgl32.Vec2{}
+	}
+	return position
+}
+
+func (c *Camera) updateFraming(deltaTime float32) {
+	if !c.Framing.Enabled || len(c.targets) < 2 {
+		return
+	}
+
+	minPoint, maxPoint := c.targetBounds()
+	width := maxPoint.X() - minPoint.X() + 2*c.Framing.Padding
+	height := maxPoint.Y() - minPoint.Y() + 2*c.Framing.Padding
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	zoom := min(c.Size.X()/width, c.Size.Y()/height)
+	zoom = mgl32.Clamp(zoom, c.Framing.MinZoom, c.Framing.MaxZoom)
+	c.Zoom += (zoom - c.Zoom) * damp(c.Framing.ZoomSpeed, deltaTime)
+}
+
+// damp turns a rate into the fraction to move this frame, independent of
+// the frame rate.
+func damp(rate, deltaTime float32) float32 {
+	if rate <= 0 {
+		return 1
+	}
+	return 1 - float32(math.Exp(float64(-rate*deltaTime)))
+}
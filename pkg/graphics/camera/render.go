@@ -0,0 +1,99 @@
+package camera
+
+import (
+	"math"
+	"sort"
+
+	"github.com/go-gl/gl/all-core/gl"
+)
+
+// ClearFlags decides what a camera clears in its rectangle before drawing.
+type ClearFlags int
+
+const (
+	ClearColorBuffer ClearFlags = 1 << iota
+	ClearDepthBuffer
+
+	ClearNone ClearFlags = 0
+	ClearAll             = ClearColorBuffer | ClearDepthBuffer
+)
+
+// AllLayers is a LayerMask that sees every entity.
+const AllLayers uint32 = math.MaxUint32
+
+// MaxLayer is the highest layer a LayerMask has a bit for.
+const MaxLayer = 31
+
+// Layers builds a LayerMask from layer numbers, 0 to MaxLayer. Others are
+// ignored.
+func Layers(layers ...int) uint32 {
+	var mask uint32
+	for _, layer := range layers {
+		if layer >= 0 && layer <= MaxLayer {
+			mask |= 1 << uint(layer)
+		}
+	}
+	return mask
+}
+
+// SeesLayer reports whether an entity on layer is in the camera's mask.
+func (c *Camera) SeesLayer(layer int) bool {
+	return layer >= 0 && layer <= MaxLayer && c.LayerMask&(1<<uint(layer)) != 0
+}
+
+// Begin points drawing at the camera's rectangle within the current GL
+// viewport and clears it as set by Clear. The current viewport is usually
+// the whole picture, or a post-processing chain's target. End restores it.
+func (c *Camera) Begin() {
+	gl.GetIntegerv(gl.VIEWPORT, &c.output[0])
+	x, y, width, height := c.outputRect()
+	gl.Viewport(x, y, width, height)
+
+	if c.Clear == ClearNone {
+		return
+	}
+
+	// Scissor so only the camera's rectangle is cleared
+	var previous [4]float32
+	gl.GetFloatv(gl.COLOR_CLEAR_VALUE, &previous[0])
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(x, y, width, height)
+
+	var mask uint32
+	if c.Clear&ClearColorBuffer != 0 {
+		gl.ClearColor(c.Background[0], c.Background[1], c.Background[2], c.Background[3])
+		mask |= gl.COLOR_BUFFER_BIT
+	}
+	if c.Clear&ClearDepthBuffer != 0 {
+		mask |= gl.DEPTH_BUFFER_BIT
+	}
+	gl.Clear(mask)
+
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.ClearColor(previous[0], previous[1], previous[2], previous[3])
+}
+
+func (c *Camera) End() {
+	gl.Viewport(c.output[0], c.output[1], c.output[2], c.output[3])
+}
+
+// outputRect is the camera's rectangle in GL viewport coordinates, which
+// count rows from the bottom.
+func (c *Camera) outputRect() (x, y, width, height int32) {
+	outputX, outputY := float64(c.output[0]), float64(c.output[1])
+	outputWidth, outputHeight := float64(c.output[2]), float64(c.output[3])
+
+	left := math.Round(outputX + outputWidth*float64(c.Rect[0]))
+	right := math.Round(outputX + outputWidth*float64(c.Rect[0]+c.Rect[2]))
+	top := math.Round(outputY + outputHeight*float64(1-c.Rect[1]))
+	bottom := math.Round(outputY + outputHeight*float64(1-c.Rect[1]-c.Rect[3]))
+	return int32(left), int32(bottom), int32(right - left), int32(top - bottom)
+}
+
+// SortByOrder sorts cameras into drawing order. Cameras with the same Order
+// keep the order they were added in.
+func SortByOrder(cameras []*Camera) {
+	sort.SliceStable(cameras, func(i, j int) bool {
+		return cameras[i].Order < cameras[j].Order
+	})
+}
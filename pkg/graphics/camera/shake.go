@@ -0,0 +1,85 @@
+package camera
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ShakeSettings controls trauma-based screen shake. Trauma is added by hits
+// and explosions and decays over time; the shake strength is trauma squared,
+// so small knocks stay subtle while big ones stack up.
+type ShakeSettings struct {
+	MaxOffset mgl32.Vec2 // Screen pixels at full trauma
+	MaxAngle  float32    // Radians at full trauma
+	Frequency float32    // Noise samples per second; higher is more violent
+	Decay     float32    // Trauma lost per second
+}
+
+func DefaultShakeSettings() ShakeSettings {
+	return ShakeSettings{
+		MaxOffset: mgl32.Vec2{16, 16},
+		MaxAngle:  0.05,
+		Frequency: 25,
+		Decay:     1.5,
+	}
+}
+
+type shakeState struct {
+	trauma float32
+	time   float32
+	offset mgl32.Vec2
+	angle  float32
+}
+
+// AddTrauma adds to the shake, from 0 (none) to 1 (full).
+func (c *Camera) AddTrauma(amount float32) {
+	c.shake.trauma = mgl32.Clamp(c.shake.trauma+amount, 0, 1)
+}
+
+func (c *Camera) GetTrauma() float32 {
+	return c.shake.trauma
+}
+
+func (c *Camera) updateShake(deltaTime float32) {
+	if c.shake.trauma <= 0 {
+		c.shake.offset = mgl32.Vec2{}
+		c.shake.angle = 0
+		return
+	}
+
+	c.shake.time += deltaTime
+	t := c.shake.time * c.Shake.Frequency
+	amount := c.shake.trauma * c.shake.trauma
+
+	// Each channel reads its own stretch of noise, so they move independently
+	c.shake.offset = mgl32.Vec2{
+		c.Shake.MaxOffset.X() * amount * noise(t),
+		c.Shake.MaxOffset.Y() * amount * noise(t+100),
+	}
+	c.shake.angle = c.Shake.MaxAngle * amount * noise(t+200)
+
+	c.shake.trauma = max(c.shake.trauma-c.Shake.Decay*deltaTime, 0)
+}
+
+// noise is smooth 1D value noise in [-1, 1], so the shake wanders instead
+// of jumping between random positions every frame.
+func noise(t float32) float32 {
+	floor := float32(math.Floor(float64(t)))
+	fraction := t - floor
+	fraction = fraction * fraction * (3 - 2*fraction)
+
+	a := hash(int32(floor))
+	b := hash(int32(floor) + 1)
+	return a + (b-a)*fraction
+}
+
+func hash(n int32) float32 {
+	x := uint32(n)
+	x ^= x >> 16
+	x *= 0x7feb352d
+	x ^= x >> 15
+	x *= 0x846ca68b
+	x ^= x >> 16
+	return float32(x)/float32(math.MaxUint32)*2 - 1
+}
@@ -0,0 +1,212 @@
+package camera
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/all-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ScalingPolicy decides how a fixed world size maps onto a window of any size.
+type ScalingPolicy int
+
+const (
+	ScaleFit     ScalingPolicy = iota // Keep aspect ratio, show the whole world, letterbox the rest
+	ScaleFill                         // Keep aspect ratio, cover the window, crop the overflow
+	ScaleStretch                      // Cover the window exactly, distorting the aspect ratio
+	ScaleInteger                      // Largest whole-number scale that fits, letterbox the rest
+	ScaleExtend                       // Keep aspect ratio and show more world along the longer side
+)
+
+// Viewport owns the mapping between the window and the camera. Call Update
+// whenever the window resizes; it recomputes the camera size, the screen
+// rectangle and the projection, and keeps ScreenToWorld correct when the
+// picture is letterboxed.
+//
+// Further cameras, such as split-screen views or a minimap, can share the
+// viewport with AddCamera. Each is sized to its Rect's share of the world.
+type Viewport struct {
+	Camera      *Camera // The main camera, used by the mouse helpers
+	Policy      ScalingPolicy
+	WorldWidth  float32 // Virtual resolution the game is designed for
+	WorldHeight float32
+
+	// Screen rectangle in framebuffer pixels, origin top-left. With
+	// ScaleFill it can extend past the window edges.
+	ScreenX, ScreenY          int32
+	ScreenWidth, ScreenHeight int32
+
+	cameras                             []*Camera
+	visibleSize                         mgl32.Vec2 // World size on screen, which ScaleExtend grows
+	windowWidth, windowHeight           int
+	framebufferWidth, framebufferHeight int
+}
+
+func NewViewport(camera *Camera, policy ScalingPolicy, worldWidth, worldHeight float32) *Viewport {
+	viewport := &Viewport{
+		Camera:      camera,
+		Policy:      policy,
+		WorldWidth:  worldWidth,
+		WorldHeight: worldHeight,
+		cameras:     make([]*Camera, 0),
+	}
+	viewport.AddCamera(camera)
+	return viewport
+}
+
+// AddCamera attaches another camera, so it is resized with the window and
+// maps the cursor through its own rectangle.
+func (v *Viewport) AddCamera(camera *Camera) {
+	for _, existing := range v.cameras {
+		if existing == camera {
+			return
+		}
+	}
+
+	camera.viewport = v
+	v.cameras = append(v.cameras, camera)
+	v.resizeCamera(camera)
+}
+
+func (v *Viewport) RemoveCamera(camera *Camera) {
+	for i, existing := range v.cameras {
+		if existing == camera && camera != v.Camera {
+			v.cameras = append(v.cameras[:i], v.cameras[i+1:]...)
+			camera.viewport = nil
+			return
+		}
+	}
+}
+
+func (v *Viewport) GetCameras() []*Camera {
+	return v.cameras
+}
+
+// resizeCamera gives a camera its Rect's share of the visible world.
+func (v *Viewport) resizeCamera(camera *Camera) {
+	if v.visibleSize.X() <= 0 || v.visibleSize.Y() <= 0 {
+		return
+	}
+	camera.Size = mgl32.Vec2{v.visibleSize.X() * camera.Rect[2], v.visibleSize.Y() * camera.Rect[3]}
+	camera.ClampToBounds()
+}
+
+// Update recomputes the viewport for a window. Window sizes are in screen
+// coordinates (what the cursor uses); framebuffer sizes are in pixels, which
+// differ on high-DPI displays.
+func (v *Viewport) Update(windowWidth, windowHeight, framebufferWidth, framebufferHeight int) {
+	v.windowWidth, v.windowHeight = windowWidth, windowHeight
+	v.framebufferWidth, v.framebufferHeight = framebufferWidth, framebufferHeight
+
+	screenWidth, screenHeight := float32(framebufferWidth), float32(framebufferHeight)
+	if screenWidth <= 0 || screenHeight <= 0 || v.WorldWidth <= 0 || v.WorldHeight <= 0 {
+		return
+	}
+
+	worldWidth, worldHeight := v.WorldWidth, v.WorldHeight
+	scaleX, scaleY := screenWidth/worldWidth, screenHeight/worldHeight
+
+	switch v.Policy {
+	case ScaleFit:
+		scale := min(scaleX, scaleY)
+		scaleX, scaleY = scale, scale
+	case ScaleFill:
+		scale := max(scaleX, scaleY)
+		scaleX, scaleY = scale, scale
+	case ScaleInteger:
+		scale := float32(math.Max(1, math.Floor(float64(min(scaleX, scaleY)))))
+		scaleX, scaleY = scale, scale
+	case ScaleExtend:
+		scale := min(scaleX, scaleY)
+		scaleX, scaleY = scale, scale
+		worldWidth, worldHeight = screenWidth/scale, screenHeight/scale
+	}
+
+	width, height := worldWidth*scaleX, worldHeight*scaleY
+	v.ScreenWidth = int32(math.Round(float64(width)))
+	v.ScreenHeight = int32(math.Round(float64(height)))
+	v.ScreenX = int32(math.Floor(float64(screenWidth-width) / 2))
+	v.ScreenY = int32(math.Floor(float64(screenHeight-height) / 2))
+
+	v.visibleSize = mgl32.Vec2{worldWidth, worldHeight}
+	for _, camera := range v.cameras {
+		v.resizeCamera(camera)
+	}
+}
+
+// Apply sets the GL viewport to the screen rectangle. GL counts rows from
+// the bottom, so the rectangle is flipped.
+func (v *Viewport) Apply() {
+	gl.Viewport(v.ScreenX, int32(v.framebufferHeight)-v.ScreenY-v.ScreenHeight, v.ScreenWidth, v.ScreenHeight)
+}
+
+func (v *Viewport) GetProjectionMatrix() mgl32.Mat4 {
+	return v.Camera.GetProjectionMatrix()
+}
+
+// pixelRatio converts cursor coordinates to framebuffer pixels.
+func (v *Viewport) pixelRatio() mgl32.Vec2 {
+	if v.windowWidth <= 0 || v.windowHeight <= 0 {
+		return mgl32.Vec2{1, 1}
+	}
+	return mgl32.Vec2{
+		float32(v.framebufferWidth) / float32(v.windowWidth),
+		float32(v.framebufferHeight) / float32(v.windowHeight),
+	}
+}
+
+// ScreenToViewport converts a cursor position to camera screen coordinates,
+// (0, 0) to Camera.Size. The result is false when the position falls in the
+// letterbox bars.
+func (v *Viewport) ScreenToViewport(screenPos mgl32.Vec2) (mgl32.Vec2, bool) {
+	return v.screenToCamera(v.Camera, screenPos)
+}
+
+// ViewportToScreen converts camera screen coordinates back to a cursor position.
+func (v *Viewport) ViewportToScreen(viewportPos mgl32.Vec2) mgl32.Vec2 {
+	return v.cameraToScreen(v.Camera, viewportPos)
+}
+
+// cameraRect is the camera's part of the screen rectangle in framebuffer
+// pixels, origin top-left.
+func (v *Viewport) cameraRect(camera *Camera) (x, y, width, height float32) {
+	width = float32(v.ScreenWidth) * camera.Rect[2]
+	height = float32(v.ScreenHeight) * camera.Rect[3]
+	x = float32(v.ScreenX) + float32(v.ScreenWidth)*camera.Rect[0]
+	y = float32(v.ScreenY) + float32(v.ScreenHeight)*camera.Rect[1]
+	return x, y, width, height
+}
+
+func (v *Viewport) screenToCamera(camera *Camera, screenPos mgl32.Vec2) (mgl32.Vec2, bool) {
+	rectX, rectY, width, height := v.cameraRect(camera)
+	if width <= 0 || height <= 0 {
+		return screenPos, false
+	}
+
+	ratio := v.pixelRatio()
+	x := (screenPos.X()*ratio.X() - rectX) / width * camera.Size.X()
+	y := (screenPos.Y()*ratio.Y() - rectY) / height * camera.Size.Y()
+
+	inside := x >= 0 && y >= 0 && x <= camera.Size.X() && y <= camera.Size.Y()
+	return mgl32.Vec2{x, y}, inside
+}
+
+func (v *Viewport) cameraToScreen(camera *Camera, cameraPos mgl32.Vec2) mgl32.Vec2 {
+	if camera.Size.X() <= 0 || camera.Size.Y() <= 0 {
+		return cameraPos
+	}
+
+	rectX, rectY, width, height := v.cameraRect(camera)
+	ratio := v.pixelRatio()
+	x := cameraPos.X()/camera.Size.X()*width + rectX
+	y := cameraPos.Y()/camera.Size.Y()*height + rectY
+	return mgl32.Vec2{x / ratio.X(), y / ratio.Y()}
+}
+
+func (v *Viewport) ScreenToWorld(screenPos mgl32.Vec2) mgl32.Vec2 {
+	return v.Camera.ScreenToWorld(screenPos)
+}
+
+func (v *Viewport) WorldToScreen(worldPos mgl32.Vec2) mgl32.Vec2 {
+	return v.Camera.WorldToScreen(worldPos)
+}
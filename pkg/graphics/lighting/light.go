@@ -0,0 +1,75 @@
+package lighting
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type LightType int
+
+const (
+	LightPoint   LightType = iota // Radiates in every direction from Position
+	LightSpot                     // A cone from Position towards Direction
+	LightAmbient                  // Lights everything evenly; position and radius are ignored
+)
+
+type Light struct {
+	Type        LightType
+	Position    mgl32.Vec2
+	Color       mgl32.Vec3
+	Intensity   float32
+	Radius      float32 // Distance where the light fades out completely
+	Falloff     float32 // Attenuation exponent; 1 is linear, higher fades faster near the center
+	Direction   float32 // Spot direction in radians, 0 points along +X
+	InnerAngle  float32 // Spot half-angle at full strength, in radians
+	OuterAngle  float32 // Spot half-angle where the cone fades out
+	Height      float32 // Distance above the scene for normal mapping; lower gives more grazing light
+	CastShadows bool
+	Enabled     bool
+}
+
+func NewPointLight(position mgl32.Vec2, color mgl32.Vec3, radius float32) *Light {
+	return &Light{
+		Type:        LightPoint,
+		Position:    position,
+		Color:       color,
+		Intensity:   1.0,
+		Radius:      radius,
+		Falloff:     2.0,
+		Height:      radius * 0.25,
+		CastShadows: true,
+		Enabled:     true,
+	}
+}
+
+// NewSpotLight creates a cone light; angle is the half-angle of the cone in
+// radians, softened over its outer quarter.
+func NewSpotLight(position mgl32.Vec2, color mgl32.Vec3, radius, direction, angle float32) *Light {
+	light := NewPointLight(position, color, radius)
+	light.Type = LightSpot
+	light.Direction = direction
+	light.InnerAngle = angle * 0.75
+	light.OuterAngle = angle
+	return light
+}
+
+func NewAmbientLight(color mgl32.Vec3, intensity float32) *Light {
+	return &Light{
+		Type:      LightAmbient,
+		Color:     color,
+		Intensity: intensity,
+		Enabled:   true,
+	}
+}
+
+// corners returns the world-space square the light can reach.
+func (l *Light) corners() [4]mgl32.Vec2 {
+	x, y, r := l.Position.X(), l.Position.Y(), l.Radius
+	return [4]mgl32.Vec2{{x - r, y - r}, {x + r, y - r}, {x - r, y + r}, {x + r, y + r}}
+}
+
+func (l *Light) directionVector() mgl32.Vec2 {
+	sin, cos := math.Sincos(float64(l.Direction))
+	return mgl32.Vec2{float32(cos), float32(sin)}
+}
@@ -0,0 +1,83 @@
+package lighting
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Occluder is a polygon that blocks light. Points are in world space and may
+// be wound either way; concave polygons work but convex ones are cheaper.
+// The polygon itself stays lit, only the area behind it is shadowed.
+type Occluder struct {
+	Points  []mgl32.Vec2
+	Enabled bool
+}
+
+func NewOccluder(points []mgl32.Vec2) *Occluder {
+	return &Occluder{
+		Points:  points,
+		Enabled: true,
+	}
+}
+
+// BoxPolygon returns a width x height rectangle centered on the origin, for
+// occluders built from a sprite's size.
+func BoxPolygon(width, height float32) []mgl32.Vec2 {
+	halfWidth, halfHeight := width/2, height/2
+	return []mgl32.Vec2{
+		{-halfWidth, -halfHeight},
+		{halfWidth, -halfHeight},
+		{halfWidth, halfHeight},
+		{-halfWidth, halfHeight},
+	}
+}
+
+// reaches reports whether the occluder's bounds overlap the light's square.
+func (o *Occluder) reaches(light *Light) bool {
+	if len(o.Points) < 2 {
+		return false
+	}
+
+	minPoint, maxPoint := o.Points[0], o.Points[0]
+	for _, point := range o.Points[1:] {
+		minPoint = mgl32.Vec2{min(minPoint.X(), point.X()), min(minPoint.Y(), point.Y())}
+		maxPoint = mgl32.Vec2{max(maxPoint.X(), point.X()), max(maxPoint.Y(), point.Y())}
+	}
+
+	x, y, r := light.Position.X(), light.Position.Y(), light.Radius
+	return maxPoint.X() >= x-r && minPoint.X() <= x+r && maxPoint.Y() >= y-r && minPoint.Y() <= y+r
+}
+
+// appendShadow appends triangles covering the shadow cast from lightPos,
+// as (x, y, w) vertices. Each edge facing away from the light is extruded
+// to infinity (w = 0).
+func (o *Occluder) appendShadow(vertices []float32, lightPos mgl32.Vec2) []float32 {
+	count := len(o.Points)
+
+	// Signed area tells the winding, so edge normals can be made to face out
+	var area float32
+	for i, a := range o.Points {
+		b := o.Points[(i+1)%count]
+		area += a.X()*b.Y() - b.X()*a.Y()
+	}
+	if area == 0 {
+		return vertices
+	}
+	winding := float32(1)
+	if area < 0 {
+		winding = -1
+	}
+
+	for i, a := range o.Points {
+		b := o.Points[(i+1)%count]
+		edge := b.Sub(a)
+		normal := mgl32.Vec2{edge.Y(), -edge.X()}.Mul(winding)
+		if normal.Dot(a.Sub(lightPos)) <= 0 {
+			continue // Faces the light
+		}
+
+		farA, farB := a.Sub(lightPos), b.Sub(lightPos)
+		vertices = append(vertices,
+			a.X(), a.Y(), 1, b.X(), b.Y(), 1, farB.X(), farB.Y(), 0,
+			a.X(), a.Y(), 1, farB.X(), farB.Y(), 0, farA.X(), farA.Y(), 0,
+		)
+	}
+	return vertices
+}
@@ -0,0 +1,448 @@
+package lighting
+
+import (
+	"embed"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/go-gl/gl/all-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+	"github.com/lunararch/helios/pkg/graphics/postprocess"
+	"github.com/lunararch/helios/pkg/graphics/shader"
+	"github.com/lunararch/helios/pkg/graphics/sprite"
+	"github.com/lunararch/helios/pkg/graphics/texture"
+)
+
+//go:embed shaders
+var shaderFiles embed.FS
+
+// Quality picks a preset of Settings.
+type Quality int
+
+const (
+	QualityLow    Quality = iota // Quarter-resolution light map, no shadows or normal maps
+	QualityMedium                // Half-resolution light map with shadows and normal maps
+	QualityHigh                  // Full resolution, no light limit
+)
+
+type Settings struct {
+	ResolutionScale float32 // Light map size relative to the scene; lights are soft, so low values hold up well
+	Shadows         bool
+	NormalMaps      bool
+	MaxLights       int // Point and spot lights drawn per frame, in the order added; 0 is unlimited
+}
+
+func QualitySettings(quality Quality) Settings {
+	switch quality {
+	case QualityLow:
+		return Settings{ResolutionScale: 0.25, MaxLights: 8}
+	case QualityMedium:
+		return Settings{ResolutionScale: 0.5, Shadows: true, NormalMaps: true, MaxLights: 32}
+	default:
+		return Settings{ResolutionScale: 1.0, Shadows: true, NormalMaps: true}
+	}
+}
+
+// Renderer draws lights into a light map and multiplies the scene by it. It
+// is a post-processing effect, so it goes into the scene's chain (usually
+// first, so bloom picks up lit highlights), and each frame the scene draws
+// normals and the light map before the chain ends:
+//
+//	chain.Begin()
+//	// draw the scene as usual
+//	if lights.BeginNormals(batch) {
+//		world.Render(alpha)
+//		lights.EndNormals(batch)
+//	}
+//	lights.Render(camera)
+//	chain.End()
+//
+// Normals come from Sprite.NormalMap, which should share its sprite's
+// alpha. They are used as drawn, so rotated or flipped sprites keep their
+// unrotated shading.
+type Renderer struct {
+	Ambient mgl32.Vec3 // Base light level, added to any ambient lights
+
+	lights    []*Light
+	occluders []*Occluder
+	settings  Settings
+
+	lightMap   *texture.RenderTarget
+	normals    *texture.RenderTarget
+	flatNormal *texture.Texture
+
+	lightShader     *shader.Shader
+	shadowShader    *shader.Shader
+	compositeShader *shader.Shader
+
+	lightVAO, lightVBO   uint32
+	shadowVAO, shadowVBO uint32
+	shadowCapacity       int // Floats the shadow buffer can hold
+	shadowVertices       []float32
+
+	width, height int32 // Scene size the chain renders at
+	normalsDrawn  bool  // The normal buffer holds this frame's normals
+}
+
+func NewRenderer(quality Quality) (*Renderer, error) {
+	lightShader, err := loadShader("light.vert", "light.frag")
+	if err != nil {
+		return nil, err
+	}
+	lightShader.Use()
+	lightShader.SetInt("normalMap", 0)
+
+	shadowShader, err := loadShader("shadow.vert", "shadow.frag")
+	if err != nil {
+		lightShader.Delete()
+		return nil, err
+	}
+
+	compositeCode, err := shaderFiles.ReadFile("shaders/composite.frag")
+	if err != nil {
+		lightShader.Delete()
+		shadowShader.Delete()
+		return nil, err
+	}
+	compositeShader, err := postprocess.NewPassShader(string(compositeCode))
+	if err != nil {
+		lightShader.Delete()
+		shadowShader.Delete()
+		return nil, fmt.Errorf("composite.frag: %w", err)
+	}
+	compositeShader.SetInt("lightMap", 1)
+
+	// Straight up, as 0.5, 0.5, 1 encodes, for sprites without a normal map
+	flat := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	flat.Set(0, 0, color.RGBA{R: 128, G: 128, B: 255, A: 255})
+	flatNormal, err := texture.LoadFromImage(flat)
+	if err != nil {
+		lightShader.Delete()
+		shadowShader.Delete()
+		compositeShader.Delete()
+		return nil, err
+	}
+
+	r := &Renderer{
+		Ambient:         mgl32.Vec3{0.1, 0.1, 0.15},
+		lights:          make([]*Light, 0),
+		occluders:       make([]*Occluder, 0),
+		settings:        QualitySettings(quality),
+		flatNormal:      flatNormal,
+		lightShader:     lightShader,
+		shadowShader:    shadowShader,
+		compositeShader: compositeShader,
+	}
+	r.setupBuffers()
+	return r, nil
+}
+
+func loadShader(vertexName, fragmentName string) (*shader.Shader, error) {
+	vertexCode, err := shaderFiles.ReadFile("shaders/" + vertexName)
+	if err != nil {
+		return nil, err
+	}
+
+	fragmentCode, err := shaderFiles.ReadFile("shaders/" + fragmentName)
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := shader.NewFromSource(string(vertexCode), string(fragmentCode))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fragmentName, err)
+	}
+	return program, nil
+}
+
+func (r *Renderer) setupBuffers() {
+	gl.GenVertexArrays(1, &r.lightVAO)
+	gl.GenBuffers(1, &r.lightVBO)
+	gl.BindVertexArray(r.lightVAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, r.lightVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*2*4, nil, gl.DYNAMIC_DRAW)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, 2*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+
+	gl.GenVertexArrays(1, &r.shadowVAO)
+	gl.GenBuffers(1, &r.shadowVBO)
+	gl.BindVertexArray(r.shadowVAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, r.shadowVBO)
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 3*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+func (r *Renderer) AddLight(light *Light) {
+	r.lights = append(r.lights, light)
+}
+
+func (r *Renderer) RemoveLight(light *Light) {
+	for i, existing := range r.lights {
+		if existing == light {
+			r.lights = append(r.lights[:i], r.lights[i+1:]...)
+			return
+		}
+	}
+}
+
+func (r *Renderer) GetLights() []*Light {
+	return r.lights
+}
+
+func (r *Renderer) AddOccluder(occluder *Occluder) {
+	r.occluders = append(r.occluders, occluder)
+}
+
+func (r *Renderer) RemoveOccluder(occluder *Occluder) {
+	for i, existing := range r.occluders {
+		if existing == occluder {
+			r.occluders = append(r.occluders[:i], r.occluders[i+1:]...)
+			return
+		}
+	}
+}
+
+func (r *Renderer) SetQuality(quality Quality) error {
+	return r.SetSettings(QualitySettings(quality))
+}
+
+func (r *Renderer) SetSettings(settings Settings) error {
+	r.settings = settings
+	if r.width > 0 && r.height > 0 {
+		return r.resizeTargets()
+	}
+	return nil
+}
+
+func (r *Renderer) GetSettings() Settings {
+	return r.settings
+}
+
+// Resize is called by the post-processing chain with the scene size.
+func (r *Renderer) Resize(width, height int32) error {
+	r.width, r.height = width, height
+	return r.resizeTargets()
+}
+
+func (r *Renderer) resizeTargets() error {
+	scale := r.settings.ResolutionScale
+	if scale <= 0 {
+		scale = 1
+	}
+	width := max(1, int32(float32(r.width)*scale))
+	height := max(1, int32(float32(r.height)*scale))
+
+	if r.lightMap == nil {
+		target, err := texture.NewRenderTarget(width, height, texture.FilterLinear)
+		if err != nil {
+			return err
+		}
+		r.lightMap = target
+	} else if err := r.lightMap.Resize(width, height); err != nil {
+		return err
+	}
+
+	if !r.settings.NormalMaps {
+		if r.normals != nil {
+			r.normals.Delete()
+			r.normals = nil
+		}
+		return nil
+	}
+
+	if r.normals == nil {
+		target, err := texture.NewRenderTarget(r.width, r.height, texture.FilterLinear)
+		if err != nil {
+			return err
+		}
+		r.normals = target
+		return nil
+	}
+	return r.normals.Resize(r.width, r.height)
+}
+
+// BeginNormals starts drawing the normal buffer with batch. It returns false
+// when normal maps are off, in which case the scene skips the pass.
+func (r *Renderer) BeginNormals(batch *sprite.SpriteBatch) bool {
+	if !r.settings.NormalMaps || r.normals == nil {
+		return false
+	}
+
+	batch.BeginTarget(r.normals)
+	r.normals.Clear(mgl32.Vec4{0.5, 0.5, 1.0, 1.0})
+	batch.SetNormalPass(r.flatNormal)
+	return true
+}
+
+func (r *Renderer) EndNormals(batch *sprite.SpriteBatch) {
+	batch.End()
+	batch.SetNormalPass(nil)
+	r.normalsDrawn = true
+}
+
+// Render draws the light map for this frame as seen by cam.
+func (r *Renderer) Render(cam *camera.Camera) {
+	if r.lightMap == nil {
+		return
+	}
+
+	ambient := r.Ambient
+	for _, light := range r.lights {
+		if light.Enabled && light.Type == LightAmbient {
+			ambient = ambient.Add(light.Color.Mul(light.Intensity))
+		}
+	}
+
+	r.lightMap.Bind()
+	defer r.lightMap.Unbind()
+	r.lightMap.Clear(ambient.Vec4(1))
+
+	depthTest := gl.IsEnabled(gl.DEPTH_TEST)
+	blend := gl.IsEnabled(gl.BLEND)
+	gl.Disable(gl.DEPTH_TEST)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.ONE, gl.ONE)
+
+	projection, view := cam.GetProjectionMatrix(), cam.GetViewMatrix()
+	r.shadowShader.Use()
+	r.shadowShader.SetMat4("projection", projection)
+	r.shadowShader.SetMat4("view", view)
+
+	useNormals := r.settings.NormalMaps && r.normalsDrawn && r.normals != nil
+	r.lightShader.Use()
+	r.lightShader.SetMat4("projection", projection)
+	r.lightShader.SetMat4("view", view)
+	r.lightShader.SetBool("useNormals", useNormals)
+	r.lightShader.SetVec2("targetSize", mgl32.Vec2{float32(r.lightMap.Width), float32(r.lightMap.Height)})
+	if useNormals {
+		r.normals.Texture.Bind(0)
+	}
+
+	drawn := 0
+	for _, light := range r.lights {
+		if !light.Enabled || light.Type == LightAmbient || light.Radius <= 0 {
+			continue
+		}
+		if r.settings.MaxLights > 0 && drawn >= r.settings.MaxLights {
+			break
+		}
+		drawn++
+
+		shadowed := r.settings.Shadows && light.CastShadows && r.drawShadows(light)
+		r.drawLight(light, useNormals)
+		if shadowed {
+			gl.Disable(gl.STENCIL_TEST)
+		}
+	}
+
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	if !blend {
+		gl.Disable(gl.BLEND)
+	}
+	if depthTest {
+		gl.Enable(gl.DEPTH_TEST)
+	}
+	r.normalsDrawn = false
+}
+
+// drawShadows marks the light's shadows in the stencil buffer and leaves
+// the stencil test set to skip them. It returns false if nothing is
+// shadowed, with the stencil test left off.
+func (r *Renderer) drawShadows(light *Light) bool {
+	r.shadowVertices = r.shadowVertices[:0]
+	for _, occluder := range r.occluders {
+		if occluder.Enabled && occluder.reaches(light) {
+			r.shadowVertices = occluder.appendShadow(r.shadowVertices, light.Position)
+		}
+	}
+	if len(r.shadowVertices) == 0 {
+		return false
+	}
+
+	gl.Enable(gl.STENCIL_TEST)
+	gl.Clear(gl.STENCIL_BUFFER_BIT)
+	gl.ColorMask(false, false, false, false)
+	gl.StencilFunc(gl.ALWAYS, 1, 0xFF)
+	gl.StencilOp(gl.KEEP, gl.KEEP, gl.REPLACE)
+
+	gl.BindVertexArray(r.shadowVAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, r.shadowVBO)
+	if len(r.shadowVertices) > r.shadowCapacity {
+		r.shadowCapacity = len(r.shadowVertices) * 2
+		gl.BufferData(gl.ARRAY_BUFFER, r.shadowCapacity*4, nil, gl.DYNAMIC_DRAW)
+	}
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(r.shadowVertices)*4, gl.Ptr(r.shadowVertices))
+
+	r.shadowShader.Use()
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(r.shadowVertices)/3))
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+
+	gl.ColorMask(true, true, true, true)
+	gl.StencilFunc(gl.EQUAL, 0, 0xFF)
+	gl.StencilOp(gl.KEEP, gl.KEEP, gl.KEEP)
+	return true
+}
+
+func (r *Renderer) drawLight(light *Light, useNormals bool) {
+	corners := light.corners()
+	vertices := []float32{
+		corners[0].X(), corners[0].Y(),
+		corners[1].X(), corners[1].Y(),
+		corners[2].X(), corners[2].Y(),
+		corners[3].X(), corners[3].Y(),
+	}
+
+	r.lightShader.Use()
+	r.lightShader.SetVec2("lightPosition", light.Position)
+	r.lightShader.SetVec3("lightColor", light.Color)
+	r.lightShader.SetFloat("intensity", light.Intensity)
+	r.lightShader.SetFloat("radius", light.Radius)
+	r.lightShader.SetFloat("falloff", max(light.Falloff, 0.01))
+	r.lightShader.SetBool("isSpot", light.Type == LightSpot)
+	if light.Type == LightSpot {
+		r.lightShader.SetVec2("direction", light.directionVector())
+		r.lightShader.SetFloat("innerCos", float32(math.Cos(float64(light.InnerAngle))))
+		r.lightShader.SetFloat("outerCos", float32(math.Cos(float64(light.OuterAngle))))
+	}
+	if useNormals {
+		r.lightShader.SetFloat("height", light.Height)
+	}
+
+	gl.BindVertexArray(r.lightVAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, r.lightVBO)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(vertices)*4, gl.Ptr(vertices))
+	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+// Apply multiplies the scene by the light map.
+func (r *Renderer) Apply(source *texture.Texture, destination *texture.RenderTarget) {
+	if r.lightMap != nil {
+		r.lightMap.Texture.Bind(1)
+	}
+	postprocess.RunPass(r.compositeShader, source, destination)
+}
+
+func (r *Renderer) Delete() {
+	for _, target := range []*texture.RenderTarget{r.lightMap, r.normals} {
+		if target != nil {
+			target.Delete()
+		}
+	}
+	r.flatNormal.Delete()
+	r.lightShader.Delete()
+	r.shadowShader.Delete()
+	r.compositeShader.Delete()
+	gl.DeleteBuffers(1, &r.lightVBO)
+	gl.DeleteVertexArrays(1, &r.lightVAO)
+	gl.DeleteBuffers(1, &r.shadowVBO)
+	gl.DeleteVertexArrays(1, &r.shadowVAO)
+}
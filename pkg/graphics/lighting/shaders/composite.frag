@@ -0,0 +1,14 @@
+#version 410 core
+
+in vec2 TexCoord;
+out vec4 FragColor;
+
+uniform sampler2D screenTexture;
+uniform sampler2D lightMap;
+
+void main()
+{
+    vec4 scene = texture(screenTexture, TexCoord);
+    vec3 light = texture(lightMap, TexCoord).rgb;
+    FragColor = vec4(scene.rgb * light, scene.a);
+}
@@ -0,0 +1,46 @@
+#version 410 core
+
+in vec2 WorldPos;
+out vec4 FragColor;
+
+uniform vec2 lightPosition;
+uniform vec3 lightColor;
+uniform float intensity;
+uniform float radius;
+uniform float falloff;
+
+uniform bool isSpot;
+uniform vec2 direction;
+uniform float innerCos;
+uniform float outerCos;
+
+uniform bool useNormals;
+uniform sampler2D normalMap;
+uniform vec2 targetSize;
+uniform float height;
+
+void main()
+{
+    vec2 toFragment = WorldPos - lightPosition;
+    float distance = length(toFragment);
+    if (distance >= radius) {
+        discard;
+    }
+
+    float attenuation = pow(1.0 - distance / radius, falloff);
+
+    if (isSpot && distance > 0.0) {
+        float cosAngle = dot(toFragment / distance, direction);
+        attenuation *= smoothstep(outerCos, innerCos, cosAngle);
+    }
+
+    if (useNormals) {
+        // The normal buffer covers the same view as the light map
+        vec3 normal = normalize(texture(normalMap, gl_FragCoord.xy / targetSize).rgb * 2.0 - 1.0);
+        // Normal maps point +Y up, the world is Y-down
+        vec3 toLight = normalize(vec3(-toFragment.x, toFragment.y, height));
+        attenuation *= max(dot(normal, toLight), 0.0);
+    }
+
+    FragColor = vec4(lightColor * intensity * attenuation, 1.0);
+}
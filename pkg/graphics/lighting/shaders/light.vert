@@ -0,0 +1,13 @@
+#version 410 core
+
+layout(location = 0) in vec2 aPos;
+
+out vec2 WorldPos;
+
+uniform mat4 view;
+uniform mat4 projection;
+
+void main(){
+    gl_Position = projection * view * vec4(aPos, 0.0, 1.0);
+    WorldPos = aPos;
+}
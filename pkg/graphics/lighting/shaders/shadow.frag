@@ -0,0 +1,9 @@
+#version 410 core
+
+out vec4 FragColor;
+
+void main()
+{
+    // Only the stencil is written
+    FragColor = vec4(0.0);
+}
@@ -0,0 +1,12 @@
+#version 410 core
+
+// W is 0 for vertices extruded away from the light, placing them at
+// infinity so shadows never end inside the light's radius
+layout(location = 0) in vec3 aPos;
+
+uniform mat4 view;
+uniform mat4 projection;
+
+void main(){
+    gl_Position = projection * view * vec4(aPos.xy, 0.0, aPos.z);
+}
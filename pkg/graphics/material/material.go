@@ -0,0 +1,183 @@
+package material
+
+import (
+	"github.com/go-gl/gl/all-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/shader"
+	"github.com/lunararch/helios/pkg/graphics/texture"
+)
+
+// BlendMode decides how drawn pixels combine with what is already there.
+type BlendMode int
+
+const (
+	BlendAlpha         BlendMode = iota // Standard transparency
+	BlendAdditive                       // Brightens, for glows, fire and sparks
+	BlendMultiply                       // Darkens, for shadows and tinting; transparent pixels leave the scene as is
+	BlendPremultiplied                  // Transparency for colors already multiplied by alpha
+)
+
+// Apply sets the GL blend function for the mode.
+func (m BlendMode) Apply() {
+	switch m {
+	case BlendAdditive:
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE)
+	case BlendMultiply:
+		// The shader premultiplies alpha, so src*a*dst + dst*(1-a) blends
+		// towards white where the sprite is transparent
+		gl.BlendFunc(gl.DST_COLOR, gl.ONE_MINUS_SRC_ALPHA)
+	case BlendPremultiplied:
+		gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	default:
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	}
+}
+
+var blendModeNames = map[string]BlendMode{
+	"alpha":         BlendAlpha,
+	"additive":      BlendAdditive,
+	"multiply":      BlendMultiply,
+	"premultiplied": BlendPremultiplied,
+}
+
+func BlendModeFromName(name string) (BlendMode, bool) {
+	mode, exists := blendModeNames[name]
+	return mode, exists
+}
+
+type materialTexture struct {
+	name    string
+	texture *texture.Texture
+}
+
+var nextMaterialID uint32
+
+// Material is a shader with its uniform values, extra textures and blend
+// mode. Sprites sharing a material batch together; the batch only breaks a
+// draw call when the material changes.
+//
+// The shader must use the sprite batch vertex layout and sampler array:
+// reuse assets/shaders/batch.vert and #include "include/sprite.glsl" in the
+// fragment shader, passing its final color through spriteOutput so multiply
+// blending gets the premultiplied alpha it needs. A nil shader keeps the batch's default one, so a
+// material can change just the blend mode.
+type Material struct {
+	Name   string
+	Shader *shader.Shader
+	Blend  BlendMode
+
+	id       uint32
+	uniforms map[string]any
+	textures []materialTexture
+}
+
+func New(name string, program *shader.Shader) *Material {
+	nextMaterialID++
+	return &Material{
+		Name:     name,
+		Shader:   program,
+		Blend:    BlendAlpha,
+		id:       nextMaterialID,
+		uniforms: make(map[string]any),
+		textures: make([]materialTexture, 0),
+	}
+}
+
+// GetID returns a number unique to the material, used to group draws.
+func (m *Material) GetID() uint32 {
+	if m == nil {
+		return 0
+	}
+	return m.id
+}
+
+func (m *Material) SetInt(name string, value int32) {
+	m.uniforms[name] = value
+}
+
+func (m *Material) SetFloat(name string, value float32) {
+	m.uniforms[name] = value
+}
+
+func (m *Material) SetBool(name string, value bool) {
+	m.uniforms[name] = value
+}
+
+func (m *Material) SetVec2(name string, value mgl32.Vec2) {
+	m.uniforms[name] = value
+}
+
+func (m *Material) SetVec3(name string, value mgl32.Vec3) {
+	m.uniforms[name] = value
+}
+
+func (m *Material) SetVec4(name string, value mgl32.Vec4) {
+	m.uniforms[name] = value
+}
+
+func (m *Material) SetMat4(name string, value mgl32.Mat4) {
+	m.uniforms[name] = value
+}
+
+func (m *Material) GetUniform(name string) (any, bool) {
+	value, exists := m.uniforms[name]
+	return value, exists
+}
+
+// SetTexture binds an extra texture, such as a mask or noise texture, to a
+// sampler uniform. Extra textures use the units after the batch's sprite
+// texture slots.
+func (m *Material) SetTexture(name string, tex *texture.Texture) {
+	for i, entry := range m.textures {
+		if entry.name == name {
+			m.textures[i].texture = tex
+			return
+		}
+	}
+	m.textures = append(m.textures, materialTexture{name: name, texture: tex})
+}
+
+func (m *Material) GetTexture(name string) *texture.Texture {
+	for _, entry := range m.textures {
+		if entry.name == name {
+			return entry.texture
+		}
+	}
+	return nil
+}
+
+// Apply makes program current with the material's uniforms, textures and
+// blend mode. Extra textures are bound from firstUnit on.
+func (m *Material) Apply(program *shader.Shader, firstUnit uint32) {
+	program.Use()
+
+	for name, value := range m.uniforms {
+		switch v := value.(type) {
+		case int32:
+			program.SetInt(name, v)
+		case float32:
+			program.SetFloat(name, v)
+		case bool:
+			program.SetBool(name, v)
+		case mgl32.Vec2:
+			program.SetVec2(name, v)
+		case mgl32.Vec3:
+			program.SetVec3(name, v)
+		case mgl32.Vec4:
+			program.SetVec4(name, v)
+		case mgl32.Mat4:
+			program.SetMat4(name, v)
+		}
+	}
+	program.SetBool("premultiplyAlpha", m.Blend == BlendMultiply)
+
+	for i, entry := range m.textures {
+		unit := firstUnit + uint32(i)
+		if entry.texture != nil {
+			entry.texture.Bind(unit)
+		}
+		program.SetInt(entry.name, int32(unit))
+	}
+
+	m.Blend.Apply()
+}
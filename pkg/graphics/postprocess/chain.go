@@ -0,0 +1,226 @@
+package postprocess
+
+import (
+	"github.com/go-gl/gl/all-core/gl"
+	"github.com/lunararch/helios/pkg/graphics/shader"
+	"github.com/lunararch/helios/pkg/graphics/texture"
+)
+
+type chainEntry struct {
+	effect  Effect
+	enabled bool
+}
+
+// Chain renders a scene offscreen, runs it through a list of effects and
+// draws the result to the screen. Scenes own their chain, so each scene can
+// configure its own effects:
+//
+//	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+//	chain.Begin()
+//	// draw the scene as usual
+//	chain.End()
+//
+// With an internal resolution set, the scene is rendered at that size and
+// scaled up by the largest whole factor that fits the window, with nearest
+// filtering, which keeps pixel art crisp. The camera size should match the
+// internal resolution so the world is rasterised at that size.
+type Chain struct {
+	entries []*chainEntry
+
+	scene    *texture.RenderTarget
+	pingPong [2]*texture.RenderTarget
+	blit     *shader.Shader
+
+	internalWidth  int32 // 0 follows the window size
+	internalHeight int32
+	width, height  int32 // Current size of the offscreen targets
+	output         [4]int32
+	prevFBO        int32
+	active         bool // False between Begin and End when there was nothing to render into, e.g. a minimized window
+}
+
+func NewChain() (*Chain, error) {
+	blit, err := loadShader("copy.frag")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Chain{
+		entries: make([]*chainEntry, 0),
+		blit:    blit,
+	}, nil
+}
+
+// Add appends an effect; effects run in the order they were added.
+func (c *Chain) Add(effect Effect) error {
+	if c.width > 0 {
+		if err := effect.Resize(c.width, c.height); err != nil {
+			return err
+		}
+	}
+	c.entries = append(c.entries, &chainEntry{effect: effect, enabled: true})
+	return nil
+}
+
+func (c *Chain) Remove(effect Effect) {
+	for i, entry := range c.entries {
+		if entry.effect == effect {
+			c.entries = append(c.entries[:i], c.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *Chain) SetEnabled(effect Effect, enabled bool) {
+	for _, entry := range c.entries {
+		if entry.effect == effect {
+			entry.enabled = enabled
+		}
+	}
+}
+
+func (c *Chain) IsEnabled(effect Effect) bool {
+	for _, entry := range c.entries {
+		if entry.effect == effect {
+			return entry.enabled
+		}
+	}
+	return false
+}
+
+// SetInternalResolution renders at a fixed size and integer-scales it to the
+// window. Zero width or height follows the window size again.
+func (c *Chain) SetInternalResolution(width, height int32) {
+	c.internalWidth, c.internalHeight = width, height
+	c.width, c.height = 0, 0 // Recreate the targets with the matching filter
+}
+
+func (c *Chain) GetInternalResolution() (int32, int32) {
+	return c.internalWidth, c.internalHeight
+}
+
+func (c *Chain) isPixelPerfect() bool {
+	return c.internalWidth > 0 && c.internalHeight > 0
+}
+
+// Begin redirects drawing into the chain. The current viewport becomes the
+// area the final image is drawn into.
+func (c *Chain) Begin() error {
+	gl.GetIntegerv(gl.VIEWPORT, &c.output[0])
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &c.prevFBO)
+
+	width, height := c.output[2], c.output[3]
+	if c.isPixelPerfect() {
+		width, height = c.internalWidth, c.internalHeight
+	}
+	if err := c.resize(width, height); err != nil {
+		return err
+	}
+
+	c.active = c.scene != nil && width > 0 && height > 0
+	if !c.active {
+		return nil
+	}
+
+	c.scene.Bind()
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	return nil
+}
+
+// End runs the enabled effects and draws the result into the framebuffer
+// and viewport that were current at Begin.
+func (c *Chain) End() {
+	if !c.active {
+		return
+	}
+	c.active = false
+	c.scene.Unbind()
+
+	depthTest := gl.IsEnabled(gl.DEPTH_TEST)
+	blend := gl.IsEnabled(gl.BLEND)
+	gl.Disable(gl.DEPTH_TEST)
+	gl.Disable(gl.BLEND)
+
+	source := c.scene.Texture
+	next := 0
+	for _, entry := range c.entries {
+		if !entry.enabled {
+			continue
+		}
+		destination := c.pingPong[next]
+		entry.effect.Apply(source, destination)
+		source = destination.Texture
+		next = 1 - next
+	}
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(c.prevFBO))
+	x, y, width, height := c.outputRect()
+	gl.Viewport(x, y, width, height)
+	RunPass(c.blit, source, nil)
+	gl.Viewport(c.output[0], c.output[1], c.output[2], c.output[3])
+
+	if depthTest {
+		gl.Enable(gl.DEPTH_TEST)
+	}
+	if blend {
+		gl.Enable(gl.BLEND)
+	}
+}
+
+// outputRect centers the image in the output viewport, scaled by a whole
+// number in pixel-perfect mode.
+func (c *Chain) outputRect() (x, y, width, height int32) {
+	if !c.isPixelPerfect() {
+		return c.output[0], c.output[1], c.output[2], c.output[3]
+	}
+
+	scale := max(1, min(c.output[2]/c.internalWidth, c.output[3]/c.internalHeight))
+	width, height = c.internalWidth*scale, c.internalHeight*scale
+	x = c.output[0] + (c.output[2]-width)/2
+	y = c.output[1] + (c.output[3]-height)/2
+	return x, y, width, height
+}
+
+func (c *Chain) resize(width, height int32) error {
+	if width <= 0 || height <= 0 || (width == c.width && height == c.height) {
+		return nil
+	}
+
+	filter := texture.FilterLinear
+	if c.isPixelPerfect() {
+		filter = texture.FilterNearest
+	}
+
+	for _, target := range []**texture.RenderTarget{&c.scene, &c.pingPong[0], &c.pingPong[1]} {
+		if *target != nil {
+			(*target).Delete()
+		}
+		created, err := texture.NewRenderTarget(width, height, filter)
+		if err != nil {
+			return err
+		}
+		*target = created
+	}
+
+	for _, entry := range c.entries {
+		if err := entry.effect.Resize(width, height); err != nil {
+			return err
+		}
+	}
+
+	c.width, c.height = width, height
+	return nil
+}
+
+// Delete frees the chain's targets and every effect added to it.
+func (c *Chain) Delete() {
+	for _, target := range []*texture.RenderTarget{c.scene, c.pingPong[0], c.pingPong[1]} {
+		if target != nil {
+			target.Delete()
+		}
+	}
+	for _, entry := range c.entries {
+		entry.effect.Delete()
+	}
+	c.blit.Delete()
+}
@@ -0,0 +1,289 @@
+package postprocess
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/shader"
+	"github.com/lunararch/helios/pkg/graphics/texture"
+)
+
+// Blur is a separable Gaussian blur, run horizontally then vertically.
+type Blur struct {
+	Radius     float32 // Spread in texels
+	Iterations int     // More iterations widen the blur without banding
+
+	shader       *shader.Shader
+	intermediate *texture.RenderTarget
+}
+
+func NewBlur(radius float32) (*Blur, error) {
+	program, err := loadShader("blur.frag")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Blur{
+		Radius:     radius,
+		Iterations: 1,
+		shader:     program,
+	}, nil
+}
+
+func (b *Blur) Resize(width, height int32) error {
+	if b.intermediate == nil {
+		target, err := texture.NewRenderTarget(width, height, texture.FilterLinear)
+		b.intermediate = target
+		return err
+	}
+	return b.intermediate.Resize(width, height)
+}
+
+func (b *Blur) Apply(source *texture.Texture, destination *texture.RenderTarget) {
+	b.blur(source, destination, b.Radius, max(1, b.Iterations))
+}
+
+// blur writes into destination, using it as scratch space between iterations.
+func (b *Blur) blur(source *texture.Texture, destination *texture.RenderTarget, radius float32, iterations int) {
+	width, height := float32(source.Width), float32(source.Height)
+
+	for i := 0; i < iterations; i++ {
+		b.shader.Use()
+		b.shader.SetVec2("direction", mgl32.Vec2{radius / width, 0})
+		RunPass(b.shader, source, b.intermediate)
+
+		b.shader.Use()
+		b.shader.SetVec2("direction", mgl32.Vec2{0, radius / height})
+		RunPass(b.shader, b.intermediate.Texture, destination)
+
+		source = destination.Texture
+	}
+}
+
+func (b *Blur) Delete() {
+	b.shader.Delete()
+	if b.intermediate != nil {
+		b.intermediate.Delete()
+	}
+}
+
+// Bloom makes bright pixels glow: it extracts everything above Threshold at
+// half resolution, blurs it and adds it back onto the scene.
+type Bloom struct {
+	Threshold  float32
+	SoftKnee   float32 // 0 is a hard cut at Threshold, 1 fades in from half of it
+	Intensity  float32
+	Radius     float32
+	Iterations int
+
+	thresholdShader *shader.Shader
+	combineShader   *shader.Shader
+	blur            *Blur
+	bright          *texture.RenderTarget
+	blurred         *texture.RenderTarget
+}
+
+func NewBloom(threshold, intensity float32) (*Bloom, error) {
+	thresholdShader, err := loadShader("threshold.frag")
+	if err != nil {
+		return nil, err
+	}
+
+	combineShader, err := loadShader("bloom.frag")
+	if err != nil {
+		thresholdShader.Delete()
+		return nil, err
+	}
+	combineShader.SetInt("bloomTexture", 1)
+
+	blur, err := NewBlur(1.0)
+	if err != nil {
+		thresholdShader.Delete()
+		combineShader.Delete()
+		return nil, err
+	}
+
+	return &Bloom{
+		Threshold:       threshold,
+		SoftKnee:        0.5,
+		Intensity:       intensity,
+		Radius:          1.5,
+		Iterations:      3,
+		thresholdShader: thresholdShader,
+		combineShader:   combineShader,
+		blur:            blur,
+	}, nil
+}
+
+func (b *Bloom) Resize(width, height int32) error {
+	width, height = max(1, width/2), max(1, height/2)
+
+	for _, target := range []**texture.RenderTarget{&b.bright, &b.blurred} {
+		if *target == nil {
+			created, err := texture.NewRenderTarget(width, height, texture.FilterLinear)
+			if err != nil {
+				return err
+			}
+			*target = created
+		} else if err := (*target).Resize(width, height); err != nil {
+			return err
+		}
+	}
+	return b.blur.Resize(width, height)
+}
+
+func (b *Bloom) Apply(source *texture.Texture, destination *texture.RenderTarget) {
+	b.thresholdShader.Use()
+	b.thresholdShader.SetFloat("threshold", b.Threshold)
+	b.thresholdShader.SetFloat("softKnee", b.SoftKnee)
+	RunPass(b.thresholdShader, source, b.bright)
+
+	b.blur.blur(b.bright.Texture, b.blurred, b.Radius, max(1, b.Iterations))
+
+	b.combineShader.Use()
+	b.combineShader.SetFloat("intensity", b.Intensity)
+	b.blurred.Texture.Bind(1)
+	RunPass(b.combineShader, source, destination)
+}
+
+func (b *Bloom) Delete() {
+	b.thresholdShader.Delete()
+	b.combineShader.Delete()
+	b.blur.Delete()
+	for _, target := range []*texture.RenderTarget{b.bright, b.blurred} {
+		if target != nil {
+			target.Delete()
+		}
+	}
+}
+
+// ColorGrading remaps colors through a lookup table laid out as a strip of
+// Size slices, each Size x Size, with blue choosing the slice (the common
+// 256x16 or 1024x32 format).
+type ColorGrading struct {
+	LUT       *texture.Texture
+	Size      int
+	Intensity float32
+
+	shader *shader.Shader
+}
+
+func NewColorGrading(lut *texture.Texture) (*ColorGrading, error) {
+	program, err := loadShader("lut.frag")
+	if err != nil {
+		return nil, err
+	}
+	program.SetInt("lutTexture", 1)
+
+	// Nearest-neighbour within a slice would band; the shader blends slices itself
+	lut.SetFilter(texture.FilterLinear)
+
+	return &ColorGrading{
+		LUT:       lut,
+		Size:      int(lut.Height),
+		Intensity: 1.0,
+		shader:    program,
+	}, nil
+}
+
+func (cg *ColorGrading) Resize(width, height int32) error {
+	return nil
+}
+
+func (cg *ColorGrading) Apply(source *texture.Texture, destination *texture.RenderTarget) {
+	cg.shader.Use()
+	cg.shader.SetFloat("lutSize", float32(cg.Size))
+	cg.shader.SetFloat("intensity", cg.Intensity)
+	cg.LUT.Bind(1)
+	RunPass(cg.shader, source, destination)
+}
+
+func (cg *ColorGrading) Delete() {
+	cg.shader.Delete()
+}
+
+// Vignette darkens the edges of the screen towards Color.
+type Vignette struct {
+	Color     mgl32.Vec4
+	Radius    float32 // Distance from the center where darkening is complete
+	Softness  float32 // Width of the fade inside Radius
+	Intensity float32
+
+	shader *shader.Shader
+	aspect float32
+}
+
+func NewVignette() (*Vignette, error) {
+	program, err := loadShader("vignette.frag")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Vignette{
+		Color:     mgl32.Vec4{0, 0, 0, 1},
+		Radius:    0.75,
+		Softness:  0.45,
+		Intensity: 1.0,
+		shader:    program,
+		aspect:    1.0,
+	}, nil
+}
+
+func (v *Vignette) Resize(width, height int32) error {
+	v.aspect = float32(width) / float32(height)
+	return nil
+}
+
+func (v *Vignette) Apply(source *texture.Texture, destination *texture.RenderTarget) {
+	v.shader.Use()
+	v.shader.SetVec4("color", v.Color)
+	v.shader.SetFloat("radius", v.Radius)
+	v.shader.SetFloat("softness", v.Softness)
+	v.shader.SetFloat("intensity", v.Intensity)
+	v.shader.SetFloat("aspect", v.aspect)
+	RunPass(v.shader, source, destination)
+}
+
+func (v *Vignette) Delete() {
+	v.shader.Delete()
+}
+
+// CRT imitates a curved tube display with scanlines and color fringing.
+type CRT struct {
+	Curvature           float32
+	ScanlineIntensity   float32
+	ChromaticAberration float32 // Channel offset in pixels
+
+	shader     *shader.Shader
+	resolution mgl32.Vec2
+}
+
+func NewCRT() (*CRT, error) {
+	program, err := loadShader("crt.frag")
+	if err != nil {
+		return nil, err
+	}
+
+	return &CRT{
+		Curvature:           0.15,
+		ScanlineIntensity:   0.35,
+		ChromaticAberration: 1.0,
+		shader:              program,
+	}, nil
+}
+
+func (c *CRT) Resize(width, height int32) error {
+	c.resolution = mgl32.Vec2{float32(width), float32(height)}
+	return nil
+}
+
+func (c *CRT) Apply(source *texture.Texture, destination *texture.RenderTarget) {
+	c.shader.Use()
+	c.shader.SetVec2("resolution", c.resolution)
+	c.shader.SetFloat("curvature", c.Curvature)
+	c.shader.SetFloat("scanlineIntensity", c.ScanlineIntensity)
+	c.shader.SetFloat("chromaticAberration", c.ChromaticAberration)
+	RunPass(c.shader, source, destination)
+}
+
+func (c *CRT) Delete() {
+	c.shader.Delete()
+}
@@ -0,0 +1,108 @@
+package postprocess
+
+import (
+	"embed"
+	"fmt"
+
+	"github.com/go-gl/gl/all-core/gl"
+	"github.com/lunararch/helios/pkg/graphics/shader"
+	"github.com/lunararch/helios/pkg/graphics/texture"
+)
+
+//go:embed shaders
+var shaderFiles embed.FS
+
+// Effect is one step of a post-processing chain. It reads the source texture
+// and draws the result into destination.
+type Effect interface {
+	Apply(source *texture.Texture, destination *texture.RenderTarget)
+	// Resize is called when the chain's internal resolution changes, so
+	// effects can size their intermediate targets.
+	Resize(width, height int32) error
+	Delete()
+}
+
+// loadShader compiles an embedded fragment shader with the full-screen
+// vertex shader.
+func loadShader(fragmentName string) (*shader.Shader, error) {
+	fragmentCode, err := shaderFiles.ReadFile("shaders/" + fragmentName)
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := NewPassShader(string(fragmentCode))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fragmentName, err)
+	}
+	return program, nil
+}
+
+// NewPassShader compiles a fragment shader with the full-screen vertex
+// shader, so effects outside this package can use RunPass. The shader gets
+// TexCoord as input and the source texture as screenTexture on unit 0.
+func NewPassShader(fragmentCode string) (*shader.Shader, error) {
+	vertexCode, err := shaderFiles.ReadFile("shaders/fullscreen.vert")
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := shader.NewFromSource(string(vertexCode), fragmentCode)
+	if err != nil {
+		return nil, err
+	}
+
+	program.Use()
+	program.SetInt("screenTexture", 0)
+	return program, nil
+}
+
+// fullscreenQuad is shared by every pass; it covers clip space with UVs
+// running bottom-up like framebuffer textures.
+var fullscreenQuad struct {
+	vao, vbo uint32
+}
+
+func drawFullscreenQuad() {
+	if fullscreenQuad.vao == 0 {
+		vertices := []float32{
+			// Position, texture coords
+			-1, -1, 0, 0,
+			1, -1, 1, 0,
+			-1, 1, 0, 1,
+			1, 1, 1, 1,
+		}
+
+		gl.GenVertexArrays(1, &fullscreenQuad.vao)
+		gl.GenBuffers(1, &fullscreenQuad.vbo)
+
+		gl.BindVertexArray(fullscreenQuad.vao)
+		gl.BindBuffer(gl.ARRAY_BUFFER, fullscreenQuad.vbo)
+		gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
+
+		gl.VertexAttribPointer(0, 2, gl.FLOAT, false, 4*4, gl.PtrOffset(0))
+		gl.EnableVertexAttribArray(0)
+
+		gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 4*4, gl.PtrOffset(2*4))
+		gl.EnableVertexAttribArray(1)
+
+		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	} else {
+		gl.BindVertexArray(fullscreenQuad.vao)
+	}
+
+	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+	gl.BindVertexArray(0)
+}
+
+// RunPass draws source through program into destination. A nil destination
+// draws into whatever framebuffer and viewport are current.
+func RunPass(program *shader.Shader, source *texture.Texture, destination *texture.RenderTarget) {
+	if destination != nil {
+		destination.Bind()
+		defer destination.Unbind()
+	}
+
+	program.Use()
+	source.Bind(0)
+	drawFullscreenQuad()
+}
@@ -0,0 +1,15 @@
+#version 410 core
+
+in vec2 TexCoord;
+out vec4 FragColor;
+
+uniform sampler2D screenTexture;
+uniform sampler2D bloomTexture;
+uniform float intensity;
+
+void main()
+{
+    vec4 scene = texture(screenTexture, TexCoord);
+    vec3 bloom = texture(bloomTexture, TexCoord).rgb;
+    FragColor = vec4(scene.rgb + bloom * intensity, scene.a);
+}
@@ -0,0 +1,21 @@
+#version 410 core
+
+in vec2 TexCoord;
+out vec4 FragColor;
+
+uniform sampler2D screenTexture;
+uniform vec2 direction; // One texel along the blur axis, scaled by the radius
+
+// 9-tap Gaussian using linear sampling between texels
+const float offsets[3] = float[](0.0, 1.3846153846, 3.2307692308);
+const float weights[3] = float[](0.2270270270, 0.3162162162, 0.0702702703);
+
+void main()
+{
+    vec4 color = texture(screenTexture, TexCoord) * weights[0];
+    for (int i = 1; i < 3; i++) {
+        color += texture(screenTexture, TexCoord + direction * offsets[i]) * weights[i];
+        color += texture(screenTexture, TexCoord - direction * offsets[i]) * weights[i];
+    }
+    FragColor = color;
+}
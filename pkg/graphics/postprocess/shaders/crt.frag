@@ -0,0 +1,42 @@
+#version 410 core
+
+in vec2 TexCoord;
+out vec4 FragColor;
+
+uniform sampler2D screenTexture;
+uniform vec2 resolution;
+uniform float curvature;
+uniform float scanlineIntensity;
+uniform float chromaticAberration;
+
+vec2 curve(vec2 uv)
+{
+    uv = uv * 2.0 - 1.0;
+    vec2 offset = abs(uv.yx) * curvature;
+    uv += uv * offset * offset;
+    return uv * 0.5 + 0.5;
+}
+
+void main()
+{
+    vec2 uv = curve(TexCoord);
+    if (uv.x < 0.0 || uv.x > 1.0 || uv.y < 0.0 || uv.y > 1.0) {
+        FragColor = vec4(0.0, 0.0, 0.0, 1.0);
+        return;
+    }
+
+    vec2 shift = vec2(chromaticAberration / resolution.x, 0.0);
+    vec3 color = vec3(
+        texture(screenTexture, uv + shift).r,
+        texture(screenTexture, uv).g,
+        texture(screenTexture, uv - shift).b
+    );
+
+    float scanline = sin(uv.y * resolution.y * 3.14159265);
+    color *= 1.0 - scanlineIntensity * (0.5 - 0.5 * scanline);
+
+    // Slight phosphor mask on alternating columns
+    color *= 1.0 - scanlineIntensity * 0.15 * step(0.5, fract(uv.x * resolution.x * 0.5));
+
+    FragColor = vec4(color, 1.0);
+}
@@ -0,0 +1,33 @@
+#version 410 core
+
+in vec2 TexCoord;
+out vec4 FragColor;
+
+uniform sampler2D screenTexture;
+uniform sampler2D lutTexture; // size*size by size strip, blue selects the slice
+uniform float lutSize;
+uniform float intensity;
+
+vec3 lookup(vec3 color)
+{
+    float maxIndex = lutSize - 1.0;
+    float slice = color.b * maxIndex;
+    float sliceLow = floor(slice);
+    float sliceHigh = min(sliceLow + 1.0, maxIndex);
+
+    // Sample texel centers so neighbouring slices don't bleed in
+    vec2 texel = vec2(1.0 / (lutSize * lutSize), 1.0 / lutSize);
+    vec2 inSlice = vec2(color.r * maxIndex * texel.x + texel.x * 0.5,
+                        color.g * maxIndex * texel.y + texel.y * 0.5);
+
+    vec3 low = texture(lutTexture, inSlice + vec2(sliceLow / lutSize, 0.0)).rgb;
+    vec3 high = texture(lutTexture, inSlice + vec2(sliceHigh / lutSize, 0.0)).rgb;
+    return mix(low, high, slice - sliceLow);
+}
+
+void main()
+{
+    vec4 color = texture(screenTexture, TexCoord);
+    vec3 graded = lookup(clamp(color.rgb, 0.0, 1.0));
+    FragColor = vec4(mix(color.rgb, graded, intensity), color.a);
+}
@@ -0,0 +1,22 @@
+#version 410 core
+
+in vec2 TexCoord;
+out vec4 FragColor;
+
+uniform sampler2D screenTexture;
+uniform float threshold;
+uniform float softKnee;
+
+void main()
+{
+    vec3 color = texture(screenTexture, TexCoord).rgb;
+    float brightness = max(color.r, max(color.g, color.b));
+
+    // Soft knee avoids a hard edge where pixels cross the threshold
+    float knee = threshold * softKnee;
+    float soft = clamp(brightness - threshold + knee, 0.0, 2.0 * knee);
+    soft = soft * soft / (4.0 * knee + 0.00001);
+    float contribution = max(soft, brightness - threshold) / max(brightness, 0.00001);
+
+    FragColor = vec4(color * contribution, 1.0);
+}
@@ -0,0 +1,22 @@
+#version 410 core
+
+in vec2 TexCoord;
+out vec4 FragColor;
+
+uniform sampler2D screenTexture;
+uniform vec4 color;
+uniform float radius;
+uniform float softness;
+uniform float intensity;
+uniform float aspect;
+
+void main()
+{
+    vec4 scene = texture(screenTexture, TexCoord);
+
+    vec2 fromCenter = TexCoord - 0.5;
+    fromCenter.x *= aspect;
+    float vignette = smoothstep(radius, radius - softness, length(fromCenter));
+
+    FragColor = vec4(mix(color.rgb, scene.rgb, mix(1.0, vignette, intensity * color.a)), scene.a);
+}
@@ -0,0 +1,263 @@
+package shader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sourceLine is one line of preprocessed GLSL and where it came from, so
+// driver errors can point at the right file.
+type sourceLine struct {
+	file string
+	line int
+	text string
+}
+
+const maxIncludeDepth = 32
+
+func splitSource(file, code string) []sourceLine {
+	texts := strings.Split(code, "\n")
+	lines := make([]sourceLine, len(texts))
+	for i, text := range texts {
+		lines[i] = sourceLine{file: file, line: i + 1, text: strings.TrimSuffix(text, "\r")}
+	}
+	return lines
+}
+
+func readSource(path string) ([]sourceLine, error) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return splitSource(path, string(code)), nil
+}
+
+func joinSource(lines []sourceLine) string {
+	var builder strings.Builder
+	for _, line := range lines {
+		builder.WriteString(line.text)
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// preprocessor expands #include directives and injects #defines for one
+// shader stage.
+type preprocessor struct {
+	defines  map[string]string
+	injected bool
+	included map[string]bool // Each file is included once per stage
+	stack    []string        // Files being expanded, to report include cycles
+	files    []string        // Every file read, for the hot reload watcher
+	output   []sourceLine
+}
+
+func newPreprocessor(defines map[string]string) *preprocessor {
+	return &preprocessor{
+		defines:  defines,
+		included: make(map[string]bool),
+		output:   make([]sourceLine, 0),
+	}
+}
+
+func (p *preprocessor) expand(lines []sourceLine) error {
+	for _, line := range lines {
+		directive := strings.TrimSpace(line.text)
+
+		if strings.HasPrefix(directive, "#include") {
+			name, err := parseInclude(directive)
+			if err != nil {
+				return fmt.Errorf("%s:%d: %w", line.file, line.line, err)
+			}
+			if err := p.include(filepath.Join(filepath.Dir(line.file), name), line); err != nil {
+				return err
+			}
+			continue
+		}
+
+		p.output = append(p.output, line)
+
+		// Defines must follow #version, which has to come first
+		if !p.injected && strings.HasPrefix(directive, "#version") {
+			p.injectDefines()
+		}
+	}
+	return nil
+}
+
+func (p *preprocessor) include(path string, from sourceLine) error {
+	for _, open := range p.stack {
+		if open == path {
+			return fmt.Errorf("%s:%d: include cycle through '%s'", from.file, from.line, path)
+		}
+	}
+	if p.included[path] {
+		return nil
+	}
+	if len(p.stack) >= maxIncludeDepth {
+		return fmt.Errorf("%s:%d: includes nested too deeply", from.file, from.line)
+	}
+
+	p.files = append(p.files, path)
+	lines, err := readSource(path)
+	if err != nil {
+		return fmt.Errorf("%s:%d: failed to include '%s': %w", from.file, from.line, path, err)
+	}
+
+	p.included[path] = true
+	p.stack = append(p.stack, path)
+	defer func() { p.stack = p.stack[:len(p.stack)-1] }()
+	return p.expand(lines)
+}
+
+// finish returns the expanded source. Without a #version the defines were
+// never injected, so they go at the top.
+func (p *preprocessor) finish() []sourceLine {
+	if p.injected {
+		return p.output
+	}
+	body := p.output
+	p.output = make([]sourceLine, 0, len(p.defines)+len(body))
+	p.injectDefines()
+	return append(p.output, body...)
+}
+
+func (p *preprocessor) injectDefines() {
+	p.injected = true
+
+	names := make([]string, 0, len(p.defines))
+	for name := range p.defines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		p.output = append(p.output, sourceLine{
+			file: "<defines>",
+			line: i + 1,
+			text: strings.TrimSpace("#define " + name + " " + p.defines[name]),
+		})
+	}
+}
+
+// parseInclude reads the file name from `#include "name"` or `#include <name>`.
+func parseInclude(directive string) (string, error) {
+	argument := strings.TrimSpace(strings.TrimPrefix(directive, "#include"))
+	if len(argument) >= 2 {
+		if (argument[0] == '"' && argument[len(argument)-1] == '"') ||
+			(argument[0] == '<' && argument[len(argument)-1] == '>') {
+			return argument[1 : len(argument)-1], nil
+		}
+	}
+	return "", fmt.Errorf("malformed #include, expected a quoted file name")
+}
+
+// preprocessFile loads a shader stage from a file.
+func preprocessFile(path string, defines map[string]string) ([]sourceLine, []string, error) {
+	p := newPreprocessor(defines)
+	p.files = append(p.files, path)
+
+	lines, err := readSource(path)
+	if err != nil {
+		return nil, p.files, fmt.Errorf("failed to read shader '%s': %w", path, err)
+	}
+
+	p.included[path] = true
+	p.stack = append(p.stack, path)
+	if err := p.expand(lines); err != nil {
+		return nil, p.files, err
+	}
+	return p.finish(), p.files, nil
+}
+
+// preprocessSingleFile splits a combined shader into its stages. Sections
+// start with `#type vertex` or `#type fragment`; lines before the first
+// section, such as #version and shared includes, start every stage.
+func preprocessSingleFile(path string, defines map[string]string) (vertex, fragment []sourceLine, files []string, err error) {
+	files = []string{path}
+
+	lines, err := readSource(path)
+	if err != nil {
+		return nil, nil, files, fmt.Errorf("failed to read shader '%s': %w", path, err)
+	}
+
+	var common []sourceLine
+	sections := make(map[string][]sourceLine)
+	current := ""
+
+	for _, line := range lines {
+		directive := strings.TrimSpace(line.text)
+		if strings.HasPrefix(directive, "#type") {
+			current = strings.TrimSpace(strings.TrimPrefix(directive, "#type"))
+			if current != "vertex" && current != "fragment" {
+				return nil, nil, files, fmt.Errorf("%s:%d: unknown shader type '%s'", line.file, line.line, current)
+			}
+			if _, exists := sections[current]; exists {
+				return nil, nil, files, fmt.Errorf("%s:%d: duplicate '%s' section", line.file, line.line, current)
+			}
+			sections[current] = append([]sourceLine{}, common...)
+			continue
+		}
+
+		if current == "" {
+			common = append(common, line)
+		} else {
+			sections[current] = append(sections[current], line)
+		}
+	}
+
+	for _, stage := range []string{"vertex", "fragment"} {
+		if _, exists := sections[stage]; !exists {
+			return nil, nil, files, fmt.Errorf("shader '%s' has no '#type %s' section", path, stage)
+		}
+	}
+
+	stages := make([][]sourceLine, 2)
+	for i, stage := range []string{"vertex", "fragment"} {
+		p := newPreprocessor(defines)
+		p.included[path] = true
+		p.stack = append(p.stack, path)
+		err := p.expand(sections[stage])
+		files = append(files, p.files...)
+		if err != nil {
+			return nil, nil, files, err
+		}
+		stages[i] = p.finish()
+	}
+	return stages[0], stages[1], files, nil
+}
+
+// logLocation matches the source position drivers put at the start of each
+// message: "0:12(5):" (Mesa), "0(12) :" (NVIDIA) and "ERROR: 0:12:" (AMD, Intel).
+var logLocation = regexp.MustCompile(`^((?:ERROR|WARNING): )?\d+(?::(\d+)|\((\d+)\))(?:\(\d+\))?\s*:`)
+
+// mapLog rewrites driver line numbers in an info log to file:line.
+func mapLog(log string, lines []sourceLine) string {
+	log = strings.TrimRight(log, "\x00\n ")
+	messages := strings.Split(log, "\n")
+
+	for i, message := range messages {
+		match := logLocation.FindStringSubmatch(message)
+		if match == nil {
+			continue
+		}
+
+		number := match[2]
+		if number == "" {
+			number = match[3]
+		}
+		line, err := strconv.Atoi(number)
+		if err != nil || line < 1 || line > len(lines) {
+			continue
+		}
+
+		source := lines[line-1]
+		messages[i] = fmt.Sprintf("%s%s:%d:%s", match[1], source.file, source.line, message[len(match[0]):])
+	}
+	return strings.Join(messages, "\n")
+}
@@ -0,0 +1,42 @@
+package shader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefines(t *testing.T) {
+	defines := map[string]string{"LIGHTS": "4", "SHADOWS": ""}
+
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"after version", "#version 410 core\nvoid main() {}", "#version 410 core\n#define LIGHTS 4\n#define SHADOWS\nvoid main() {}\n"},
+		{"after leading comments", "// Lit\n#version 410 core\nvoid main() {}", "// Lit\n#version 410 core\n#define LIGHTS 4\n#define SHADOWS\nvoid main() {}\n"},
+		{"without version", "void main() {}", "#define LIGHTS 4\n#define SHADOWS\nvoid main() {}\n"},
+		{"from an include", "#include \"version.glsl\"\nvoid main() {}", "#version 410 core\n#define LIGHTS 4\n#define SHADOWS\nvoid main() {}\n"},
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "version.glsl"), []byte("#version 410 core"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(dir, "shader.frag")
+		if err := os.WriteFile(path, []byte(test.source), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		lines, _, err := preprocessFile(path, defines)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got := joinSource(lines); got != test.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, got, test.want)
+		}
+	}
+}
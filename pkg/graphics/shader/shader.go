@@ -4,34 +4,151 @@ import (
 	"fmt"
 	"github.com/go-gl/gl/all-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
-	"os"
 	"strings"
 )
 
 type Shader struct {
-	ID uint32
+	ID        uint32
+	locations map[string]int32 // Uniform locations, looked up once per name
+
+	// Where a file-based program came from, so it can be rebuilt by Reload
+	vertexPath   string
+	fragmentPath string
+	path         string // Single-file shader
+	defines      map[string]string
+	files        []string // Every file the last build read, includes too
+	onReload     func(*Shader)
 }
 
+// New loads a program from a vertex and a fragment shader file. Both may use
+// #include, resolved relative to the including file.
 func New(vertexPath, fragmentPath string) (*Shader, error) {
-	vertexCode, err := os.ReadFile(vertexPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read vertex shader: %w", err)
+	return NewWithDefines(vertexPath, fragmentPath, nil)
+}
+
+// NewWithDefines loads a variant of a program, with each define injected
+// after #version as `#define NAME VALUE`.
+func NewWithDefines(vertexPath, fragmentPath string, defines map[string]string) (*Shader, error) {
+	s := &Shader{
+		vertexPath:   vertexPath,
+		fragmentPath: fragmentPath,
+		defines:      defines,
 	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
 
-	fragmentCode, err := os.ReadFile(fragmentPath)
+// Load loads a program from a single file with `#type vertex` and
+// `#type fragment` sections. Anything before the first section, usually
+// #version and shared includes, is part of both stages.
+func Load(path string, defines map[string]string) (*Shader, error) {
+	s := &Shader{
+		path:    path,
+		defines: defines,
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Shader) load() error {
+	id, err := s.build()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read fragment shader: %w", err)
+		return err
 	}
 
-	vertexShader, err := compileShader(string(vertexCode), gl.VERTEX_SHADER)
+	s.ID = id
+	s.locations = make(map[string]int32)
+	if hotReload != nil {
+		hotReload.Add(s)
+	}
+	return nil
+}
+
+// build preprocesses and links the program's files. The files read are only
+// kept once every stage preprocesses, so a failed reload keeps watching the
+// previous set.
+func (s *Shader) build() (uint32, error) {
+	var vertex, fragment []sourceLine
+	var files []string
+	var err error
+
+	if s.path != "" {
+		vertex, fragment, files, err = preprocessSingleFile(s.path, s.defines)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		var vertexFiles, fragmentFiles []string
+		if vertex, vertexFiles, err = preprocessFile(s.vertexPath, s.defines); err != nil {
+			return 0, err
+		}
+		if fragment, fragmentFiles, err = preprocessFile(s.fragmentPath, s.defines); err != nil {
+			return 0, err
+		}
+		files = append(vertexFiles, fragmentFiles...)
+	}
+
+	s.files = files
+	return linkProgram(vertex, fragment)
+}
+
+// NewFromSource compiles and links a program from GLSL source held in memory.
+func NewFromSource(vertexCode, fragmentCode string) (*Shader, error) {
+	id, err := linkProgram(splitSource("vertex", vertexCode), splitSource("fragment", fragmentCode))
 	if err != nil {
 		return nil, err
 	}
+	return &Shader{ID: id, locations: make(map[string]int32)}, nil
+}
+
+// Reload rebuilds a file-based program from disk and swaps it in place, so
+// everything holding the Shader picks up the change. On failure the old
+// program stays in use. Uniforms must be set again; see SetOnReload.
+func (s *Shader) Reload() error {
+	if s.path == "" && s.vertexPath == "" {
+		return fmt.Errorf("shader was not loaded from files")
+	}
+
+	id, err := s.build()
+	if err != nil {
+		return err
+	}
+
+	gl.DeleteProgram(s.ID)
+	s.ID = id
+	s.locations = make(map[string]int32)
+
+	if s.onReload != nil {
+		s.onReload(s)
+	}
+	return nil
+}
+
+// SetOnReload registers a callback run after a successful Reload, to set
+// uniforms that are normally set once after loading.
+func (s *Shader) SetOnReload(callback func(*Shader)) {
+	s.onReload = callback
+}
+
+// GetFiles returns every file the program was built from.
+func (s *Shader) GetFiles() []string {
+	return s.files
+}
+
+func linkProgram(vertex, fragment []sourceLine) (uint32, error) {
+	vertexShader, err := compileShader(vertex, gl.VERTEX_SHADER)
+	if err != nil {
+		return 0, err
+	}
 	defer gl.DeleteShader(vertexShader)
 
-	fragmentShader, err := compileShader(string(fragmentCode), gl.FRAGMENT_SHADER)
+	fragmentShader, err := compileShader(fragment, gl.FRAGMENT_SHADER)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 	defer gl.DeleteShader(fragmentShader)
 
@@ -49,9 +166,10 @@ func New(vertexPath, fragmentPath string) (*Shader, error) {
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetProgramInfoLog(programID, logLength, nil, gl.Str(log))
 
-		return nil, fmt.Errorf("failed to link shader program: %v", log)
+		gl.DeleteProgram(programID)
+		return 0, fmt.Errorf("failed to link shader program: %v", strings.TrimRight(log, "\x00"))
 	}
-	return &Shader{ID: programID}, nil
+	return programID, nil
 }
 
 func (s *Shader) Use() {
@@ -60,6 +178,30 @@ func (s *Shader) Use() {
 
 func (s *Shader) Delete() {
 	gl.DeleteProgram(s.ID)
+	if hotReload != nil {
+		hotReload.Remove(s)
+	}
+}
+
+// GetUniformLocation returns the location of a uniform, or -1 if the program
+// has no active uniform by that name. Locations are cached, so setting
+// uniforms by name every frame stays cheap.
+func (s *Shader) GetUniformLocation(name string) int32 {
+	if location, exists := s.locations[name]; exists {
+		return location
+	}
+
+	if s.locations == nil {
+		s.locations = make(map[string]int32)
+	}
+	location := gl.GetUniformLocation(s.ID, gl.Str(name+"\x00"))
+	s.locations[name] = location
+	return location
+}
+
+// HasUniform reports whether the program uses a uniform.
+func (s *Shader) HasUniform(name string) bool {
+	return s.GetUniformLocation(name) >= 0
 }
 
 func (s *Shader) SetBool(name string, value bool) {
@@ -67,39 +209,43 @@ func (s *Shader) SetBool(name string, value bool) {
 	if value {
 		intValue = 1
 	}
-	gl.Uniform1i(gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")), intValue)
+	gl.Uniform1i(s.GetUniformLocation(name), intValue)
 }
 
 func (s *Shader) SetInt(name string, value int32) {
-	gl.Uniform1i(gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")), value)
+	gl.Uniform1i(s.GetUniformLocation(name), value)
 }
 
 func (s *Shader) SetFloat(name string, value float32) {
-	gl.Uniform1f(gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")), value)
+	gl.Uniform1f(s.GetUniformLocation(name), value)
 }
 
 func (s *Shader) SetVec2(name string, value mgl32.Vec2) {
-	gl.Uniform2fv(gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")), 1, &value[0])
+	gl.Uniform2fv(s.GetUniformLocation(name), 1, &value[0])
 }
 
 func (s *Shader) SetVec3(name string, value mgl32.Vec3) {
-	gl.Uniform3fv(gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")), 1, &value[0])
+	gl.Uniform3fv(s.GetUniformLocation(name), 1, &value[0])
 }
 
 func (s *Shader) SetVec4(name string, value mgl32.Vec4) {
-	gl.Uniform4fv(gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")), 1, &value[0])
+	gl.Uniform4fv(s.GetUniformLocation(name), 1, &value[0])
+}
+
+func (s *Shader) SetMat3(name string, value mgl32.Mat3) {
+	gl.UniformMatrix3fv(s.GetUniformLocation(name), 1, false, &value[0])
 }
 
 func (s *Shader) SetMat4(name string, value mgl32.Mat4) {
 	gl.UniformMatrix4fv(
-		gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")),
+		s.GetUniformLocation(name),
 		1, false, &value[0],
 	)
 }
 
-func compileShader(source string, shaderType uint32) (uint32, error) {
+func compileShader(source []sourceLine, shaderType uint32) (uint32, error) {
 	shader := gl.CreateShader(shaderType)
-	csources, free := gl.Strs(source + "\x00")
+	csources, free := gl.Strs(joinSource(source) + "\x00")
 	gl.ShaderSource(shader, 1, csources, nil)
 	free()
 	gl.CompileShader(shader)
@@ -112,8 +258,13 @@ func compileShader(source string, shaderType uint32) (uint32, error) {
 
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
+		gl.DeleteShader(shader)
 
-		return 0, fmt.Errorf("failed to compile shader: %v", log)
+		file := "shader"
+		if len(source) > 0 {
+			file = source[0].file
+		}
+		return 0, fmt.Errorf("failed to compile '%s':\n%s", file, mapLog(log, source))
 	}
 
 	return shader, nil
@@ -0,0 +1,62 @@
+package shader
+
+import (
+	"unsafe"
+
+	"github.com/go-gl/gl/all-core/gl"
+)
+
+// UniformBlock is a uniform buffer holding one value of T, shared by every
+// program that binds its block to the same binding point. T must follow the
+// block's std140 layout: float, vec4 and mat4 members map directly, while
+// vec2 needs 8-byte alignment and vec3 a float of padding after it.
+type UniformBlock[T any] struct {
+	ubo     uint32
+	binding uint32
+}
+
+func NewUniformBlock[T any](binding uint32) *UniformBlock[T] {
+	block := &UniformBlock[T]{binding: binding}
+
+	var zero T
+	gl.GenBuffers(1, &block.ubo)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, block.ubo)
+	gl.BufferData(gl.UNIFORM_BUFFER, int(unsafe.Sizeof(zero)), nil, gl.DYNAMIC_DRAW)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, 0)
+
+	block.Bind()
+	return block
+}
+
+// Set uploads a new value for every program using the block.
+func (b *UniformBlock[T]) Set(value T) {
+	gl.BindBuffer(gl.UNIFORM_BUFFER, b.ubo)
+	gl.BufferSubData(gl.UNIFORM_BUFFER, 0, int(unsafe.Sizeof(value)), unsafe.Pointer(&value))
+	gl.BindBuffer(gl.UNIFORM_BUFFER, 0)
+}
+
+// Bind attaches the buffer to its binding point again, for when several
+// blocks share one.
+func (b *UniformBlock[T]) Bind() {
+	gl.BindBufferBase(gl.UNIFORM_BUFFER, b.binding, b.ubo)
+}
+
+func (b *UniformBlock[T]) GetBinding() uint32 {
+	return b.binding
+}
+
+func (b *UniformBlock[T]) Delete() {
+	gl.DeleteBuffers(1, &b.ubo)
+}
+
+// BindUniformBlock connects the program's named uniform block to a binding
+// point. It returns false if the program has no such block.
+func (s *Shader) BindUniformBlock(name string, binding uint32) bool {
+	index := gl.GetUniformBlockIndex(s.ID, gl.Str(name+"\x00"))
+	if index == gl.INVALID_INDEX {
+		return false
+	}
+
+	gl.UniformBlockBinding(s.ID, index, binding)
+	return true
+}
@@ -0,0 +1,105 @@
+package shader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Watcher rebuilds shaders when any file they were built from changes on
+// disk. It polls modification times from Update, on the GL thread, so
+// reloads happen between frames without extra threads or dependencies.
+type Watcher struct {
+	Interval time.Duration // Minimum time between checks
+
+	shaders   []*Shader
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+}
+
+func NewWatcher() *Watcher {
+	return &Watcher{
+		Interval: 500 * time.Millisecond,
+		shaders:  make([]*Shader, 0),
+		modTimes: make(map[string]time.Time),
+	}
+}
+
+// hotReload watches every file-based shader once EnableHotReload is called.
+var hotReload *Watcher
+
+// EnableHotReload watches every shader loaded from files from now on; call
+// it before loading scenes, and Update on the returned watcher once a frame.
+func EnableHotReload() *Watcher {
+	if hotReload == nil {
+		hotReload = NewWatcher()
+	}
+	return hotReload
+}
+
+func (w *Watcher) Add(s *Shader) {
+	for _, existing := range w.shaders {
+		if existing == s {
+			return
+		}
+	}
+	w.shaders = append(w.shaders, s)
+	w.stamp(s)
+}
+
+func (w *Watcher) Remove(s *Shader) {
+	for i, existing := range w.shaders {
+		if existing == s {
+			w.shaders = append(w.shaders[:i], w.shaders[i+1:]...)
+			return
+		}
+	}
+}
+
+// stamp records the current modification times of a shader's files.
+func (w *Watcher) stamp(s *Shader) {
+	for _, file := range s.files {
+		if info, err := os.Stat(file); err == nil {
+			w.modTimes[file] = info.ModTime()
+		}
+	}
+}
+
+func (w *Watcher) changed(s *Shader) bool {
+	for _, file := range s.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue // Editors often replace files by deleting them first
+		}
+		if !info.ModTime().Equal(w.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Update reloads every shader whose files changed since the last check. A
+// shader that fails to rebuild keeps its old program and is retried on the
+// next change; the errors are returned together.
+func (w *Watcher) Update() error {
+	now := time.Now()
+	if now.Sub(w.lastCheck) < w.Interval {
+		return nil
+	}
+	w.lastCheck = now
+
+	var errs []error
+	for _, s := range w.shaders {
+		if !w.changed(s) {
+			continue
+		}
+
+		err := s.Reload()
+		w.stamp(s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reloading shader: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,205 @@
+package skeleton
+
+import (
+	"math"
+	"sort"
+
+	"github.com/lunararch/helios/pkg/graphics/animation"
+)
+
+// Animation keyframes bones and slots. Bone curves hold offsets from the setup
+// pose (rotation and translation are added, scale is multiplied), matching
+// how Spine stores its timelines.
+type Animation struct {
+	Name     string
+	Duration float32
+	Bones    []*BoneTimeline
+	Slots    []*SlotTimeline
+}
+
+type BoneTimeline struct {
+	BoneIndex  int
+	Rotate     *animation.Curve
+	TranslateX *animation.Curve
+	TranslateY *animation.Curve
+	ScaleX     *animation.Curve
+	ScaleY     *animation.Curve
+}
+
+type SlotTimeline struct {
+	SlotIndex   int
+	Attachments []AttachmentKey
+	Color       [4]*animation.Curve // R, G, B, A; nil when the slot color is not keyed
+}
+
+type AttachmentKey struct {
+	Time float32
+	Name string // Empty hides the attachment
+}
+
+func NewAnimation(name string) *Animation {
+	return &Animation{
+		Name:  name,
+		Bones: make([]*BoneTimeline, 0),
+		Slots: make([]*SlotTimeline, 0),
+	}
+}
+
+// Apply poses the skeleton at the given time. Alpha blends from the current
+// pose (0) to the animated pose (1), which is how two animations are crossfaded.
+func (a *Animation) Apply(skeleton *Skeleton, time float32, loop bool, alpha float32) {
+	if loop && a.Duration > 0 {
+		time = float32(math.Mod(float64(time), float64(a.Duration)))
+	}
+
+	for _, timeline := range a.Bones {
+		bone := skeleton.Bones[timeline.BoneIndex]
+		setup := bone.Data
+
+		if timeline.Rotate != nil {
+			target := setup.Rotation + timeline.Rotate.Evaluate(time)
+			bone.Rotation += shortestAngle(bone.Rotation, target) * alpha
+		}
+		if timeline.TranslateX != nil {
+			bone.X += (setup.X + timeline.TranslateX.Evaluate(time) - bone.X) * alpha
+		}
+		if timeline.TranslateY != nil {
+			bone.Y += (setup.Y + timeline.TranslateY.Evaluate(time) - bone.Y) * alpha
+		}
+		if timeline.ScaleX != nil {
+			bone.ScaleX += (setup.ScaleX*timeline.ScaleX.Evaluate(time) - bone.ScaleX) * alpha
+		}
+		if timeline.ScaleY != nil {
+			bone.ScaleY += (setup.ScaleY*timeline.ScaleY.Evaluate(time) - bone.ScaleY) * alpha
+		}
+	}
+
+	for _, timeline := range a.Slots {
+		slot := skeleton.Slots[timeline.SlotIndex]
+
+		// Attachments can't be blended, so they switch once this animation dominates
+		if len(timeline.Attachments) > 0 && alpha >= 0.5 {
+			index := sort.Search(len(timeline.Attachments), func(i int) bool {
+				return timeline.Attachments[i].Time > time
+			}) - 1
+			if index >= 0 {
+				skeleton.SetAttachment(slot.Data.Name, timeline.Attachments[index].Name)
+			}
+		}
+
+		for channel, curve := range timeline.Color {
+			if curve != nil {
+				slot.Color[channel] += (curve.Evaluate(time) - slot.Color[channel]) * alpha
+			}
+		}
+	}
+}
+
+func shortestAngle(from, to float32) float32 {
+	diff := math.Mod(float64(to-from), 2*math.Pi)
+	if diff > math.Pi {
+		diff -= 2 * math.Pi
+	} else if diff < -math.Pi {
+		diff += 2 * math.Pi
+	}
+	return float32(diff)
+}
+
+// Player advances one animation on a skeleton and crossfades when the
+// animation changes.
+type Player struct {
+	skeleton     *Skeleton
+	current      *Animation
+	previous     *Animation
+	currentTime  float32
+	previousTime float32
+	loop         bool
+	previousLoop bool
+	mixDuration  float32
+	mixTime      float32
+	Speed        float32
+	playing      bool
+}
+
+func NewPlayer(skeleton *Skeleton) *Player {
+	return &Player{
+		skeleton: skeleton,
+		Speed:    1.0,
+		playing:  true,
+	}
+}
+
+// Play switches animation, fading from the previous one over mixDuration seconds.
+func (p *Player) Play(anim *Animation, loop bool, mixDuration float32) {
+	if p.current == anim {
+		p.loop = loop
+		return
+	}
+
+	p.previous, p.previousTime, p.previousLoop = p.current, p.currentTime, p.loop
+	p.current, p.currentTime, p.loop = anim, 0, loop
+	p.mixDuration = mixDuration
+	p.mixTime = 0
+	p.playing = true
+
+	if p.previous == nil || mixDuration <= 0 {
+		p.previous = nil
+	}
+}
+
+func (p *Player) Update(deltaTime float32) {
+	if p.current == nil {
+		return
+	}
+
+	if p.playing {
+		deltaTime *= p.Speed
+		p.currentTime += deltaTime
+		p.previousTime += deltaTime
+		p.mixTime += deltaTime
+	}
+
+	p.skeleton.SetBonesToSetupPose()
+
+	if p.previous != nil {
+		if p.mixTime >= p.mixDuration {
+			p.previous = nil
+		} else {
+			p.previous.Apply(p.skeleton, p.previousTime, p.previousLoop, 1)
+			p.current.Apply(p.skeleton, p.currentTime, p.loop, p.mixTime/p.mixDuration)
+		}
+	}
+	if p.previous == nil {
+		p.current.Apply(p.skeleton, p.currentTime, p.loop, 1)
+	}
+
+	p.skeleton.UpdateWorldTransform()
+}
+
+func (p *Player) GetCurrent() *Animation {
+	return p.current
+}
+
+func (p *Player) GetTime() float32 {
+	return p.currentTime
+}
+
+func (p *Player) IsComplete() bool {
+	return p.current != nil && !p.loop && p.currentTime >= p.current.Duration
+}
+
+func (p *Player) Pause() {
+	p.playing = false
+}
+
+func (p *Player) Resume() {
+	p.playing = true
+}
+
+func (p *Player) IsPlaying() bool {
+	return p.playing
+}
+
+func (p *Player) GetSkeleton() *Skeleton {
+	return p.skeleton
+}
@@ -0,0 +1,547 @@
+package skeleton
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/animation"
+	"github.com/lunararch/helios/pkg/graphics/texture"
+)
+
+// Rig files use a subset of the Spine 3.8 JSON export:
+//
+//	bones       [{name, parent, x, y, rotation, scaleX, scaleY, length}]
+//	            rotation in degrees, counter-clockwise, Y up; parents listed first
+//	slots       [{name, bone, attachment, color}]   color is "rrggbb" or "rrggbbaa"
+//	skins       [{name, attachments: {slot: {attachment: {...}}}}]
+//	            (the older {skinName: {slot: ...}} object form is also accepted)
+//	            attachments must be "region" (the default type):
+//	            {path, x, y, rotation, scaleX, scaleY, width, height, color}
+//	animations  {name: {bones: {bone: {rotate, translate, scale}},
+//	                    slots: {slot: {attachment, color}}}}
+//	            rotate keys:     {time, angle}
+//	            translate keys:  {time, x, y}
+//	            scale keys:      {time, x, y}
+//	            attachment keys: {time, name}    name null hides the slot
+//	            color keys:      {time, color}
+//	            every key except attachment keys may set "curve": "stepped",
+//	            "curve": [cx1, cy1, cx2, cy2] or "curve": cx1 with "c2", "c3", "c4"
+//
+// Meshes, IK/transform/path constraints, events, deform and draw order
+// timelines are not supported; files using them are rejected.
+
+// RegionResolver maps an attachment path to a texture region. Returning nil
+// reports the region as missing.
+type RegionResolver func(path string) *texture.TextureRegion
+
+func RegionMap(regions map[string]*texture.TextureRegion) RegionResolver {
+	return func(path string) *texture.TextureRegion {
+		return regions[path]
+	}
+}
+
+// LoadSkeletonData reads a rig file. With a nil resolver attachments are left
+// without regions, which is enough to compute poses without a GPU.
+func LoadSkeletonData(path string, resolve RegionResolver) (*SkeletonData, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read skeleton file: %w", err)
+	}
+
+	skeletonData, err := ParseSkeletonData(data, resolve)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return skeletonData, nil
+}
+
+type rigFile struct {
+	Bones      []rigBone               `json:"bones"`
+	Slots      []rigSlot               `json:"slots"`
+	Skins      json.RawMessage         `json:"skins"`
+	Animations map[string]rigAnimation `json:"animations"`
+	IK         json.RawMessage         `json:"ik"`
+	Transform  json.RawMessage         `json:"transform"`
+	Path       json.RawMessage         `json:"path"`
+}
+
+type rigBone struct {
+	Name     string   `json:"name"`
+	Parent   string   `json:"parent"`
+	X        float32  `json:"x"`
+	Y        float32  `json:"y"`
+	Rotation float32  `json:"rotation"`
+	ScaleX   *float32 `json:"scaleX"`
+	ScaleY   *float32 `json:"scaleY"`
+	Length   float32  `json:"length"`
+}
+
+type rigSlot struct {
+	Name       string `json:"name"`
+	Bone       string `json:"bone"`
+	Attachment string `json:"attachment"`
+	Color      string `json:"color"`
+}
+
+type rigSkin struct {
+	Name        string                                    `json:"name"`
+	Attachments map[string]map[string]rigRegionAttachment `json:"attachments"`
+}
+
+type rigRegionAttachment struct {
+	Type     string   `json:"type"`
+	Path     string   `json:"path"`
+	X        float32  `json:"x"`
+	Y        float32  `json:"y"`
+	Rotation float32  `json:"rotation"`
+	ScaleX   *float32 `json:"scaleX"`
+	ScaleY   *float32 `json:"scaleY"`
+	Width    float32  `json:"width"`
+	Height   float32  `json:"height"`
+	Color    string   `json:"color"`
+}
+
+type rigAnimation struct {
+	Bones     map[string]rigBoneTimelines `json:"bones"`
+	Slots     map[string]rigSlotTimelines `json:"slots"`
+	IK        json.RawMessage             `json:"ik"`
+	Deform    json.RawMessage             `json:"deform"`
+	DrawOrder json.RawMessage             `json:"drawOrder"`
+	Events    json.RawMessage             `json:"events"`
+}
+
+type rigBoneTimelines struct {
+	Rotate    []rigKey `json:"rotate"`
+	Translate []rigKey `json:"translate"`
+	Scale     []rigKey `json:"scale"`
+	Shear     []rigKey `json:"shear"`
+}
+
+type rigSlotTimelines struct {
+	Attachment []rigKey `json:"attachment"`
+	Color      []rigKey `json:"color"`
+}
+
+type rigKey struct {
+	Time  float32         `json:"time"`
+	Angle *float32        `json:"angle"`
+	X     *float32        `json:"x"`
+	Y     *float32        `json:"y"`
+	Name  *string         `json:"name"`
+	Color string          `json:"color"`
+	Curve json.RawMessage `json:"curve"`
+	C2    *float32        `json:"c2"`
+	C3    *float32        `json:"c3"`
+	C4    *float32        `json:"c4"`
+}
+
+func ParseSkeletonData(data []byte, resolve RegionResolver) (*SkeletonData, error) {
+	var file rigFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse skeleton: %w", err)
+	}
+
+	constraints := []struct {
+		name    string
+		section json.RawMessage
+	}{{"ik", file.IK}, {"transform", file.Transform}, {"path", file.Path}}
+	for _, constraint := range constraints {
+		if len(constraint.section) > 0 && string(constraint.section) != "[]" && string(constraint.section) != "null" {
+			return nil, fmt.Errorf("%s constraints are not supported", constraint.name)
+		}
+	}
+
+	skeletonData := NewSkeletonData()
+	skeletonData.YUp = true
+
+	if err := parseBones(skeletonData, file.Bones); err != nil {
+		return nil, err
+	}
+	if err := parseSlots(skeletonData, file.Slots); err != nil {
+		return nil, err
+	}
+	if err := parseSkins(skeletonData, file.Skins, resolve); err != nil {
+		return nil, err
+	}
+
+	// In name order, so the same file always reports the same error first
+	for _, name := range slices.Sorted(maps.Keys(file.Animations)) {
+		anim, err := parseAnimation(skeletonData, name, file.Animations[name])
+		if err != nil {
+			return nil, fmt.Errorf("animation '%s': %w", name, err)
+		}
+		skeletonData.Animations[name] = anim
+	}
+
+	return skeletonData, nil
+}
+
+func parseBones(skeletonData *SkeletonData, bones []rigBone) error {
+	if len(bones) == 0 {
+		return fmt.Errorf("skeleton has no bones")
+	}
+
+	for i, rig := range bones {
+		if rig.Name == "" {
+			return fmt.Errorf("bone %d has no name", i)
+		}
+		if skeletonData.FindBone(rig.Name) != nil {
+			return fmt.Errorf("duplicate bone '%s'", rig.Name)
+		}
+
+		bone := &BoneData{
+			Name:     rig.Name,
+			Index:    i,
+			X:        rig.X,
+			Y:        rig.Y,
+			Rotation: mgl32.DegToRad(rig.Rotation),
+			ScaleX:   valueOr(rig.ScaleX, 1),
+			ScaleY:   valueOr(rig.ScaleY, 1),
+			Length:   rig.Length,
+		}
+
+		if rig.Parent != "" {
+			bone.Parent = skeletonData.FindBone(rig.Parent)
+			if bone.Parent == nil {
+				return fmt.Errorf("bone '%s' has parent '%s', which is unknown or listed after it", rig.Name, rig.Parent)
+			}
+		} else if i != 0 {
+			return fmt.Errorf("bone '%s' has no parent; only the first bone may be a root", rig.Name)
+		}
+
+		skeletonData.Bones = append(skeletonData.Bones, bone)
+	}
+	return nil
+}
+
+func parseSlots(skeletonData *SkeletonData, slots []rigSlot) error {
+	for i, rig := range slots {
+		if skeletonData.FindSlot(rig.Name) != nil {
+			return fmt.Errorf("duplicate slot '%s'", rig.Name)
+		}
+
+		bone := skeletonData.FindBone(rig.Bone)
+		if bone == nil {
+			return fmt.Errorf("slot '%s' references unknown bone '%s'", rig.Name, rig.Bone)
+		}
+
+		color, err := parseColor(rig.Color)
+		if err != nil {
+			return fmt.Errorf("slot '%s': %w", rig.Name, err)
+		}
+
+		skeletonData.Slots = append(skeletonData.Slots, &SlotData{
+			Name:       rig.Name,
+			Index:      i,
+			Bone:       bone,
+			Attachment: rig.Attachment,
+			Color:      color,
+		})
+	}
+	return nil
+}
+
+func parseSkins(skeletonData *SkeletonData, raw json.RawMessage, resolve RegionResolver) error {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var skins []rigSkin
+	if err := json.Unmarshal(raw, &skins); err != nil {
+		// Spine before 3.8 stores skins as an object keyed by name
+		var byName map[string]map[string]map[string]rigRegionAttachment
+		if err := json.Unmarshal(raw, &byName); err != nil {
+			return fmt.Errorf("failed to parse skins: %w", err)
+		}
+		for _, name := range slices.Sorted(maps.Keys(byName)) {
+			skins = append(skins, rigSkin{Name: name, Attachments: byName[name]})
+		}
+	}
+
+	for _, rig := range skins {
+		skin := NewSkin(rig.Name)
+
+		for _, slotName := range slices.Sorted(maps.Keys(rig.Attachments)) {
+			attachments := rig.Attachments[slotName]
+			slot := skeletonData.FindSlot(slotName)
+			if slot == nil {
+				return fmt.Errorf("skin '%s' references unknown slot '%s'", rig.Name, slotName)
+			}
+
+			for _, attachmentName := range slices.Sorted(maps.Keys(attachments)) {
+				attachment, err := parseRegionAttachment(attachmentName, attachments[attachmentName], resolve)
+				if err != nil {
+					return fmt.Errorf("skin '%s' slot '%s': %w", rig.Name, slotName, err)
+				}
+				skin.AddAttachment(slot.Index, attachment)
+			}
+		}
+
+		skeletonData.Skins[skin.Name] = skin
+		if skin.Name == "default" {
+			skeletonData.DefaultSkin = skin
+		}
+	}
+	return nil
+}
+
+func parseRegionAttachment(name string, rig rigRegionAttachment, resolve RegionResolver) (*RegionAttachment, error) {
+	if rig.Type != "" && rig.Type != "region" {
+		return nil, fmt.Errorf("attachment '%s' has unsupported type '%s'", name, rig.Type)
+	}
+
+	color, err := parseColor(rig.Color)
+	if err != nil {
+		return nil, fmt.Errorf("attachment '%s': %w", name, err)
+	}
+
+	attachment := &RegionAttachment{
+		Name:     name,
+		Path:     rig.Path,
+		X:        rig.X,
+		Y:        rig.Y,
+		Rotation: mgl32.DegToRad(rig.Rotation),
+		ScaleX:   valueOr(rig.ScaleX, 1),
+		ScaleY:   valueOr(rig.ScaleY, 1),
+		Width:    rig.Width,
+		Height:   rig.Height,
+		Color:    color,
+	}
+	if attachment.Path == "" {
+		attachment.Path = name
+	}
+
+	if resolve != nil {
+		attachment.Region = resolve(attachment.Path)
+		if attachment.Region == nil {
+			return nil, fmt.Errorf("attachment '%s' references unknown region '%s'", name, attachment.Path)
+		}
+	}
+
+	return attachment, nil
+}
+
+func parseAnimation(skeletonData *SkeletonData, name string, rig rigAnimation) (*Animation, error) {
+	unsupported := []struct {
+		section string
+		raw     json.RawMessage
+	}{{"ik", rig.IK}, {"deform", rig.Deform}, {"drawOrder", rig.DrawOrder}, {"events", rig.Events}}
+	for _, timelines := range unsupported {
+		if len(timelines.raw) > 0 && string(timelines.raw) != "null" {
+			return nil, fmt.Errorf("%s timelines are not supported", timelines.section)
+		}
+	}
+
+	anim := NewAnimation(name)
+
+	// Bones and slots in name order, so the first error reported is always the same
+	for _, boneName := range slices.Sorted(maps.Keys(rig.Bones)) {
+		timelines := rig.Bones[boneName]
+		bone := skeletonData.FindBone(boneName)
+		if bone == nil {
+			return nil, fmt.Errorf("unknown bone '%s'", boneName)
+		}
+		if len(timelines.Shear) > 0 {
+			return nil, fmt.Errorf("bone '%s': shear timelines are not supported", boneName)
+		}
+
+		timeline := &BoneTimeline{BoneIndex: bone.Index}
+
+		if len(timelines.Rotate) > 0 {
+			curves, err := buildCurves(timelines.Rotate, func(key rigKey) []float32 {
+				return []float32{mgl32.DegToRad(valueOr(key.Angle, 0))}
+			})
+			if err != nil {
+				return nil, fmt.Errorf("bone '%s' rotate: %w", boneName, err)
+			}
+			timeline.Rotate = curves[0]
+		}
+
+		if len(timelines.Translate) > 0 {
+			curves, err := buildCurves(timelines.Translate, func(key rigKey) []float32 {
+				return []float32{valueOr(key.X, 0), valueOr(key.Y, 0)}
+			})
+			if err != nil {
+				return nil, fmt.Errorf("bone '%s' translate: %w", boneName, err)
+			}
+			timeline.TranslateX, timeline.TranslateY = curves[0], curves[1]
+		}
+
+		if len(timelines.Scale) > 0 {
+			curves, err := buildCurves(timelines.Scale, func(key rigKey) []float32 {
+				return []float32{valueOr(key.X, 1), valueOr(key.Y, 1)}
+			})
+			if err != nil {
+				return nil, fmt.Errorf("bone '%s' scale: %w", boneName, err)
+			}
+			timeline.ScaleX, timeline.ScaleY = curves[0], curves[1]
+		}
+
+		anim.Bones = append(anim.Bones, timeline)
+		anim.Duration = maxKeyTime(anim.Duration, timelines.Rotate, timelines.Translate, timelines.Scale)
+	}
+
+	for _, slotName := range slices.Sorted(maps.Keys(rig.Slots)) {
+		timelines := rig.Slots[slotName]
+		slot := skeletonData.FindSlot(slotName)
+		if slot == nil {
+			return nil, fmt.Errorf("unknown slot '%s'", slotName)
+		}
+
+		timeline := &SlotTimeline{SlotIndex: slot.Index}
+
+		for _, key := range timelines.Attachment {
+			attachmentKey := AttachmentKey{Time: key.Time}
+			if key.Name != nil {
+				attachmentKey.Name = *key.Name
+			}
+			timeline.Attachments = append(timeline.Attachments, attachmentKey)
+		}
+
+		if len(timelines.Color) > 0 {
+			var colorErr error
+			curves, err := buildCurves(timelines.Color, func(key rigKey) []float32 {
+				color, err := parseColor(key.Color)
+				if err != nil {
+					colorErr = err
+				}
+				return color[:]
+			})
+			if err == nil {
+				err = colorErr
+			}
+			if err != nil {
+				return nil, fmt.Errorf("slot '%s' color: %w", slotName, err)
+			}
+			copy(timeline.Color[:], curves)
+		}
+
+		anim.Slots = append(anim.Slots, timeline)
+		anim.Duration = maxKeyTime(anim.Duration, timelines.Attachment, timelines.Color)
+	}
+
+	return anim, nil
+}
+
+// buildCurves turns Spine keys into one animation.Curve per channel. Spine
+// curves are normalised Bezier handles shared by every channel of a key, so
+// they are scaled into each channel's time/value range.
+func buildCurves(keys []rigKey, values func(key rigKey) []float32) ([]*animation.Curve, error) {
+	channels := len(values(keys[0]))
+	keyframes := make([][]animation.Keyframe, channels)
+
+	handles := make([][4]float32, len(keys))
+
+	for i, key := range keys {
+		if i > 0 && key.Time < keys[i-1].Time {
+			return nil, fmt.Errorf("keys are not in time order at %v", key.Time)
+		}
+
+		interpolation, keyHandles, err := parseCurve(key)
+		if err != nil {
+			return nil, err
+		}
+		handles[i] = keyHandles
+
+		keyValues := values(key)
+		for channel := 0; channel < channels; channel++ {
+			keyframes[channel] = append(keyframes[channel], animation.Keyframe{
+				Time:          key.Time,
+				Value:         keyValues[channel],
+				Interpolation: interpolation,
+			})
+		}
+	}
+
+	for channel := 0; channel < channels; channel++ {
+		for i := 0; i+1 < len(keys); i++ {
+			from, to := &keyframes[channel][i], &keyframes[channel][i+1]
+			if from.Interpolation != animation.InterpolationBezier {
+				continue
+			}
+
+			// Both handles are relative to their own key; the incoming one lives on the next key
+			duration, delta := to.Time-from.Time, to.Value-from.Value
+			from.OutHandle = mgl32.Vec2{handles[i][0] * duration, handles[i][1] * delta}
+			to.InHandle = mgl32.Vec2{(handles[i][2] - 1) * duration, (handles[i][3] - 1) * delta}
+		}
+	}
+
+	curves := make([]*animation.Curve, channels)
+	for channel := range curves {
+		curves[channel] = animation.NewCurve()
+		curves[channel].Keys = keyframes[channel]
+	}
+	return curves, nil
+}
+
+func parseCurve(key rigKey) (animation.Interpolation, [4]float32, error) {
+	var handles [4]float32
+	raw := strings.TrimSpace(string(key.Curve))
+
+	switch {
+	case raw == "" || raw == "null" || raw == `"linear"`:
+		return animation.InterpolationLinear, handles, nil
+	case raw == `"stepped"`:
+		return animation.InterpolationStep, handles, nil
+	case strings.HasPrefix(raw, "["):
+		var values []float32
+		if err := json.Unmarshal(key.Curve, &values); err != nil || len(values) != 4 {
+			return 0, handles, fmt.Errorf("curve at %v must be [cx1, cy1, cx2, cy2]", key.Time)
+		}
+		copy(handles[:], values)
+	default:
+		var cx1 float32
+		if err := json.Unmarshal(key.Curve, &cx1); err != nil {
+			return 0, handles, fmt.Errorf("unsupported curve %s at %v", raw, key.Time)
+		}
+		handles = [4]float32{cx1, valueOr(key.C2, 0), valueOr(key.C3, 1), valueOr(key.C4, 1)}
+	}
+
+	return animation.InterpolationBezier, handles, nil
+}
+
+func maxKeyTime(duration float32, timelines ...[]rigKey) float32 {
+	for _, keys := range timelines {
+		if len(keys) > 0 && keys[len(keys)-1].Time > duration {
+			duration = keys[len(keys)-1].Time
+		}
+	}
+	return duration
+}
+
+func parseColor(hex string) (mgl32.Vec4, error) {
+	if hex == "" {
+		return mgl32.Vec4{1, 1, 1, 1}, nil
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return mgl32.Vec4{}, fmt.Errorf("invalid color '%s'", hex)
+	}
+
+	var channels [4]uint8
+	if _, err := fmt.Sscanf(hex, "%02x%02x%02x%02x", &channels[0], &channels[1], &channels[2], &channels[3]); err != nil {
+		return mgl32.Vec4{}, fmt.Errorf("invalid color '%s'", hex)
+	}
+
+	var color mgl32.Vec4
+	for i, channel := range channels {
+		color[i] = float32(channel) / math.MaxUint8
+	}
+	return color, nil
+}
+
+func valueOr(value *float32, fallback float32) float32 {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
@@ -0,0 +1,137 @@
+package skeleton
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const epsilon = 1e-3
+
+// testRig is an arm raised straight up from a root bone, with one animation
+// that swings the arm on an ease-in-out curve and slides the root linearly.
+const testRig = `{
+	"bones": [
+		{"name": "root", "x": 10},
+		{"name": "arm", "parent": "root", "x": 5, "rotation": 90, "length": 5},
+		{"name": "hand", "parent": "arm", "x": 5, "scaleX": 2}
+	],
+	"animations": {
+		"wave": {
+			"bones": {
+				"arm": {"rotate": [
+					{"time": 0, "angle": 0, "curve": 0.25, "c2": 0, "c3": 0.75},
+					{"time": 1, "angle": 90}
+				]},
+				"root": {"translate": [
+					{"time": 0, "x": 0, "y": 0},
+					{"time": 2, "x": 10, "y": 20}
+				]}
+			}
+		}
+	}
+}`
+
+func loadTestSkeleton(t *testing.T) *Skeleton {
+	t.Helper()
+	data, err := ParseSkeletonData([]byte(testRig), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewSkeleton(data)
+}
+
+func TestSetupPoseWorldTransforms(t *testing.T) {
+	skeleton := loadTestSkeleton(t)
+
+	// Rigs are Y up and the world is Y down, so the raised arm points to -Y
+	tests := []struct {
+		bone     string
+		position mgl32.Vec2
+	}{
+		{"root", mgl32.Vec2{10, 0}},
+		{"arm", mgl32.Vec2{15, 0}},
+		{"hand", mgl32.Vec2{15, -5}},
+	}
+
+	for _, test := range tests {
+		if got := skeleton.FindBone(test.bone).WorldPosition(); !got.ApproxEqualThreshold(test.position, epsilon) {
+			t.Errorf("%s world position = %v, want %v", test.bone, got, test.position)
+		}
+	}
+}
+
+func TestAnimationSamples(t *testing.T) {
+	skeleton := loadTestSkeleton(t)
+	wave := skeleton.Data.FindAnimation("wave")
+	if wave == nil || wave.Duration != 2 {
+		t.Fatalf("wave animation = %v, want one 2 seconds long", wave)
+	}
+
+	setupRotation := float32(math.Pi / 2)
+	tests := []struct {
+		time     float32
+		loop     bool
+		rotation float32 // Of the arm, over its setup pose
+		root     mgl32.Vec2
+	}{
+		{time: 0, rotation: 0, root: mgl32.Vec2{10, 0}},
+		// The curve is symmetric, so the midpoint is exactly halfway
+		{time: 0.5, rotation: math.Pi / 4, root: mgl32.Vec2{12.5, 5}},
+		{time: 1, rotation: math.Pi / 2, root: mgl32.Vec2{15, 10}},
+		{time: 2, rotation: math.Pi / 2, root: mgl32.Vec2{20, 20}},
+		{time: 3, rotation: math.Pi / 2, root: mgl32.Vec2{20, 20}},
+		{time: 2.5, loop: true, rotation: math.Pi / 4, root: mgl32.Vec2{12.5, 5}},
+	}
+
+	for _, test := range tests {
+		skeleton.SetToSetupPose()
+		wave.Apply(skeleton, test.time, test.loop, 1)
+		skeleton.UpdateWorldTransform()
+
+		arm, root := skeleton.FindBone("arm"), skeleton.FindBone("root")
+		if got := arm.Rotation - setupRotation; math.Abs(float64(got-test.rotation)) > epsilon {
+			t.Errorf("at %g arm rotation = %g, want %g", test.time, got, test.rotation)
+		}
+		if got := (mgl32.Vec2{root.X, root.Y}); !got.ApproxEqualThreshold(test.root, epsilon) {
+			t.Errorf("at %g root = %v, want %v", test.time, got, test.root)
+		}
+
+		// The arm's world position follows the root, flipped to Y down
+		want := mgl32.Vec2{test.root.X() + 5, -test.root.Y()}
+		if got := arm.WorldPosition(); !got.ApproxEqualThreshold(want, epsilon) {
+			t.Errorf("at %g arm world position = %v, want %v", test.time, got, want)
+		}
+	}
+}
+
+func TestCurveEasesIn(t *testing.T) {
+	skeleton := loadTestSkeleton(t)
+	wave := skeleton.Data.FindAnimation("wave")
+
+	// Eased in, a quarter of the way through is well short of a quarter turn
+	wave.Apply(skeleton, 0.25, false, 1)
+	got := skeleton.FindBone("arm").Rotation - math.Pi/2
+	if got <= 0 || got >= math.Pi/8 {
+		t.Errorf("arm rotation at 0.25 = %g, want between 0 and %g", got, math.Pi/8)
+	}
+}
+
+func TestFirstErrorIsDeterministic(t *testing.T) {
+	rig := `{
+		"bones": [{"name": "root"}],
+		"animations": {
+			"walk": {"bones": {"leg": {}, "arm": {}, "tail": {}}},
+			"idle": {"bones": {"head": {}}}
+		}
+	}`
+
+	for range 20 {
+		_, err := ParseSkeletonData([]byte(rig), nil)
+		if err == nil || !strings.Contains(err.Error(), "animation 'idle': unknown bone 'head'") {
+			t.Fatalf("got error %v, want the idle animation's unknown bone", err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package skeleton
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/sprite"
+)
+
+// Draw submits every visible attachment in draw order. Slots whose
+// attachment has no region are skipped.
+func Draw(batch *sprite.SpriteBatch, skeleton *Skeleton, depth float32) {
+	for _, slot := range skeleton.DrawOrder {
+		attachment := slot.Attachment
+		if attachment == nil || attachment.Region == nil {
+			continue
+		}
+
+		points, visible := skeleton.AttachmentCorners(slot)
+		if !visible {
+			continue
+		}
+
+		var corners [4]mgl32.Vec3
+		for i, point := range points {
+			corners[i] = point.Vec3(depth)
+		}
+
+		color := mulColor(mulColor(skeleton.Color, slot.Color), attachment.Color)
+		if color.W() <= 0 {
+			continue
+		}
+
+		region := attachment.Region
+		batch.DrawQuad(region.Texture, corners, [4]float32{region.U1, region.V1, region.U2, region.V2}, color)
+	}
+}
+
+func mulColor(a, b mgl32.Vec4) mgl32.Vec4 {
+	return mgl32.Vec4{a[0] * b[0], a[1] * b[1], a[2] * b[2], a[3] * b[3]}
+}
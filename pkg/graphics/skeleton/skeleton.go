@@ -0,0 +1,358 @@
+package skeleton
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/texture"
+)
+
+// BoneData is the setup pose of a bone, relative to its parent. Rotations
+// are in radians.
+type BoneData struct {
+	Name     string
+	Index    int
+	Parent   *BoneData
+	X, Y     float32
+	Rotation float32
+	ScaleX   float32
+	ScaleY   float32
+	Length   float32
+}
+
+type SlotData struct {
+	Name       string
+	Index      int
+	Bone       *BoneData
+	Attachment string // Attachment shown in the setup pose, empty for none
+	Color      mgl32.Vec4
+}
+
+// RegionAttachment is a textured quad placed in its bone's space.
+type RegionAttachment struct {
+	Name     string
+	Path     string // Region name used to resolve the texture, defaults to Name
+	Region   *texture.TextureRegion
+	X, Y     float32
+	Rotation float32
+	ScaleX   float32
+	ScaleY   float32
+	Width    float32
+	Height   float32
+	Color    mgl32.Vec4
+}
+
+type Skin struct {
+	Name        string
+	attachments map[int]map[string]*RegionAttachment // Slot index -> attachment name
+}
+
+func NewSkin(name string) *Skin {
+	return &Skin{
+		Name:        name,
+		attachments: make(map[int]map[string]*RegionAttachment),
+	}
+}
+
+func (s *Skin) AddAttachment(slotIndex int, attachment *RegionAttachment) {
+	if _, exists := s.attachments[slotIndex]; !exists {
+		s.attachments[slotIndex] = make(map[string]*RegionAttachment)
+	}
+	s.attachments[slotIndex][attachment.Name] = attachment
+}
+
+func (s *Skin) GetAttachment(slotIndex int, name string) *RegionAttachment {
+	if slotAttachments, exists := s.attachments[slotIndex]; exists {
+		return slotAttachments[name]
+	}
+	return nil
+}
+
+// SkeletonData is the shared, immutable part of a rig. Any number of
+// Skeletons can be created from one SkeletonData.
+type SkeletonData struct {
+	Bones       []*BoneData // Parents always come before their children
+	Slots       []*SlotData // Setup draw order
+	Skins       map[string]*Skin
+	DefaultSkin *Skin
+	Animations  map[string]*Animation
+	YUp         bool // Rig authored with Y pointing up (as Spine does); flipped when posed
+}
+
+func NewSkeletonData() *SkeletonData {
+	return &SkeletonData{
+		Bones:      make([]*BoneData, 0),
+		Slots:      make([]*SlotData, 0),
+		Skins:      make(map[string]*Skin),
+		Animations: make(map[string]*Animation),
+	}
+}
+
+func (sd *SkeletonData) FindBone(name string) *BoneData {
+	for _, bone := range sd.Bones {
+		if bone.Name == name {
+			return bone
+		}
+	}
+	return nil
+}
+
+func (sd *SkeletonData) FindSlot(name string) *SlotData {
+	for _, slot := range sd.Slots {
+		if slot.Name == name {
+			return slot
+		}
+	}
+	return nil
+}
+
+func (sd *SkeletonData) FindAnimation(name string) *Animation {
+	return sd.Animations[name]
+}
+
+type Bone struct {
+	Data     *BoneData
+	Parent   *Bone
+	X, Y     float32
+	Rotation float32
+	ScaleX   float32
+	ScaleY   float32
+	World    mgl32.Mat3 // Bone space to skeleton world space, valid after UpdateWorldTransform
+}
+
+func (b *Bone) SetToSetupPose() {
+	b.X = b.Data.X
+	b.Y = b.Data.Y
+	b.Rotation = b.Data.Rotation
+	b.ScaleX = b.Data.ScaleX
+	b.ScaleY = b.Data.ScaleY
+}
+
+func (b *Bone) LocalMatrix() mgl32.Mat3 {
+	return mgl32.Translate2D(b.X, b.Y).
+		Mul3(mgl32.HomogRotate2D(b.Rotation)).
+		Mul3(mgl32.Scale2D(b.ScaleX, b.ScaleY))
+}
+
+// WorldPosition returns the bone origin in world space.
+func (b *Bone) WorldPosition() mgl32.Vec2 {
+	return mgl32.Vec2{b.World[6], b.World[7]}
+}
+
+type Slot struct {
+	Data       *SlotData
+	Bone       *Bone
+	Attachment *RegionAttachment
+	Color      mgl32.Vec4
+}
+
+func (s *Slot) SetToSetupPose(skin, defaultSkin *Skin) {
+	s.Color = s.Data.Color
+	s.Attachment = nil
+	if s.Data.Attachment != "" {
+		s.Attachment = findAttachment(skin, defaultSkin, s.Data.Index, s.Data.Attachment)
+	}
+}
+
+func findAttachment(skin, defaultSkin *Skin, slotIndex int, name string) *RegionAttachment {
+	if skin != nil {
+		if attachment := skin.GetAttachment(slotIndex, name); attachment != nil {
+			return attachment
+		}
+	}
+	if defaultSkin != nil {
+		return defaultSkin.GetAttachment(slotIndex, name)
+	}
+	return nil
+}
+
+// Skeleton is one posed instance of a rig.
+type Skeleton struct {
+	Data      *SkeletonData
+	Bones     []*Bone
+	Slots     []*Slot
+	DrawOrder []*Slot
+	Skin      *Skin
+	Position  mgl32.Vec2
+	Rotation  float32
+	Scale     mgl32.Vec2
+	Color     mgl32.Vec4
+}
+
+func NewSkeleton(data *SkeletonData) *Skeleton {
+	skeleton := &Skeleton{
+		Data:      data,
+		Bones:     make([]*Bone, len(data.Bones)),
+		Slots:     make([]*Slot, len(data.Slots)),
+		DrawOrder: make([]*Slot, len(data.Slots)),
+		Scale:     mgl32.Vec2{1, 1},
+		Color:     mgl32.Vec4{1, 1, 1, 1},
+	}
+
+	for i, boneData := range data.Bones {
+		bone := &Bone{Data: boneData}
+		if boneData.Parent != nil {
+			bone.Parent = skeleton.Bones[boneData.Parent.Index]
+		}
+		skeleton.Bones[i] = bone
+	}
+
+	for i, slotData := range data.Slots {
+		slot := &Slot{
+			Data: slotData,
+			Bone: skeleton.Bones[slotData.Bone.Index],
+		}
+		skeleton.Slots[i] = slot
+		skeleton.DrawOrder[i] = slot
+	}
+
+	skeleton.SetToSetupPose()
+	skeleton.UpdateWorldTransform()
+	return skeleton
+}
+
+func (s *Skeleton) SetToSetupPose() {
+	s.SetBonesToSetupPose()
+	s.SetSlotsToSetupPose()
+}
+
+func (s *Skeleton) SetBonesToSetupPose() {
+	for _, bone := range s.Bones {
+		bone.SetToSetupPose()
+	}
+}
+
+func (s *Skeleton) SetSlotsToSetupPose() {
+	copy(s.DrawOrder, s.Slots)
+	for _, slot := range s.Slots {
+		slot.SetToSetupPose(s.Skin, s.Data.DefaultSkin)
+	}
+}
+
+// UpdateWorldTransform recomputes every bone's world matrix from the local
+// pose. Call it after applying animations and before drawing.
+func (s *Skeleton) UpdateWorldTransform() {
+	scaleY := s.Scale.Y()
+	if s.Data.YUp {
+		scaleY = -scaleY
+	}
+
+	root := mgl32.Translate2D(s.Position.X(), s.Position.Y()).
+		Mul3(mgl32.HomogRotate2D(s.Rotation)).
+		Mul3(mgl32.Scale2D(s.Scale.X(), scaleY))
+
+	for _, bone := range s.Bones {
+		parent := root
+		if bone.Parent != nil {
+			parent = bone.Parent.World
+		}
+		bone.World = parent.Mul3(bone.LocalMatrix())
+	}
+}
+
+func (s *Skeleton) FindBone(name string) *Bone {
+	for _, bone := range s.Bones {
+		if bone.Data.Name == name {
+			return bone
+		}
+	}
+	return nil
+}
+
+func (s *Skeleton) FindSlot(name string) *Slot {
+	for _, slot := range s.Slots {
+		if slot.Data.Name == name {
+			return slot
+		}
+	}
+	return nil
+}
+
+func (s *Skeleton) SetSkin(name string) error {
+	skin, exists := s.Data.Skins[name]
+	if !exists {
+		return fmt.Errorf("skin '%s' not found", name)
+	}
+
+	s.Skin = skin
+	s.SetSlotsToSetupPose()
+	return nil
+}
+
+// SetAttachment shows the named attachment in a slot; an empty name hides it.
+func (s *Skeleton) SetAttachment(slotName, attachmentName string) error {
+	slot := s.FindSlot(slotName)
+	if slot == nil {
+		return fmt.Errorf("slot '%s' not found", slotName)
+	}
+
+	if attachmentName == "" {
+		slot.Attachment = nil
+		return nil
+	}
+
+	attachment := findAttachment(s.Skin, s.Data.DefaultSkin, slot.Data.Index, attachmentName)
+	if attachment == nil {
+		return fmt.Errorf("attachment '%s' not found for slot '%s'", attachmentName, slotName)
+	}
+	slot.Attachment = attachment
+	return nil
+}
+
+// AttachmentCorners returns the world-space corners of the slot's region
+// attachment in texture order: (U1,V1), (U2,V1), (U1,V2), (U2,V2).
+func (s *Skeleton) AttachmentCorners(slot *Slot) ([4]mgl32.Vec2, bool) {
+	var corners [4]mgl32.Vec2
+	attachment := slot.Attachment
+	if attachment == nil {
+		return corners, false
+	}
+
+	local := mgl32.Translate2D(attachment.X, attachment.Y).
+		Mul3(mgl32.HomogRotate2D(attachment.Rotation)).
+		Mul3(mgl32.Scale2D(attachment.ScaleX, attachment.ScaleY))
+	world := slot.Bone.World.Mul3(local)
+
+	halfWidth := attachment.Width / 2
+	top := -attachment.Height / 2
+	if s.Data.YUp {
+		top = -top
+	}
+
+	points := [4]mgl32.Vec2{
+		{-halfWidth, top},
+		{halfWidth, top},
+		{-halfWidth, -top},
+		{halfWidth, -top},
+	}
+	for i, point := range points {
+		corners[i] = world.Mul3x1(point.Vec3(1)).Vec2()
+	}
+	return corners, true
+}
+
+// Bounds returns the axis-aligned box around every visible attachment.
+func (s *Skeleton) Bounds() (min, max mgl32.Vec2, ok bool) {
+	min = mgl32.Vec2{float32(math.Inf(1)), float32(math.Inf(1))}
+	max = mgl32.Vec2{float32(math.Inf(-1)), float32(math.Inf(-1))}
+
+	for _, slot := range s.DrawOrder {
+		corners, visible := s.AttachmentCorners(slot)
+		if !visible {
+			continue
+		}
+		ok = true
+		for _, corner := range corners {
+			for axis := 0; axis < 2; axis++ {
+				if corner[axis] < min[axis] {
+					min[axis] = corner[axis]
+				}
+				if corner[axis] > max[axis] {
+					max[axis] = corner[axis]
+				}
+			}
+		}
+	}
+	return min, max, ok
+}
@@ -1,44 +1,124 @@
 package sprite
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/go-gl/gl/all-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/material"
 	"github.com/lunararch/helios/pkg/graphics/shader"
 	"github.com/lunararch/helios/pkg/graphics/texture"
 )
 
 const (
 	MaxBatchSize      = 1000 // Maximum number of sprites in a batch
-	VertecesPerSprite = 6    // Number of vertices per sprite (6 for two triangles)
-	VertexSize        = 9    // Size of each vertex (3 for position, 2 for texture coords, 4 for color = 9 floats total, 36 bytes per vertex)
+	VertecesPerSprite = 4    // Number of vertices per sprite (one quad, drawn with indices)
+	IndicesPerSprite  = 6    // Number of indices per sprite (two triangles)
+	VertexSize        = 10   // Size of each vertex (3 for position, 2 for texture coords, 4 for color, 1 for texture slot = 10 floats total, 40 bytes per vertex)
+	MaxTextureSlots   = 8    // Textures bound per draw call; must match the sampler array in batch.frag
+	CameraBinding     = 0    // Uniform block binding point of the Camera block in batch.vert
+)
+
+// CameraUniforms matches the std140 Camera block in batch.vert, shared by
+// the default shader and every material shader.
+type CameraUniforms struct {
+	Projection mgl32.Mat4
+	View       mgl32.Mat4
+}
+
+// SortMode decides the order sprites are drawn in when the batch is flushed.
+type SortMode int
+
+const (
+	SortDeferred    SortMode = iota // Submission order
+	SortTexture                     // Grouped by material and texture, fewest draw calls
+	SortDepth                       // Painter's order by SortKey: layer, order in layer, Y on y-sorted layers, then Z
+	SortBackToFront                 // By Z, farthest first, for blended sprites
+	SortFrontToBack                 // By Z, nearest first, for opaque sprites with depth testing
 )
 
+// Every mode but SortFrontToBack draws in painter's order with the depth test
+// off, since depth writes from transparent pixels would hide whatever is
+// drawn behind them later. SortFrontToBack turns the depth test on.
+
+// BatchStats counts the work done between Begin and End.
+type BatchStats struct {
+	DrawCalls int
+	Sprites   int
+	Flushes   int // Draw calls forced early because texture slots or the buffer ran out
+}
+
+type batchQuad struct {
+	texture  *texture.Texture
+	material *material.Material
+	corners  [4]mgl32.Vec3
+	uvs      [4]float32
+	color    mgl32.Vec4
+	key      SortKey
+}
+
 type SpriteBatch struct {
 	shader      *shader.Shader
 	vao         uint32
 	vbo         uint32
+	ebo         uint32
 	vertices    []float32
 	spriteCount int
-	currentTex  *texture.Texture
+	quads       []batchQuad
+	textures    []*texture.Texture // Textures bound to slots for the pending draw call
+	maxTextures int
+	sortMode    SortMode
+	layer       int
+	order       int
+	stats       BatchStats
+	lastStats   BatchStats
+	target      *texture.RenderTarget
+	normalPass  *texture.Texture // Fallback normal map while drawing normals, nil otherwise
+
+	camera       *shader.UniformBlock[CameraUniforms]
+	material     *material.Material        // Used by DrawQuad, DrawNineSlice and DrawTiled
+	drawMaterial *material.Material        // Material of the pending draw call
+	prepared     map[*shader.Shader]uint32 // Program ID each shader was prepared with
 }
 
 func NewSpriteBatch(shaderProgram *shader.Shader) *SpriteBatch {
 	maxSize := MaxBatchSize * VertecesPerSprite * VertexSize
 
+	var textureUnits int32
+	gl.GetIntegerv(gl.MAX_TEXTURE_IMAGE_UNITS, &textureUnits)
+
 	batch := &SpriteBatch{
 		shader:      shaderProgram,
 		vertices:    make([]float32, 0, maxSize),
 		spriteCount: 0,
+		quads:       make([]batchQuad, 0, MaxBatchSize),
+		textures:    make([]*texture.Texture, 0, MaxTextureSlots),
+		maxTextures: max(1, min(MaxTextureSlots, int(textureUnits))),
+		sortMode:    SortDeferred,
+		camera:      shader.NewUniformBlock[CameraUniforms](CameraBinding),
+		prepared:    make(map[*shader.Shader]uint32),
+	}
+
+	// Every quad uses the same index pattern, so the index buffer never changes
+	indices := make([]uint32, 0, MaxBatchSize*IndicesPerSprite)
+	for i := uint32(0); i < MaxBatchSize; i++ {
+		base := i * VertecesPerSprite
+		indices = append(indices, base, base+1, base+2, base+1, base+3, base+2)
 	}
 
 	gl.GenVertexArrays(1, &batch.vao)
 	gl.GenBuffers(1, &batch.vbo)
+	gl.GenBuffers(1, &batch.ebo)
 
 	gl.BindVertexArray(batch.vao)
 	gl.BindBuffer(gl.ARRAY_BUFFER, batch.vbo)
 
 	gl.BufferData(gl.ARRAY_BUFFER, maxSize*4, nil, gl.DYNAMIC_DRAW)
 
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, batch.ebo)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
+
 	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, VertexSize*4, gl.PtrOffset(0))
 	gl.EnableVertexAttribArray(0)
 
@@ -48,23 +128,207 @@ func NewSpriteBatch(shaderProgram *shader.Shader) *SpriteBatch {
 	gl.VertexAttribPointer(2, 4, gl.FLOAT, false, VertexSize*4, gl.PtrOffset(5*4))
 	gl.EnableVertexAttribArray(2)
 
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.VertexAttribPointer(3, 1, gl.FLOAT, false, VertexSize*4, gl.PtrOffset(9*4))
+	gl.EnableVertexAttribArray(3)
+
 	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
 
+	batch.prepare(shaderProgram)
 	return batch
 }
 
+// prepare points a program's sampler array at the texture slots and its
+// Camera block at the batch's camera, once per program and again after the
+// shader is reloaded.
+func (b *SpriteBatch) prepare(program *shader.Shader) {
+	if b.prepared[program] == program.ID {
+		return
+	}
+
+	program.Use()
+	for i := 0; i < MaxTextureSlots; i++ {
+		program.SetInt(fmt.Sprintf("textures[%d]", i), int32(i))
+	}
+	program.BindUniformBlock("Camera", CameraBinding)
+	b.prepared[program] = program.ID
+}
+
+// SetCamera sets the projection and view used by every shader the batch
+// draws with.
+func (b *SpriteBatch) SetCamera(projection, view mgl32.Mat4) {
+	b.camera.Set(CameraUniforms{Projection: projection, View: view})
+}
+
+// SetMaterial sets the material used by DrawQuad, DrawNineSlice and
+// DrawTiled; nil uses the default shader. Sprites carry their own material.
+func (b *SpriteBatch) SetMaterial(mat *material.Material) {
+	b.material = mat
+}
+
+func (b *SpriteBatch) GetMaterial() *material.Material {
+	return b.material
+}
+
+func (b *SpriteBatch) SetSortMode(mode SortMode) {
+	b.sortMode = mode
+}
+
+func (b *SpriteBatch) GetSortMode() SortMode {
+	return b.sortMode
+}
+
+// SetLayer sets the layer used by DrawQuad when sorting by depth.
+func (b *SpriteBatch) SetLayer(layer int) {
+	b.layer = layer
+}
+
+// SetOrder sets the order in layer used by DrawQuad when sorting by depth.
+func (b *SpriteBatch) SetOrder(order int) {
+	b.order = order
+}
+
+// SetNormalPass makes the batch draw each sprite's NormalMap instead of its
+// texture, with fallback for sprites that have none, so lighting can build a
+// normal buffer from the usual draw calls. Nil returns to normal drawing.
+func (b *SpriteBatch) SetNormalPass(fallback *texture.Texture) {
+	b.normalPass = fallback
+}
+
+// GetStats returns the statistics of the last Begin/End pair.
+func (b *SpriteBatch) GetStats() BatchStats {
+	return b.lastStats
+}
+
 func (b *SpriteBatch) Begin() {
 	b.vertices = b.vertices[:0]
+	b.quads = b.quads[:0]
+	b.textures = b.textures[:0]
 	b.spriteCount = 0
-	b.currentTex = nil
+	b.drawMaterial = nil
+	b.stats = BatchStats{}
+}
+
+// BeginTarget starts a batch that draws into a render target instead of the
+// current framebuffer. End restores the previous framebuffer.
+func (b *SpriteBatch) BeginTarget(target *texture.RenderTarget) {
+	b.Begin()
+	b.target = target
+	target.Bind()
 }
 
 func (b *SpriteBatch) End() {
 	b.Flush()
+	b.lastStats = b.stats
+	material.BlendAlpha.Apply()
+
+	if b.target != nil {
+		b.target.Unbind()
+		b.target = nil
+	}
 }
 
+// Flush sorts and draws every queued sprite, using as few draw calls as the
+// texture slots and material changes allow.
 func (b *SpriteBatch) Flush() {
+	if len(b.quads) == 0 {
+		return
+	}
+
+	b.sortQuads()
+
+	depthTest := gl.IsEnabled(gl.DEPTH_TEST)
+	if b.sortMode == SortFrontToBack {
+		gl.Enable(gl.DEPTH_TEST)
+	} else {
+		gl.Disable(gl.DEPTH_TEST)
+	}
+
+	for i := range b.quads {
+		quad := &b.quads[i]
+
+		if quad.material != b.drawMaterial {
+			b.render()
+			b.drawMaterial = quad.material
+		}
+
+		slot := b.textureSlot(quad.texture)
+		if slot < 0 || b.spriteCount >= MaxBatchSize {
+			b.render()
+			b.stats.Flushes++
+			slot = b.textureSlot(quad.texture)
+		}
+
+		texU1, texV1, texU2, texV2 := quad.uvs[0], quad.uvs[1], quad.uvs[2], quad.uvs[3]
+		textureIndex := float32(slot)
+
+		b.appendVertex(quad.corners[0], texU1, texV1, quad.color, textureIndex) // Bottom left
+		b.appendVertex(quad.corners[1], texU2, texV1, quad.color, textureIndex) // Bottom right
+		b.appendVertex(quad.corners[2], texU1, texV2, quad.color, textureIndex) // Top left
+		b.appendVertex(quad.corners[3], texU2, texV2, quad.color, textureIndex) // Top right
+
+		b.spriteCount++
+	}
+
+	b.render()
+	b.quads = b.quads[:0]
+
+	if depthTest {
+		gl.Enable(gl.DEPTH_TEST)
+	} else {
+		gl.Disable(gl.DEPTH_TEST)
+	}
+}
+
+func (b *SpriteBatch) sortQuads() {
+	var less func(i, j int) bool
+
+	switch b.sortMode {
+	case SortTexture:
+		less = func(i, j int) bool {
+			if b.quads[i].material != b.quads[j].material {
+				return b.quads[i].material.GetID() < b.quads[j].material.GetID()
+			}
+			return textureID(b.quads[i].texture) < textureID(b.quads[j].texture)
+		}
+	case SortDepth:
+		less = func(i, j int) bool { return b.quads[i].key.Less(b.quads[j].key) }
+	case SortBackToFront:
+		less = func(i, j int) bool { return b.quads[i].key.Z < b.quads[j].key.Z }
+	case SortFrontToBack:
+		less = func(i, j int) bool { return b.quads[i].key.Z > b.quads[j].key.Z }
+	default:
+		return
+	}
+
+	// Stable, so sprites that compare equal keep their submission order
+	sort.SliceStable(b.quads, less)
+}
+
+func textureID(tex *texture.Texture) uint32 {
+	if tex == nil {
+		return 0
+	}
+	return tex.ID
+}
+
+// textureSlot returns the slot a texture is bound to in the pending draw
+// call, claiming a free one if needed, or -1 when all slots are taken.
+func (b *SpriteBatch) textureSlot(tex *texture.Texture) int {
+	for i, bound := range b.textures {
+		if bound == tex {
+			return i
+		}
+	}
+	if len(b.textures) >= b.maxTextures {
+		return -1
+	}
+	b.textures = append(b.textures, tex)
+	return len(b.textures) - 1
+}
+
+func (b *SpriteBatch) render() {
 	if b.spriteCount == 0 {
 		return
 	}
@@ -72,94 +336,201 @@ func (b *SpriteBatch) Flush() {
 	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
 	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(b.vertices)*4, gl.Ptr(b.vertices))
 
-	b.shader.Use()
+	program := b.shader
+	if b.drawMaterial != nil && b.drawMaterial.Shader != nil {
+		program = b.drawMaterial.Shader
+	}
+	b.prepare(program)
+	b.camera.Bind()
+
+	if b.drawMaterial != nil {
+		b.drawMaterial.Apply(program, MaxTextureSlots)
+	} else {
+		program.Use()
+		program.SetBool("premultiplyAlpha", false)
+		material.BlendAlpha.Apply()
+	}
 
-	if b.currentTex != nil {
-		b.currentTex.Bind(0)
+	for i, tex := range b.textures {
+		if tex != nil {
+			tex.Bind(uint32(i))
+		}
 	}
 
 	gl.BindVertexArray(b.vao)
-	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(b.vertices)/VertexSize))
+	gl.DrawElements(gl.TRIANGLES, int32(b.spriteCount*IndicesPerSprite), gl.UNSIGNED_INT, nil)
 	gl.BindVertexArray(0)
 
+	b.stats.DrawCalls++
+	b.stats.Sprites += b.spriteCount
+
 	b.vertices = b.vertices[:0]
+	b.textures = b.textures[:0]
 	b.spriteCount = 0
 }
 
 func (b *SpriteBatch) Draw(sprite *Sprite) {
-	if (b.currentTex != nil && b.currentTex != sprite.Texture) || b.spriteCount >= MaxBatchSize {
-		b.Flush()
-		b.currentTex = sprite.Texture
-	} else if b.currentTex == nil {
-		b.currentTex = sprite.Texture
+	transform := sprite.GetTransform()
+	uvs := [4]float32{}
+	uvs[0], uvs[1], uvs[2], uvs[3] = sprite.GetTextureCoords()
+
+	switch {
+	case sprite.Mode == DrawSliced && sprite.Region != nil && sprite.Region.HasBorders():
+		b.drawSliced(sprite, transform, uvs)
+	case sprite.Mode == DrawTiled:
+		b.drawTiled(sprite, transform, uvs)
+	default:
+		b.drawLocal(sprite, transform, 0, 0, sprite.Size.X(), sprite.Size.Y(), uvs)
 	}
+}
+
+// DrawNineSlice stretches a region with borders over a rectangle, keeping
+// its corners at their pixel size.
+func (b *SpriteBatch) DrawNineSlice(region *texture.TextureRegion, position mgl32.Vec3, size mgl32.Vec2, color mgl32.Vec4) {
+	sprite := NewSpriteWithRegion(region, position, size)
+	sprite.Mode = DrawSliced
+	sprite.Color = color
+	sprite.Layer = b.layer
+	sprite.Order = b.order
+	sprite.Material = b.material
+	b.Draw(sprite)
+}
 
-	model := mgl32.Translate3D(sprite.Position.X(), sprite.Position.Y(), sprite.Position.Z())
+// DrawTiled repeats a region across a rectangle at the given tile size; a
+// zero tile size uses the region's pixel size.
+func (b *SpriteBatch) DrawTiled(region *texture.TextureRegion, position mgl32.Vec3, size, tileSize mgl32.Vec2, color mgl32.Vec4) {
+	sprite := NewSpriteWithRegion(region, position, size)
+	sprite.Mode = DrawTiled
+	sprite.TileSize = tileSize
+	sprite.Color = color
+	sprite.Layer = b.layer
+	sprite.Order = b.order
+	sprite.Material = b.material
+	b.Draw(sprite)
+}
 
-	if sprite.Rotation != 0 {
-		model = model.Mul4(mgl32.Translate3D(sprite.Size.X()/2, sprite.Size.Y()/2, 0))
-		model = model.Mul4(mgl32.HomogRotate3DZ(sprite.Rotation))
-		model = model.Mul4(mgl32.Translate3D(-sprite.Size.X()/2, -sprite.Size.Y()/2, 0))
+// drawLocal draws the sprite-local rectangle (x1, y1)-(x2, y2) with the
+// given UVs through the sprite's transform.
+func (b *SpriteBatch) drawLocal(sprite *Sprite, transform mgl32.Mat4, x1, y1, x2, y2 float32, uvs [4]float32) {
+	if x2 <= x1 || y2 <= y1 {
+		return
 	}
 
-	model = model.Mul4(mgl32.Scale3D(sprite.Size.X(), sprite.Size.Y(), 1.0))
+	corners := [4]mgl32.Vec3{
+		transform.Mul4x1(mgl32.Vec4{x1, y1, 0, 1}).Vec3(),
+		transform.Mul4x1(mgl32.Vec4{x2, y1, 0, 1}).Vec3(),
+		transform.Mul4x1(mgl32.Vec4{x1, y2, 0, 1}).Vec3(),
+		transform.Mul4x1(mgl32.Vec4{x2, y2, 0, 1}).Vec3(),
+	}
 
-	var texU1, texV1, texU2, texV2 float32
-	if sprite.Region != nil {
-		texU1, texV1 = sprite.Region.U1, sprite.Region.V1
-		texU2, texV2 = sprite.Region.U2, sprite.Region.V2
-	} else {
-		texU1, texV1 = 0.0, 0.0
-		texU2, texV2 = 1.0, 1.0
+	tex, color, mat := sprite.Texture, sprite.Color, sprite.Material
+	if b.normalPass != nil {
+		tex, color, mat = b.normalTexture(sprite.NormalMap), mgl32.Vec4{1, 1, 1, color.W()}, nil
 	}
+	b.submit(tex, mat, corners, uvs, color, sprite.GetSortKey())
+}
 
-	// Add the vertices to the batch (2 triangles, 6 vertices)
-	// Bottom left
-	pos := model.Mul4x1(mgl32.Vec4{0, 0, 0, 1})
-	b.vertices = append(b.vertices,
-		pos.X(), pos.Y(), pos.Z(),
-		texU1, texV1,
-		sprite.Color.X(), sprite.Color.Y(), sprite.Color.Z(), sprite.Color.W())
+func (b *SpriteBatch) normalTexture(normalMap *texture.Texture) *texture.Texture {
+	if normalMap != nil {
+		return normalMap
+	}
+	return b.normalPass
+}
 
-	// Bottom right
-	pos = model.Mul4x1(mgl32.Vec4{1, 0, 0, 1})
-	b.vertices = append(b.vertices,
-		pos.X(), pos.Y(), pos.Z(),
-		texU2, texV1,
-		sprite.Color.X(), sprite.Color.Y(), sprite.Color.Z(), sprite.Color.W())
+func (b *SpriteBatch) drawSliced(sprite *Sprite, transform mgl32.Mat4, uvs [4]float32) {
+	borders := sprite.Region.Borders
+	texWidth, texHeight := float32(sprite.Texture.Width), float32(sprite.Texture.Height)
+	width, height := sprite.Size.X(), sprite.Size.Y()
 
-	// Top left
-	pos = model.Mul4x1(mgl32.Vec4{0, 1, 0, 1})
-	b.vertices = append(b.vertices,
-		pos.X(), pos.Y(), pos.Z(),
-		texU1, texV2,
-		sprite.Color.X(), sprite.Color.Y(), sprite.Color.Z(), sprite.Color.W())
+	left, right := float32(borders.Left), float32(borders.Right)
+	top, bottom := float32(borders.Top), float32(borders.Bottom)
 
-	// Bottom right (second triangle)
-	pos = model.Mul4x1(mgl32.Vec4{1, 0, 0, 1})
-	b.vertices = append(b.vertices,
-		pos.X(), pos.Y(), pos.Z(),
-		texU2, texV1,
-		sprite.Color.X(), sprite.Color.Y(), sprite.Color.Z(), sprite.Color.W())
+	// Shrink the borders evenly when the sprite is smaller than its corners
+	if left+right > width && left+right > 0 {
+		scale := width / (left + right)
+		left, right = left*scale, right*scale
+	}
+	if top+bottom > height && top+bottom > 0 {
+		scale := height / (top + bottom)
+		top, bottom = top*scale, bottom*scale
+	}
 
-	// Top right
-	pos = model.Mul4x1(mgl32.Vec4{1, 1, 0, 1})
-	b.vertices = append(b.vertices,
-		pos.X(), pos.Y(), pos.Z(),
-		texU2, texV2,
-		sprite.Color.X(), sprite.Color.Y(), sprite.Color.Z(), sprite.Color.W())
+	xs := [4]float32{0, left, width - right, width}
+	ys := [4]float32{0, top, height - bottom, height}
+	us := [4]float32{uvs[0], uvs[0] + float32(borders.Left)/texWidth, uvs[2] - float32(borders.Right)/texWidth, uvs[2]}
+	vs := [4]float32{uvs[1], uvs[1] + float32(borders.Top)/texHeight, uvs[3] - float32(borders.Bottom)/texHeight, uvs[3]}
+
+	for row := 0; row < 3; row++ {
+		for column := 0; column < 3; column++ {
+			b.drawLocal(sprite, transform, xs[column], ys[row], xs[column+1], ys[row+1],
+				[4]float32{us[column], vs[row], us[column+1], vs[row+1]})
+		}
+	}
+}
 
-	// Top left (second triangle)
-	pos = model.Mul4x1(mgl32.Vec4{0, 1, 0, 1})
+func (b *SpriteBatch) drawTiled(sprite *Sprite, transform mgl32.Mat4, uvs [4]float32) {
+	tileSize := sprite.TileSize
+	if tileSize.X() <= 0 || tileSize.Y() <= 0 {
+		tileSize = sprite.GetSourceSize()
+	}
+	if tileSize.X() <= 0 || tileSize.Y() <= 0 {
+		return
+	}
+
+	width, height := sprite.Size.X(), sprite.Size.Y()
+	for y := float32(0); y < height; y += tileSize.Y() {
+		cellHeight := min(tileSize.Y(), height-y)
+		v2 := uvs[1] + (uvs[3]-uvs[1])*cellHeight/tileSize.Y()
+
+		for x := float32(0); x < width; x += tileSize.X() {
+			// The last tile in a row or column is cropped, not squashed
+			cellWidth := min(tileSize.X(), width-x)
+			u2 := uvs[0] + (uvs[2]-uvs[0])*cellWidth/tileSize.X()
+
+			b.drawLocal(sprite, transform, x, y, x+cellWidth, y+cellHeight, [4]float32{uvs[0], uvs[1], u2, v2})
+		}
+	}
+}
+
+// DrawQuad draws an arbitrary quad on the current layer and order. Corners are given in
+// texture order: (U1,V1), (U2,V1), (U1,V2), (U2,V2), so any affine transform
+// (including shear and mirroring) can be drawn.
+func (b *SpriteBatch) DrawQuad(tex *texture.Texture, corners [4]mgl32.Vec3, uvs [4]float32, color mgl32.Vec4) {
+	mat := b.material
+	if b.normalPass != nil {
+		tex, color, mat = b.normalPass, mgl32.Vec4{1, 1, 1, color.W()}, nil
+	}
+	b.submit(tex, mat, corners, uvs, color, quadSortKey(b.layer, b.order, corners))
+}
+
+func (b *SpriteBatch) submit(tex *texture.Texture, mat *material.Material, corners [4]mgl32.Vec3, uvs [4]float32, color mgl32.Vec4, key SortKey) {
+	// Submission order is the draw order, so there is nothing to wait for
+	if b.sortMode == SortDeferred && len(b.quads) >= MaxBatchSize {
+		b.Flush()
+		b.stats.Flushes++
+	}
+
+	b.quads = append(b.quads, batchQuad{
+		texture:  tex,
+		material: mat,
+		corners:  corners,
+		uvs:      uvs,
+		color:    color,
+		key:      key,
+	})
+}
+
+func (b *SpriteBatch) appendVertex(pos mgl32.Vec3, u, v float32, color mgl32.Vec4, textureIndex float32) {
 	b.vertices = append(b.vertices,
 		pos.X(), pos.Y(), pos.Z(),
-		texU1, texV2,
-		sprite.Color.X(), sprite.Color.Y(), sprite.Color.Z(), sprite.Color.W())
-
-	b.spriteCount++
+		u, v,
+		color.X(), color.Y(), color.Z(), color.W(),
+		textureIndex)
 }
 
 func (b *SpriteBatch) Delete() {
+	b.camera.Delete()
 	gl.DeleteBuffers(1, &b.vbo)
+	gl.DeleteBuffers(1, &b.ebo)
 	gl.DeleteVertexArrays(1, &b.vao)
 }
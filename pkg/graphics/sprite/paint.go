@@ -0,0 +1,59 @@
+package sprite
+
+import "github.com/go-gl/mathgl/mgl32"
+
+type paintKind int
+
+const (
+	paintSolid paintKind = iota
+	paintLinear
+	paintRadial
+)
+
+// Paint is the fill of a shape: a solid color or a gradient. Gradients are
+// evaluated at each vertex and interpolated across the shape, which is exact
+// for linear gradients on rectangles and close enough on curves.
+type Paint struct {
+	kind       paintKind
+	from, to   mgl32.Vec4
+	start, end mgl32.Vec2 // Linear: the gradient line; radial: the center, and end.X() as the radius
+}
+
+func SolidPaint(color mgl32.Vec4) Paint {
+	return Paint{kind: paintSolid, from: color, to: color}
+}
+
+// LinearGradient fades from one color at start to another at end, and is
+// constant past either point.
+func LinearGradient(start, end mgl32.Vec2, from, to mgl32.Vec4) Paint {
+	return Paint{kind: paintLinear, from: from, to: to, start: start, end: end}
+}
+
+// RadialGradient fades from inner at the center to outer at the radius.
+func RadialGradient(center mgl32.Vec2, radius float32, inner, outer mgl32.Vec4) Paint {
+	return Paint{kind: paintRadial, from: inner, to: outer, start: center, end: mgl32.Vec2{radius, 0}}
+}
+
+func (p Paint) colorAt(point mgl32.Vec2) mgl32.Vec4 {
+	var t float32
+
+	switch p.kind {
+	case paintLinear:
+		axis := p.end.Sub(p.start)
+		lengthSquared := axis.Dot(axis)
+		if lengthSquared == 0 {
+			return p.from
+		}
+		t = point.Sub(p.start).Dot(axis) / lengthSquared
+	case paintRadial:
+		if p.end.X() <= 0 {
+			return p.from
+		}
+		t = point.Sub(p.start).Len() / p.end.X()
+	default:
+		return p.from
+	}
+
+	t = mgl32.Clamp(t, 0, 1)
+	return p.from.Add(p.to.Sub(p.from).Mul(t))
+}
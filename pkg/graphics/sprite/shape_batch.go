@@ -0,0 +1,186 @@
+package sprite
+
+import (
+	"github.com/go-gl/gl/all-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/material"
+	"github.com/lunararch/helios/pkg/graphics/shader"
+)
+
+const (
+	MaxShapeVertices = 3 * 8192 // Vertices per draw call, as whole triangles
+	ShapeVertexSize  = 6        // 2 for position, 4 for color
+)
+
+// ShapeStats counts the work done between Begin and End.
+type ShapeStats struct {
+	DrawCalls int
+	Triangles int
+}
+
+// ShapeBatch draws untextured shapes: filled and outlined rectangles, rounded
+// rectangles, circles, polygons and thick polylines, in solid colors or
+// gradients. It shares the Camera block with SpriteBatch, so both draw
+// through the same projection and view.
+//
+// Edges are antialiased with a fringe: a strip about one screen pixel wide
+// around each shape whose alpha fades to zero, so no multisampling is needed.
+type ShapeBatch struct {
+	shader    *shader.Shader
+	vao       uint32
+	vbo       uint32
+	vertices  []float32
+	feather   float32 // Fringe width in screen pixels, 0 for hard edges
+	scale     float32 // Screen pixels per world unit, from the camera zoom
+	fringe    float32 // Fringe width in world units
+	stats     ShapeStats
+	lastStats ShapeStats
+	depthTest bool
+
+	camera   *shader.UniformBlock[CameraUniforms]
+	prepared uint32 // Program ID the shader was prepared with
+}
+
+func NewShapeBatch(shaderProgram *shader.Shader) *ShapeBatch {
+	maxSize := MaxShapeVertices * ShapeVertexSize
+
+	batch := &ShapeBatch{
+		shader:   shaderProgram,
+		vertices: make([]float32, 0, maxSize),
+		feather:  1,
+		scale:    1,
+		fringe:   1,
+		camera:   shader.NewUniformBlock[CameraUniforms](CameraBinding),
+	}
+
+	gl.GenVertexArrays(1, &batch.vao)
+	gl.GenBuffers(1, &batch.vbo)
+
+	gl.BindVertexArray(batch.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, batch.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, maxSize*4, nil, gl.DYNAMIC_DRAW)
+
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, ShapeVertexSize*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+
+	gl.VertexAttribPointer(1, 4, gl.FLOAT, false, ShapeVertexSize*4, gl.PtrOffset(2*4))
+	gl.EnableVertexAttribArray(1)
+
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+
+	batch.prepare()
+	return batch
+}
+
+// prepare points the shader's Camera block at the batch's camera, again after
+// the shader is reloaded.
+func (b *ShapeBatch) prepare() {
+	if b.prepared == b.shader.ID {
+		return
+	}
+
+	b.shader.BindUniformBlock("Camera", CameraBinding)
+	b.prepared = b.shader.ID
+}
+
+// SetCamera sets the projection and view shapes are drawn with. The view's
+// scale also sets how wide the antialiasing fringe is in world units, so
+// edges stay one pixel soft at any zoom.
+func (b *ShapeBatch) SetCamera(projection, view mgl32.Mat4) {
+	b.camera.Set(CameraUniforms{Projection: projection, View: view})
+
+	b.scale = view.Col(0).Vec3().Len()
+	if b.scale <= 0 {
+		b.scale = 1
+	}
+	b.fringe = b.feather / b.scale
+}
+
+// SetFeather sets the width of antialiased edges in screen pixels; 0 turns
+// antialiasing off, for pixel art and text. It applies to shapes drawn after
+// it.
+func (b *ShapeBatch) SetFeather(pixels float32) {
+	b.feather = max(pixels, 0)
+	b.fringe = b.feather / b.scale
+}
+
+func (b *ShapeBatch) GetFeather() float32 {
+	return b.feather
+}
+
+// GetStats returns the statistics of the last Begin/End pair.
+func (b *ShapeBatch) GetStats() ShapeStats {
+	return b.lastStats
+}
+
+// Begin starts a batch. Shapes are drawn in the order they are submitted,
+// with the depth test off so later shapes always cover earlier ones.
+func (b *ShapeBatch) Begin() {
+	b.vertices = b.vertices[:0]
+	b.stats = ShapeStats{}
+	b.depthTest = gl.IsEnabled(gl.DEPTH_TEST)
+	gl.Disable(gl.DEPTH_TEST)
+}
+
+func (b *ShapeBatch) End() {
+	b.Flush()
+	b.lastStats = b.stats
+
+	if b.depthTest {
+		gl.Enable(gl.DEPTH_TEST)
+	}
+}
+
+// Flush draws every queued shape with one draw call.
+func (b *ShapeBatch) Flush() {
+	if len(b.vertices) == 0 {
+		return
+	}
+
+	b.prepare()
+	b.camera.Bind()
+	b.shader.Use()
+	material.BlendAlpha.Apply()
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(b.vertices)*4, gl.Ptr(b.vertices))
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+
+	count := len(b.vertices) / ShapeVertexSize
+	gl.BindVertexArray(b.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(count))
+	gl.BindVertexArray(0)
+
+	b.stats.DrawCalls++
+	b.stats.Triangles += count / 3
+	b.vertices = b.vertices[:0]
+}
+
+func (b *ShapeBatch) triangle(p1, p2, p3 mgl32.Vec2, c1, c2, c3 mgl32.Vec4) {
+	if len(b.vertices)+3*ShapeVertexSize > cap(b.vertices) {
+		b.Flush()
+	}
+
+	b.appendVertex(p1, c1)
+	b.appendVertex(p2, c2)
+	b.appendVertex(p3, c3)
+}
+
+// quad queues two triangles; the corners go around the edge in order.
+func (b *ShapeBatch) quad(p1, p2, p3, p4 mgl32.Vec2, c1, c2, c3, c4 mgl32.Vec4) {
+	b.triangle(p1, p2, p3, c1, c2, c3)
+	b.triangle(p1, p3, p4, c1, c3, c4)
+}
+
+func (b *ShapeBatch) appendVertex(pos mgl32.Vec2, color mgl32.Vec4) {
+	b.vertices = append(b.vertices,
+		pos.X(), pos.Y(),
+		color.X(), color.Y(), color.Z(), color.W())
+}
+
+func (b *ShapeBatch) Delete() {
+	b.camera.Delete()
+	gl.DeleteBuffers(1, &b.vbo)
+	gl.DeleteVertexArrays(1, &b.vao)
+}
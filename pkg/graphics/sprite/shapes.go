@@ -0,0 +1,386 @@
+package sprite
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	miterLimit    = 4    // Longest miter at a sharp corner, in half widths; sharper corners are cut short
+	arcTolerance  = 0.25 // Furthest a curve's segments stray from the true curve, in screen pixels
+	maxArcSegment = 128  // Segments in a full circle, at most
+)
+
+// Outlines are centered on the shape's edge, so a stroke of thickness t
+// reaches t/2 outside and inside it.
+
+// FillRect fills the box between two corners.
+func (b *ShapeBatch) FillRect(minPoint, maxPoint mgl32.Vec2, paint Paint) {
+	b.fillPath(rectPoints(minPoint, maxPoint), paint)
+}
+
+func (b *ShapeBatch) StrokeRect(minPoint, maxPoint mgl32.Vec2, thickness float32, paint Paint) {
+	b.strokePath(rectPoints(minPoint, maxPoint), thickness, paint, true)
+}
+
+// FillRoundedRect fills a box with corners rounded to a radius, which is
+// limited to half the shorter side.
+func (b *ShapeBatch) FillRoundedRect(minPoint, maxPoint mgl32.Vec2, radius float32, paint Paint) {
+	b.fillPath(b.roundedRectPoints(minPoint, maxPoint, radius), paint)
+}
+
+func (b *ShapeBatch) StrokeRoundedRect(minPoint, maxPoint mgl32.Vec2, radius, thickness float32, paint Paint) {
+	b.strokePath(b.roundedRectPoints(minPoint, maxPoint, radius), thickness, paint, true)
+}
+
+func (b *ShapeBatch) FillCircle(center mgl32.Vec2, radius float32, paint Paint) {
+	b.fillPath(b.arcPoints(nil, center, radius, 0, 2*math.Pi, false), paint)
+}
+
+func (b *ShapeBatch) StrokeCircle(center mgl32.Vec2, radius, thickness float32, paint Paint) {
+	b.strokePath(b.arcPoints(nil, center, radius, 0, 2*math.Pi, false), thickness, paint, true)
+}
+
+// FillPolygon fills a simple polygon, convex or concave, in either winding.
+// Self-intersecting polygons are filled as well as the triangulation allows.
+func (b *ShapeBatch) FillPolygon(points []mgl32.Vec2, paint Paint) {
+	b.fillPath(points, paint)
+}
+
+func (b *ShapeBatch) StrokePolygon(points []mgl32.Vec2, thickness float32, paint Paint) {
+	b.strokePath(points, thickness, paint, true)
+}
+
+// Polyline draws a thick line through the points with mitered joins and flat
+// ends.
+func (b *ShapeBatch) Polyline(points []mgl32.Vec2, thickness float32, paint Paint) {
+	b.strokePath(points, thickness, paint, false)
+}
+
+func (b *ShapeBatch) Line(from, to mgl32.Vec2, thickness float32, paint Paint) {
+	b.strokePath([]mgl32.Vec2{from, to}, thickness, paint, false)
+}
+
+func rectPoints(minPoint, maxPoint mgl32.Vec2) []mgl32.Vec2 {
+	return []mgl32.Vec2{
+		minPoint, {maxPoint.X(), minPoint.Y()}, maxPoint, {minPoint.X(), maxPoint.Y()},
+	}
+}
+
+func (b *ShapeBatch) roundedRectPoints(minPoint, maxPoint mgl32.Vec2, radius float32) []mgl32.Vec2 {
+	size := maxPoint.Sub(minPoint)
+	radius = min(radius, min(abs(size.X()), abs(size.Y()))/2)
+	if radius <= 0 {
+		return rectPoints(minPoint, maxPoint)
+	}
+
+	// Corners clockwise on screen from the top left, each a quarter turn
+	points := make([]mgl32.Vec2, 0)
+	points = b.arcPoints(points, mgl32.Vec2{minPoint.X() + radius, minPoint.Y() + radius}, radius, math.Pi, 1.5*math.Pi, true)
+	points = b.arcPoints(points, mgl32.Vec2{maxPoint.X() - radius, minPoint.Y() + radius}, radius, 1.5*math.Pi, 2*math.Pi, true)
+	points = b.arcPoints(points, mgl32.Vec2{maxPoint.X() - radius, maxPoint.Y() - radius}, radius, 0, 0.5*math.Pi, true)
+	points = b.arcPoints(points, mgl32.Vec2{minPoint.X() + radius, maxPoint.Y() - radius}, radius, 0.5*math.Pi, math.Pi, true)
+	return points
+}
+
+// arcPoints appends points along an arc, with as many segments as the arc
+// needs to look smooth at the current zoom. Closed circles leave out the end
+// point, which would repeat the start.
+func (b *ShapeBatch) arcPoints(points []mgl32.Vec2, center mgl32.Vec2, radius float32, from, to float64, includeEnd bool) []mgl32.Vec2 {
+	if radius <= 0 {
+		return points
+	}
+
+	count := b.arcSegments(radius, to-from)
+	last := count - 1
+	if includeEnd {
+		last = count
+	}
+	for i := 0; i <= last; i++ {
+		angle := from + (to-from)*float64(i)/float64(count)
+		points = append(points, center.Add(mgl32.Vec2{float32(math.Cos(angle)), float32(math.Sin(angle))}.Mul(radius)))
+	}
+	return points
+}
+
+func (b *ShapeBatch) arcSegments(radius float32, angle float64) int {
+	pixelRadius := float64(radius * b.scale)
+
+	minimum := max(2, int(math.Ceil(angle/(math.Pi/6))))
+	if pixelRadius <= arcTolerance {
+		return minimum
+	}
+
+	// Each segment may turn as far as keeps its middle within the tolerance
+	step := 2 * math.Acos(1-arcTolerance/pixelRadius)
+	count := int(math.Ceil(angle / step))
+	return max(minimum, min(count, int(math.Ceil(maxArcSegment*angle/(2*math.Pi)))))
+}
+
+// fillPath fills a closed outline. The fill is inset by half the fringe and
+// the fringe fades out past the edge, so the visible edge sits on the
+// outline.
+func (b *ShapeBatch) fillPath(points []mgl32.Vec2, paint Paint) {
+	points = dedupePoints(points, true)
+	if len(points) < 3 {
+		return
+	}
+
+	area := signedArea(points)
+	if area == 0 {
+		return
+	}
+
+	offsets := vertexOffsets(points, edgeNormals(points, area > 0), true)
+	inner := points
+	if b.fringe > 0 {
+		inner = make([]mgl32.Vec2, len(points))
+		for i, point := range points {
+			inner[i] = point.Sub(offsets[i].Mul(b.fringe / 2))
+		}
+	}
+
+	colors := make([]mgl32.Vec4, len(inner))
+	for i, point := range inner {
+		colors[i] = paint.colorAt(point)
+	}
+
+	for _, tri := range triangulate(points, area > 0) {
+		b.triangle(inner[tri[0]], inner[tri[1]], inner[tri[2]], colors[tri[0]], colors[tri[1]], colors[tri[2]])
+	}
+
+	if b.fringe <= 0 {
+		return
+	}
+	for i := range points {
+		j := (i + 1) % len(points)
+		outerI := points[i].Add(offsets[i].Mul(b.fringe / 2))
+		outerJ := points[j].Add(offsets[j].Mul(b.fringe / 2))
+		b.quad(inner[i], outerI, outerJ, inner[j],
+			colors[i], transparent(paint.colorAt(outerI)), transparent(paint.colorAt(outerJ)), colors[j])
+	}
+}
+
+// strokePath draws a thick line along the points as a solid core with a fringe
+// on both sides. Lines thinner than the fringe are drawn fringe-wide and
+// fainter instead, which reads as thinner without breaking into dashes.
+func (b *ShapeBatch) strokePath(points []mgl32.Vec2, thickness float32, paint Paint, closed bool) {
+	points = dedupePoints(points, closed)
+	if len(points) < 2 || thickness <= 0 || (closed && len(points) < 3) {
+		return
+	}
+
+	alpha := float32(1)
+	if thickness < b.fringe {
+		alpha = thickness / b.fringe
+		thickness = b.fringe
+	}
+	coreHalf := (thickness - b.fringe) / 2
+	outerHalf := (thickness + b.fringe) / 2
+
+	// Left-hand side normals; the winding doesn't matter for strokes
+	sides := edgeNormals(points, false)
+	offsets := vertexOffsets(points, sides, closed)
+
+	count := len(points)
+	left := make([]mgl32.Vec2, count)
+	right := make([]mgl32.Vec2, count)
+	colors := make([]mgl32.Vec4, count)
+	for i, point := range points {
+		left[i] = point.Add(offsets[i].Mul(coreHalf))
+		right[i] = point.Sub(offsets[i].Mul(coreHalf))
+		colors[i] = paint.colorAt(point)
+		colors[i][3] *= alpha
+	}
+
+	segments := count - 1
+	if closed {
+		segments = count
+	}
+	for i := 0; i < segments; i++ {
+		j := (i + 1) % count
+		b.quad(left[i], left[j], right[j], right[i], colors[i], colors[j], colors[j], colors[i])
+	}
+
+	if b.fringe <= 0 {
+		return
+	}
+
+	leftOuter := make([]mgl32.Vec2, count)
+	rightOuter := make([]mgl32.Vec2, count)
+	for i, point := range points {
+		leftOuter[i] = point.Add(offsets[i].Mul(outerHalf))
+		rightOuter[i] = point.Sub(offsets[i].Mul(outerHalf))
+	}
+	for i := 0; i < segments; i++ {
+		j := (i + 1) % count
+		clearI, clearJ := transparent(colors[i]), transparent(colors[j])
+		b.quad(leftOuter[i], leftOuter[j], left[j], left[i], clearI, clearJ, colors[j], colors[i])
+		b.quad(right[i], right[j], rightOuter[j], rightOuter[i], colors[i], colors[j], clearJ, clearI)
+	}
+
+	if !closed {
+		first := points[1].Sub(points[0]).Normalize()
+		last := points[count-1].Sub(points[count-2]).Normalize()
+		b.capFringe(left[0], right[0], leftOuter[0], rightOuter[0], first.Mul(-b.fringe/2), colors[0])
+		b.capFringe(right[count-1], left[count-1], rightOuter[count-1], leftOuter[count-1], last.Mul(b.fringe/2), colors[count-1])
+	}
+}
+
+// capFringe fades out the flat end of a line, past the corners too.
+func (b *ShapeBatch) capFringe(left, right, leftOuter, rightOuter, back mgl32.Vec2, color mgl32.Vec4) {
+	clearColor := transparent(color)
+	b.quad(left, right, rightOuter.Add(back), leftOuter.Add(back), color, color, clearColor, clearColor)
+	b.triangle(left, leftOuter.Add(back), leftOuter, color, clearColor, clearColor)
+	b.triangle(right, rightOuter, rightOuter.Add(back), color, clearColor, clearColor)
+}
+
+func abs(value float32) float32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func transparent(color mgl32.Vec4) mgl32.Vec4 {
+	return mgl32.Vec4{color.X(), color.Y(), color.Z(), 0}
+}
+
+// dedupePoints drops points that repeat the one before them, which have no
+// direction to build normals from.
+func dedupePoints(points []mgl32.Vec2, closed bool) []mgl32.Vec2 {
+	const epsilon = 1e-6
+
+	result := make([]mgl32.Vec2, 0, len(points))
+	for _, point := range points {
+		if len(result) > 0 && point.Sub(result[len(result)-1]).LenSqr() < epsilon {
+			continue
+		}
+		result = append(result, point)
+	}
+	if closed && len(result) > 1 && result[0].Sub(result[len(result)-1]).LenSqr() < epsilon {
+		result = result[:len(result)-1]
+	}
+	return result
+}
+
+// signedArea is positive when the points turn from +X towards +Y.
+func signedArea(points []mgl32.Vec2) float32 {
+	var area float32
+	for i, point := range points {
+		next := points[(i+1)%len(points)]
+		area += point.X()*next.Y() - next.X()*point.Y()
+	}
+	return area / 2
+}
+
+// edgeNormals returns a unit normal for each edge from point i to i+1. With
+// outward set, the normals point out of a polygon wound towards +Y; otherwise
+// they point to the left of the edge.
+func edgeNormals(points []mgl32.Vec2, outward bool) []mgl32.Vec2 {
+	normals := make([]mgl32.Vec2, len(points))
+	for i, point := range points {
+		direction := points[(i+1)%len(points)].Sub(point).Normalize()
+		if outward {
+			normals[i] = mgl32.Vec2{direction.Y(), -direction.X()}
+		} else {
+			normals[i] = mgl32.Vec2{-direction.Y(), direction.X()}
+		}
+	}
+	return normals
+}
+
+// vertexOffsets returns, for each point, the direction to move it so both
+// edges meeting there move one unit along their normals: the miter. Open ends
+// use their only edge's normal.
+func vertexOffsets(points, normals []mgl32.Vec2, closed bool) []mgl32.Vec2 {
+	count := len(points)
+	offsets := make([]mgl32.Vec2, count)
+	for i := range points {
+		previous := normals[(i+count-1)%count]
+		next := normals[i]
+		if !closed {
+			if i == 0 {
+				previous = next
+			} else if i == count-1 {
+				next = previous
+			}
+		}
+
+		miter := previous.Add(next)
+		if miter.LenSqr() < 1e-6 {
+			// The line doubles back on itself
+			offsets[i] = next
+			continue
+		}
+		miter = miter.Normalize()
+
+		scale := 1 / max(miter.Dot(next), 1/miterLimit)
+		offsets[i] = miter.Mul(scale)
+	}
+	return offsets
+}
+
+// triangulate splits a simple polygon into triangles by ear clipping,
+// returning indices into points. positive is the sign of its signed area.
+func triangulate(points []mgl32.Vec2, positive bool) [][3]int {
+	remaining := make([]int, len(points))
+	for i := range remaining {
+		remaining[i] = i
+	}
+	triangles := make([][3]int, 0, len(points)-2)
+
+	cross := func(a, b, c mgl32.Vec2) float32 {
+		value := (b.X()-a.X())*(c.Y()-a.Y()) - (b.Y()-a.Y())*(c.X()-a.X())
+		if !positive {
+			return -value
+		}
+		return value
+	}
+
+	for len(remaining) > 3 {
+		found := false
+		for i := range remaining {
+			prev := remaining[(i+len(remaining)-1)%len(remaining)]
+			curr := remaining[i]
+			next := remaining[(i+1)%len(remaining)]
+			a, b, c := points[prev], points[curr], points[next]
+
+			if cross(a, b, c) <= 0 {
+				continue // Reflex corner
+			}
+
+			ear := true
+			for _, other := range remaining {
+				if other == prev || other == curr || other == next {
+					continue
+				}
+				// Points on the ear's outer edges are fine; on the new diagonal they aren't
+				p := points[other]
+				if cross(a, b, p) > 0 && cross(b, c, p) > 0 && cross(c, a, p) >= 0 {
+					ear = false
+					break
+				}
+			}
+			if !ear {
+				continue
+			}
+
+			triangles = append(triangles, [3]int{prev, curr, next})
+			remaining = append(remaining[:i], remaining[i+1:]...)
+			found = true
+			break
+		}
+
+		if !found {
+			// Self-intersecting or degenerate; fan out what is left
+			for i := 1; i+1 < len(remaining); i++ {
+				triangles = append(triangles, [3]int{remaining[0], remaining[i], remaining[i+1]})
+			}
+			return triangles
+		}
+	}
+
+	return append(triangles, [3]int{remaining[0], remaining[1], remaining[2]})
+}
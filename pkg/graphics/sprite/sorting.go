@@ -0,0 +1,82 @@
+package sprite
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Named sorting layers and their values; lower values draw first. Sprites
+// can also use any other value directly.
+var sortingLayers = map[string]int{
+	"background": -100,
+	"default":    0,
+	"foreground": 100,
+	"ui":         1000,
+}
+
+// Layers whose sprites are also ordered by Y, for top-down games
+var ySortedLayers = make(map[int]bool)
+
+// AddSortingLayer names a layer value, or renames an existing one.
+func AddSortingLayer(name string, value int) {
+	sortingLayers[name] = value
+}
+
+func SortingLayerFromName(name string) (int, bool) {
+	value, exists := sortingLayers[name]
+	return value, exists
+}
+
+// SetYSorted makes sprites on a layer with the same order draw from the top
+// of the screen down, so ones lower on screen overlap the ones behind them.
+func SetYSorted(layer int, ySorted bool) {
+	if ySorted {
+		ySortedLayers[layer] = true
+	} else {
+		delete(ySortedLayers, layer)
+	}
+}
+
+func IsYSorted(layer int) bool {
+	return ySortedLayers[layer]
+}
+
+// SortKey is what painter's order compares, most significant first:
+// sorting layer, order in layer, Y on y-sorted layers, then Z.
+type SortKey struct {
+	Layer int
+	Order int
+	Y     float32 // Usually the sprite's feet
+	Z     float32 // Higher is nearer, so draws later
+}
+
+func (k SortKey) Less(other SortKey) bool {
+	if k.Layer != other.Layer {
+		return k.Layer < other.Layer
+	}
+	if k.Order != other.Order {
+		return k.Order < other.Order
+	}
+	if k.Y != other.Y && IsYSorted(k.Layer) {
+		return k.Y < other.Y
+	}
+	return k.Z < other.Z
+}
+
+// GetSortKey returns the sprite's sort key. Its Y is the rotation origin,
+// which SpriteComponent.SetPivot also places at the entity position.
+func (s *Sprite) GetSortKey() SortKey {
+	return SortKey{
+		Layer: s.Layer,
+		Order: s.Order,
+		Y:     s.Position.Y() + s.Origin.Y()*s.Size.Y(),
+		Z:     s.Position.Z(),
+	}
+}
+
+// quadSortKey is the key for a quad drawn without a sprite; its Y is the
+// lowest corner.
+func quadSortKey(layer, order int, corners [4]mgl32.Vec3) SortKey {
+	y := corners[0].Y()
+	for _, corner := range corners[1:] {
+		y = max(y, corner.Y())
+	}
+	return SortKey{Layer: layer, Order: order, Y: y, Z: corners[0].Z()}
+}
@@ -2,16 +2,35 @@ package sprite
 
 import (
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/material"
 	"github.com/lunararch/helios/pkg/graphics/texture"
 )
 
+// DrawMode decides how a sprite's texture fills its size.
+type DrawMode int
+
+const (
+	DrawSimple DrawMode = iota // Stretch the whole region over the sprite
+	DrawSliced                 // Nine-slice using the region's borders
+	DrawTiled                  // Repeat the region at TileSize
+)
+
 type Sprite struct {
-	Texture  *texture.Texture
-	Region   *texture.TextureRegion // Optional texture region for sprite sheets
-	Position mgl32.Vec3
-	Size     mgl32.Vec2
-	Rotation float32
-	Color    mgl32.Vec4
+	Texture   *texture.Texture
+	NormalMap *texture.Texture       // Optional, laid out like Texture; drawn by the lighting normal pass
+	Region    *texture.TextureRegion // Optional texture region for sprite sheets
+	Position  mgl32.Vec3             // Top-left corner before rotation
+	Size      mgl32.Vec2
+	Rotation  float32
+	Origin    mgl32.Vec2 // Rotation pivot as a fraction of Size; (0.5, 0.5) is the center
+	FlipX     bool
+	FlipY     bool
+	Mode      DrawMode
+	TileSize  mgl32.Vec2 // Size of one tile in DrawTiled mode; zero uses the region's pixel size
+	Color     mgl32.Vec4
+	Layer     int                // Sorting layer, used when the batch sorts by depth; see SortingLayerFromName
+	Order     int                // Order within the layer; higher draws on top
+	Material  *material.Material // Nil draws with the batch's default shader
 }
 
 func NewSprite(tex *texture.Texture, position mgl32.Vec3, size mgl32.Vec2) *Sprite {
@@ -21,6 +40,7 @@ func NewSprite(tex *texture.Texture, position mgl32.Vec3, size mgl32.Vec2) *Spri
 		Position: position,
 		Size:     size,
 		Rotation: 0,
+		Origin:   mgl32.Vec2{0.5, 0.5},
 		Color:    mgl32.Vec4{1.0, 1.0, 1.0, 1.0},
 	}
 }
@@ -32,6 +52,7 @@ func NewSpriteWithRegion(region *texture.TextureRegion, position mgl32.Vec3, siz
 		Position: position,
 		Size:     size,
 		Rotation: 0,
+		Origin:   mgl32.Vec2{0.5, 0.5},
 		Color:    mgl32.Vec4{1.0, 1.0, 1.0, 1.0},
 	}
 
@@ -55,3 +76,61 @@ func (s *Sprite) GetTextureCoords() (u1, v1, u2, v2 float32) {
 	}
 	return 0.0, 0.0, 1.0, 1.0
 }
+
+// GetSourceSize returns the size in texture pixels of the drawn region.
+func (s *Sprite) GetSourceSize() mgl32.Vec2 {
+	if s.Region != nil {
+		return mgl32.Vec2{float32(s.Region.GetWidth()), float32(s.Region.GetHeight())}
+	}
+	if s.Texture != nil {
+		return mgl32.Vec2{float32(s.Texture.Width), float32(s.Texture.Height)}
+	}
+	return mgl32.Vec2{}
+}
+
+// GetTransform maps sprite-local pixels, (0, 0) to Size, into world space,
+// applying flips, rotation around Origin and Position.
+func (s *Sprite) GetTransform() mgl32.Mat4 {
+	transform := mgl32.Translate3D(s.Position.X(), s.Position.Y(), s.Position.Z())
+
+	if s.Rotation != 0 {
+		pivot := mgl32.Vec2{s.Origin.X() * s.Size.X(), s.Origin.Y() * s.Size.Y()}
+		transform = transform.Mul4(mgl32.Translate3D(pivot.X(), pivot.Y(), 0))
+		transform = transform.Mul4(mgl32.HomogRotate3DZ(s.Rotation))
+		transform = transform.Mul4(mgl32.Translate3D(-pivot.X(), -pivot.Y(), 0))
+	}
+
+	if s.FlipX || s.FlipY {
+		scaleX, scaleY := float32(1), float32(1)
+		if s.FlipX {
+			scaleX = -1
+		}
+		if s.FlipY {
+			scaleY = -1
+		}
+		// Mirror in place so flipping doesn't move the sprite
+		transform = transform.Mul4(mgl32.Translate3D(s.Size.X()/2, s.Size.Y()/2, 0))
+		transform = transform.Mul4(mgl32.Scale3D(scaleX, scaleY, 1))
+		transform = transform.Mul4(mgl32.Translate3D(-s.Size.X()/2, -s.Size.Y()/2, 0))
+	}
+
+	return transform
+}
+
+// GetBounds returns the world-space box around the sprite's quad, after
+// rotation and flips.
+func (s *Sprite) GetBounds() (minPoint, maxPoint mgl32.Vec2) {
+	transform := s.GetTransform()
+
+	corners := [4]mgl32.Vec2{{0, 0}, {s.Size.X(), 0}, {s.Size.X(), s.Size.Y()}, {0, s.Size.Y()}}
+	for i, corner := range corners {
+		world := transform.Mul4x1(mgl32.Vec4{corner.X(), corner.Y(), 0, 1}).Vec2()
+		if i == 0 {
+			minPoint, maxPoint = world, world
+			continue
+		}
+		minPoint = mgl32.Vec2{min(minPoint.X(), world.X()), min(minPoint.Y(), world.Y())}
+		maxPoint = mgl32.Vec2{max(maxPoint.X(), world.X()), max(maxPoint.Y(), world.Y())}
+	}
+	return minPoint, maxPoint
+}
@@ -0,0 +1,191 @@
+package texture
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+)
+
+// Atlas is a packed atlas uploaded to the GPU. Sprites drawn from the same
+// page share a texture, so SpriteBatch doesn't flush between them.
+type Atlas struct {
+	Pages   []*Texture
+	Regions map[string]*TextureRegion
+}
+
+// NewAtlas uploads every page of a packed atlas.
+func NewAtlas(packed *PackedAtlas) (*Atlas, error) {
+	atlas := &Atlas{
+		Pages:   make([]*Texture, 0, len(packed.Pages)),
+		Regions: make(map[string]*TextureRegion),
+	}
+
+	for _, page := range packed.Pages {
+		tex, err := LoadFromImage(page)
+		if err != nil {
+			atlas.Delete()
+			return nil, fmt.Errorf("failed to upload atlas page: %w", err)
+		}
+		atlas.Pages = append(atlas.Pages, tex)
+	}
+
+	for _, region := range packed.Regions {
+		atlas.Regions[region.Name] = NewTextureRegionFromPixels(
+			atlas.Pages[region.Page], region.X, region.Y, region.Width, region.Height)
+	}
+
+	return atlas, nil
+}
+
+// PackDirectory packs every image in dir and uploads the result.
+func PackDirectory(dir string, config PackerConfig) (*Atlas, error) {
+	packer := NewAtlasPacker(config)
+	if err := packer.AddDirectory(dir); err != nil {
+		return nil, err
+	}
+
+	packed, err := packer.Pack()
+	if err != nil {
+		return nil, err
+	}
+	return NewAtlas(packed)
+}
+
+func (a *Atlas) GetRegion(name string) (*TextureRegion, error) {
+	region, exists := a.Regions[name]
+	if !exists {
+		return nil, fmt.Errorf("atlas region '%s' not found", name)
+	}
+	return region, nil
+}
+
+func (a *Atlas) Delete() {
+	for _, page := range a.Pages {
+		page.Delete()
+	}
+	a.Pages = nil
+}
+
+// An atlas manifest sits next to its page images:
+//
+//	{
+//	  "pages": [{"image": "sprites_0.png", "width": 512, "height": 256}],
+//	  "regions": {"knight": {"page": 0, "x": 1, "y": 1, "width": 187, "height": 256}}
+//	}
+type atlasManifest struct {
+	Pages   []manifestPage            `json:"pages"`
+	Regions map[string]manifestRegion `json:"regions"`
+}
+
+type manifestPage struct {
+	Image  string `json:"image"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type manifestRegion struct {
+	Page   int `json:"page"`
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Save writes name_N.png for each page and name.json into dir, so atlases
+// can be packed as a build step and loaded with LoadAtlas.
+func (pa *PackedAtlas) Save(dir, name string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create atlas directory: %w", err)
+	}
+
+	manifest := atlasManifest{
+		Pages:   make([]manifestPage, 0, len(pa.Pages)),
+		Regions: make(map[string]manifestRegion, len(pa.Regions)),
+	}
+
+	for i, page := range pa.Pages {
+		imageName := fmt.Sprintf("%s_%d.png", name, i)
+		if err := writePNG(filepath.Join(dir, imageName), page); err != nil {
+			return err
+		}
+		manifest.Pages = append(manifest.Pages, manifestPage{
+			Image:  imageName,
+			Width:  page.Bounds().Dx(),
+			Height: page.Bounds().Dy(),
+		})
+	}
+
+	for _, region := range pa.Regions {
+		manifest.Regions[region.Name] = manifestRegion{
+			Page:   region.Page,
+			X:      region.X,
+			Y:      region.Y,
+			Width:  region.Width,
+			Height: region.Height,
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode atlas manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".json"), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write atlas manifest: %w", err)
+	}
+	return nil
+}
+
+func writePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create atlas page: %w", err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, img); err != nil {
+		return fmt.Errorf("failed to encode atlas page: %w", err)
+	}
+	return nil
+}
+
+// LoadAtlas loads an atlas written by PackedAtlas.Save.
+func LoadAtlas(manifestPath string) (*Atlas, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read atlas manifest: %w", err)
+	}
+
+	var manifest atlasManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse atlas manifest: %w", err)
+	}
+
+	atlas := &Atlas{
+		Pages:   make([]*Texture, 0, len(manifest.Pages)),
+		Regions: make(map[string]*TextureRegion, len(manifest.Regions)),
+	}
+
+	dir := filepath.Dir(manifestPath)
+	for _, page := range manifest.Pages {
+		tex, err := LoadFromFile(filepath.Join(dir, page.Image))
+		if err != nil {
+			atlas.Delete()
+			return nil, fmt.Errorf("failed to load atlas page '%s': %w", page.Image, err)
+		}
+		atlas.Pages = append(atlas.Pages, tex)
+	}
+
+	for name, region := range manifest.Regions {
+		if region.Page < 0 || region.Page >= len(atlas.Pages) {
+			atlas.Delete()
+			return nil, fmt.Errorf("atlas region '%s' references missing page %d", name, region.Page)
+		}
+		atlas.Regions[name] = NewTextureRegionFromPixels(
+			atlas.Pages[region.Page], region.X, region.Y, region.Width, region.Height)
+	}
+
+	return atlas, nil
+}
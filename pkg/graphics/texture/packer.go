@@ -0,0 +1,300 @@
+package texture
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PackerConfig controls how images are laid out on atlas pages.
+type PackerConfig struct {
+	MaxWidth   int  // Largest page width in pixels
+	MaxHeight  int  // Largest page height in pixels
+	Padding    int  // Transparent pixels left between neighbouring images
+	Extrude    int  // Edge pixels repeated around each image so filtering doesn't bleed
+	PowerOfTwo bool // Round page sizes up to powers of two
+	MultiPage  bool // Open a new page when one is full instead of failing
+}
+
+func DefaultPackerConfig() PackerConfig {
+	return PackerConfig{
+		MaxWidth:   2048,
+		MaxHeight:  2048,
+		Padding:    2,
+		Extrude:    1,
+		PowerOfTwo: true,
+		MultiPage:  true,
+	}
+}
+
+// PackedRegion is where an image ended up, in pixels on its page.
+type PackedRegion struct {
+	Name   string
+	Page   int
+	X, Y   int
+	Width  int
+	Height int
+}
+
+// PackedAtlas is the CPU side of a packed atlas. Upload it with NewAtlas or
+// write it to disk with Save.
+type PackedAtlas struct {
+	Pages   []*image.RGBA
+	Regions []PackedRegion
+}
+
+// AtlasPacker packs images into atlas pages using the MaxRects algorithm with
+// the best-short-side-fit heuristic.
+type AtlasPacker struct {
+	config PackerConfig
+	images map[string]image.Image
+	names  []string
+}
+
+func NewAtlasPacker(config PackerConfig) *AtlasPacker {
+	return &AtlasPacker{
+		config: config,
+		images: make(map[string]image.Image),
+		names:  make([]string, 0),
+	}
+}
+
+func (ap *AtlasPacker) Add(name string, img image.Image) error {
+	if _, exists := ap.images[name]; exists {
+		return fmt.Errorf("atlas already contains an image named '%s'", name)
+	}
+
+	ap.images[name] = img
+	ap.names = append(ap.names, name)
+	return nil
+}
+
+func (ap *AtlasPacker) AddFile(name, filePath string) error {
+	imgFile, err := os.Open(filepath.Clean(filePath))
+	if err != nil {
+		return fmt.Errorf("failed to open image file: %w", err)
+	}
+	defer imgFile.Close()
+
+	img, _, err := image.Decode(imgFile)
+	if err != nil {
+		return fmt.Errorf("failed to decode image '%s': %w", filePath, err)
+	}
+	return ap.Add(name, img)
+}
+
+// AddDirectory adds every PNG and JPEG in dir, named after the file without
+// its extension.
+func (ap *AtlasPacker) AddDirectory(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".png" && ext != ".jpg" && ext != ".jpeg") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if err := ap.AddFile(name, filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type packRect struct {
+	x, y, width, height int
+}
+
+type packPage struct {
+	free         []packRect
+	usedW, usedH int
+	placed       []int // Indexes into the packed regions
+}
+
+func (ap *AtlasPacker) Pack() (*PackedAtlas, error) {
+	if len(ap.names) == 0 {
+		return nil, fmt.Errorf("atlas has no images to pack")
+	}
+
+	border := ap.config.Extrude*2 + ap.config.Padding
+
+	// Big images first leaves the small ones to fill the gaps
+	order := append([]string(nil), ap.names...)
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := ap.images[order[i]].Bounds(), ap.images[order[j]].Bounds()
+		return max(a.Dx(), a.Dy()) > max(b.Dx(), b.Dy())
+	})
+
+	atlas := &PackedAtlas{Regions: make([]PackedRegion, 0, len(order))}
+	pages := make([]*packPage, 0)
+
+	for _, name := range order {
+		bounds := ap.images[name].Bounds()
+		width, height := bounds.Dx()+border, bounds.Dy()+border
+
+		if width > ap.config.MaxWidth+ap.config.Padding || height > ap.config.MaxHeight+ap.config.Padding {
+			return nil, fmt.Errorf("image '%s' (%dx%d) does not fit in a %dx%d page",
+				name, bounds.Dx(), bounds.Dy(), ap.config.MaxWidth, ap.config.MaxHeight)
+		}
+
+		pageIndex, rect, found := -1, packRect{}, false
+		for i, page := range pages {
+			if rect, found = page.find(width, height); found {
+				pageIndex = i
+				break
+			}
+		}
+
+		if !found {
+			if len(pages) > 0 && !ap.config.MultiPage {
+				return nil, fmt.Errorf("image '%s' does not fit in the atlas page", name)
+			}
+			// The trailing padding may hang off the page edge
+			pages = append(pages, &packPage{
+				free: []packRect{{0, 0, ap.config.MaxWidth + ap.config.Padding, ap.config.MaxHeight + ap.config.Padding}},
+			})
+			pageIndex = len(pages) - 1
+			rect, _ = pages[pageIndex].find(width, height)
+		}
+
+		page := pages[pageIndex]
+		page.place(rect)
+		page.usedW = max(page.usedW, rect.x+rect.width-ap.config.Padding)
+		page.usedH = max(page.usedH, rect.y+rect.height-ap.config.Padding)
+		page.placed = append(page.placed, len(atlas.Regions))
+
+		atlas.Regions = append(atlas.Regions, PackedRegion{
+			Name:   name,
+			Page:   pageIndex,
+			X:      rect.x + ap.config.Extrude,
+			Y:      rect.y + ap.config.Extrude,
+			Width:  bounds.Dx(),
+			Height: bounds.Dy(),
+		})
+	}
+
+	for _, page := range pages {
+		width, height := page.usedW, page.usedH
+		if ap.config.PowerOfTwo {
+			width = min(nextPowerOfTwo(width), max(ap.config.MaxWidth, width))
+			height = min(nextPowerOfTwo(height), max(ap.config.MaxHeight, height))
+		}
+
+		rgba := image.NewRGBA(image.Rect(0, 0, width, height))
+		for _, index := range page.placed {
+			region := atlas.Regions[index]
+			ap.blit(rgba, ap.images[region.Name], region)
+		}
+		atlas.Pages = append(atlas.Pages, rgba)
+	}
+
+	return atlas, nil
+}
+
+// blit copies an image onto its page and repeats its outer pixels Extrude
+// times on every side.
+func (ap *AtlasPacker) blit(page *image.RGBA, img image.Image, region PackedRegion) {
+	bounds := img.Bounds()
+	target := image.Rect(region.X, region.Y, region.X+region.Width, region.Y+region.Height)
+	draw.Draw(page, target, img, bounds.Min, draw.Src)
+
+	extrude := ap.config.Extrude
+	for i := 1; i <= extrude; i++ {
+		for x := 0; x < region.Width; x++ {
+			page.Set(region.X+x, region.Y-i, page.At(region.X+x, region.Y))
+			page.Set(region.X+x, region.Y+region.Height-1+i, page.At(region.X+x, region.Y+region.Height-1))
+		}
+	}
+	for i := 1; i <= extrude; i++ {
+		for y := -extrude; y < region.Height+extrude; y++ {
+			page.Set(region.X-i, region.Y+y, page.At(region.X, region.Y+y))
+			page.Set(region.X+region.Width-1+i, region.Y+y, page.At(region.X+region.Width-1, region.Y+y))
+		}
+	}
+}
+
+// find returns the free position with the smallest leftover short side.
+func (pp *packPage) find(width, height int) (packRect, bool) {
+	best, bestShort, bestLong, found := packRect{}, math.MaxInt, math.MaxInt, false
+
+	for _, free := range pp.free {
+		if free.width < width || free.height < height {
+			continue
+		}
+
+		leftoverW, leftoverH := free.width-width, free.height-height
+		short, long := min(leftoverW, leftoverH), max(leftoverW, leftoverH)
+		if short < bestShort || (short == bestShort && long < bestLong) {
+			best = packRect{free.x, free.y, width, height}
+			bestShort, bestLong, found = short, long, true
+		}
+	}
+	return best, found
+}
+
+// place splits every free rectangle the new one overlaps and drops free
+// rectangles contained in others.
+func (pp *packPage) place(used packRect) {
+	free := make([]packRect, 0, len(pp.free)+4)
+
+	for _, rect := range pp.free {
+		if !rect.intersects(used) {
+			free = append(free, rect)
+			continue
+		}
+
+		if used.x > rect.x {
+			free = append(free, packRect{rect.x, rect.y, used.x - rect.x, rect.height})
+		}
+		if used.x+used.width < rect.x+rect.width {
+			free = append(free, packRect{used.x + used.width, rect.y, rect.x + rect.width - used.x - used.width, rect.height})
+		}
+		if used.y > rect.y {
+			free = append(free, packRect{rect.x, rect.y, rect.width, used.y - rect.y})
+		}
+		if used.y+used.height < rect.y+rect.height {
+			free = append(free, packRect{rect.x, used.y + used.height, rect.width, rect.y + rect.height - used.y - used.height})
+		}
+	}
+
+	pruned := make([]packRect, 0, len(free))
+	for i, rect := range free {
+		contained := false
+		for j, other := range free {
+			if i != j && other.contains(rect) && (rect != other || j < i) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			pruned = append(pruned, rect)
+		}
+	}
+	pp.free = pruned
+}
+
+func (r packRect) intersects(other packRect) bool {
+	return r.x < other.x+other.width && other.x < r.x+r.width &&
+		r.y < other.y+other.height && other.y < r.y+r.height
+}
+
+func (r packRect) contains(other packRect) bool {
+	return other.x >= r.x && other.y >= r.y &&
+		other.x+other.width <= r.x+r.width && other.y+other.height <= r.y+r.height
+}
+
+func nextPowerOfTwo(value int) int {
+	power := 1
+	for power < value {
+		power <<= 1
+	}
+	return power
+}
@@ -0,0 +1,110 @@
+package texture
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math/rand/v2"
+	"testing"
+)
+
+// solidImage is a width by height image filled with one colour, so a blit can
+// be checked by sampling the page.
+func solidImage(width, height int, fill color.RGBA) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.SetRGBA(x, y, fill)
+		}
+	}
+	return img
+}
+
+func TestPackNoOverlap(t *testing.T) {
+	tests := []struct {
+		name   string
+		config PackerConfig
+		count  int
+		size   [2]int // Smallest and largest image side
+	}{
+		{"tight", PackerConfig{MaxWidth: 256, MaxHeight: 256, MultiPage: true}, 60, [2]int{4, 40}},
+		{"padded", PackerConfig{MaxWidth: 256, MaxHeight: 256, Padding: 2, MultiPage: true}, 60, [2]int{4, 40}},
+		{"extruded", PackerConfig{MaxWidth: 256, MaxHeight: 256, Padding: 1, Extrude: 2, MultiPage: true}, 60, [2]int{4, 40}},
+		{"power of two", DefaultPackerConfig(), 40, [2]int{1, 100}},
+		{"many pages", PackerConfig{MaxWidth: 64, MaxHeight: 64, Padding: 2, Extrude: 1, MultiPage: true}, 50, [2]int{8, 30}},
+		{"exact fit", PackerConfig{MaxWidth: 32, MaxHeight: 32}, 4, [2]int{16, 16}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(1, uint64(len(test.name))))
+			packer := NewAtlasPacker(test.config)
+			colors := make(map[string]color.RGBA)
+			for i := range test.count {
+				name := fmt.Sprintf("image%d", i)
+				low, high := test.size[0], test.size[1]
+				fill := color.RGBA{uint8(i), uint8(i >> 8), 100, 255}
+				colors[name] = fill
+				if err := packer.Add(name, solidImage(low+rng.IntN(high-low+1), low+rng.IntN(high-low+1), fill)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			atlas, err := packer.Pack()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(atlas.Regions) != test.count {
+				t.Fatalf("packed %d regions, want %d", len(atlas.Regions), test.count)
+			}
+
+			// Each image with its extruded border, which neighbours must not touch
+			// and must keep Padding pixels away from
+			extrude, padding := test.config.Extrude, test.config.Padding
+			outer := func(r PackedRegion) image.Rectangle {
+				return image.Rect(r.X-extrude, r.Y-extrude, r.X+r.Width+extrude, r.Y+r.Height+extrude)
+			}
+
+			for i, a := range atlas.Regions {
+				page := atlas.Pages[a.Page].Bounds()
+				if !outer(a).In(page) {
+					t.Errorf("%s at %v is outside its %v page", a.Name, outer(a), page)
+				}
+				if got := atlas.Pages[a.Page].RGBAAt(a.X+a.Width/2, a.Y+a.Height/2); got != colors[a.Name] {
+					t.Errorf("%s page pixel is %v, want %v", a.Name, got, colors[a.Name])
+				}
+
+				for _, b := range atlas.Regions[i+1:] {
+					if a.Page != b.Page {
+						continue
+					}
+					if outer(a).Inset(-padding).Overlaps(outer(b)) {
+						t.Errorf("%s at %v and %s at %v are closer than %d pixels", a.Name, outer(a), b.Name, outer(b), padding)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPackTooLarge(t *testing.T) {
+	tests := []struct {
+		name   string
+		config PackerConfig
+		sizes  [][2]int
+	}{
+		{"image over the page size", PackerConfig{MaxWidth: 32, MaxHeight: 32}, [][2]int{{33, 8}}},
+		{"extrusion over the page size", PackerConfig{MaxWidth: 32, MaxHeight: 32, Extrude: 1}, [][2]int{{32, 8}}},
+		{"second page without multi-page", PackerConfig{MaxWidth: 32, MaxHeight: 32}, [][2]int{{32, 32}, {1, 1}}},
+	}
+
+	for _, test := range tests {
+		packer := NewAtlasPacker(test.config)
+		for i, size := range test.sizes {
+			packer.Add(fmt.Sprint(i), solidImage(size[0], size[1], color.RGBA{A: 255}))
+		}
+		if _, err := packer.Pack(); err == nil {
+			t.Errorf("%s: packed without an error", test.name)
+		}
+	}
+}
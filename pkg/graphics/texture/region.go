@@ -4,6 +4,17 @@ type TextureRegion struct {
 	Texture *Texture
 	U1, V1  float32 // Top-left UV coordinates
 	U2, V2  float32 // Bottom-right UV coordinates
+	Borders NineSlice
+}
+
+// NineSlice insets, in texture pixels, split a region into fixed corners,
+// edges that stretch along one axis and a center that stretches along both.
+type NineSlice struct {
+	Left, Right, Top, Bottom int
+}
+
+func (ns NineSlice) IsZero() bool {
+	return ns.Left == 0 && ns.Right == 0 && ns.Top == 0 && ns.Bottom == 0
 }
 
 func NewTextureRegion(texture *Texture, u1, v1, u2, v2 float32) *TextureRegion {
@@ -39,6 +50,15 @@ func (tr *TextureRegion) GetHeight() int {
 	return int((tr.V2 - tr.V1) * float32(tr.Texture.Height))
 }
 
+func (tr *TextureRegion) SetBorders(left, right, top, bottom int) *TextureRegion {
+	tr.Borders = NineSlice{Left: left, Right: right, Top: top, Bottom: bottom}
+	return tr
+}
+
+func (tr *TextureRegion) HasBorders() bool {
+	return !tr.Borders.IsZero()
+}
+
 func (tr *TextureRegion) GetUVs() [4]float32 {
 	return [4]float32{tr.U1, tr.V1, tr.U2, tr.V2}
 }
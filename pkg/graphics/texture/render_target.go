@@ -0,0 +1,140 @@
+package texture
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/all-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// RenderTarget is an offscreen framebuffer whose color buffer is a Texture,
+// so whatever is drawn into it can be drawn again as a sprite or fed to a
+// post-processing pass.
+type RenderTarget struct {
+	Texture *Texture
+	Width   int32
+	Height  int32
+
+	fbo          uint32
+	depthBuffer  uint32 // Depth and stencil
+	filter       Filter
+	prevFBO      int32
+	prevViewport [4]int32
+	bound        bool
+}
+
+func NewRenderTarget(width, height int32, filter Filter) (*RenderTarget, error) {
+	target := &RenderTarget{filter: filter}
+	if err := target.Resize(width, height); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// Resize recreates the attachments at a new size, discarding their contents.
+func (rt *RenderTarget) Resize(width, height int32) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("invalid render target size %dx%d", width, height)
+	}
+	if rt.Texture != nil && rt.Width == width && rt.Height == height {
+		return nil
+	}
+
+	rt.deleteAttachments()
+	rt.Width, rt.Height = width, height
+
+	var textureID uint32
+	gl.GenTextures(1, &textureID)
+	gl.BindTexture(gl.TEXTURE_2D, textureID)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, width, height, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, int32(rt.filter))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, int32(rt.filter))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+
+	rt.Texture = &Texture{
+		ID:     textureID,
+		Width:  width,
+		Height: height,
+		Format: gl.RGBA,
+	}
+
+	gl.GenRenderbuffers(1, &rt.depthBuffer)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, rt.depthBuffer)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, width, height)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+	var previous int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &previous)
+
+	gl.GenFramebuffers(1, &rt.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, rt.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, textureID, 0)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, rt.depthBuffer)
+
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(previous))
+
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		rt.deleteAttachments()
+		return fmt.Errorf("render target framebuffer incomplete: 0x%x", status)
+	}
+	return nil
+}
+
+// Bind redirects drawing into the target until Unbind, remembering the
+// framebuffer and viewport that were active.
+func (rt *RenderTarget) Bind() {
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &rt.prevFBO)
+	gl.GetIntegerv(gl.VIEWPORT, &rt.prevViewport[0])
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, rt.fbo)
+	gl.Viewport(0, 0, rt.Width, rt.Height)
+	rt.bound = true
+}
+
+func (rt *RenderTarget) Unbind() {
+	if !rt.bound {
+		return
+	}
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(rt.prevFBO))
+	gl.Viewport(rt.prevViewport[0], rt.prevViewport[1], rt.prevViewport[2], rt.prevViewport[3])
+	rt.bound = false
+}
+
+// Clear clears the color, depth and stencil of the target, which must be bound.
+func (rt *RenderTarget) Clear(color mgl32.Vec4) {
+	var previous [4]float32
+	gl.GetFloatv(gl.COLOR_CLEAR_VALUE, &previous[0])
+
+	gl.ClearColor(color.X(), color.Y(), color.Z(), color.W())
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
+	gl.ClearColor(previous[0], previous[1], previous[2], previous[3])
+}
+
+// Region returns the whole target as a region. Framebuffer rows are stored
+// bottom-up, so the region is flipped vertically to draw upright with the
+// engine's y-down projection.
+func (rt *RenderTarget) Region() *TextureRegion {
+	return NewTextureRegion(rt.Texture, 0, 1, 1, 0)
+}
+
+func (rt *RenderTarget) deleteAttachments() {
+	if rt.fbo != 0 {
+		gl.DeleteFramebuffers(1, &rt.fbo)
+		rt.fbo = 0
+	}
+	if rt.depthBuffer != 0 {
+		gl.DeleteRenderbuffers(1, &rt.depthBuffer)
+		rt.depthBuffer = 0
+	}
+	if rt.Texture != nil {
+		rt.Texture.Delete()
+		rt.Texture = nil
+	}
+}
+
+func (rt *RenderTarget) Delete() {
+	rt.deleteAttachments()
+}
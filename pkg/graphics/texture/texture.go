@@ -62,6 +62,27 @@ func LoadFromImage(img image.Image) (*Texture, error) {
 	}, nil
 }
 
+// Filter chooses how a texture is sampled when scaled.
+type Filter int32
+
+const (
+	FilterLinear  Filter = gl.LINEAR
+	FilterNearest Filter = gl.NEAREST // Keeps pixel art crisp
+)
+
+// SetFilter changes magnification and minification filtering. Linear
+// minification keeps using the mipmaps generated at load time.
+func (t *Texture) SetFilter(filter Filter) {
+	minFilter := int32(gl.LINEAR_MIPMAP_LINEAR)
+	if filter == FilterNearest {
+		minFilter = gl.NEAREST_MIPMAP_NEAREST
+	}
+
+	gl.BindTexture(gl.TEXTURE_2D, t.ID)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, minFilter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, int32(filter))
+}
+
 func (t *Texture) Bind(unit uint32) {
 	gl.ActiveTexture(gl.TEXTURE0 + unit)
 	gl.BindTexture(gl.TEXTURE_2D, t.ID)
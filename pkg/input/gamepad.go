@@ -0,0 +1,118 @@
+package input
+
+import (
+	"slices"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// AxisThreshold is how far a gamepad axis must be pushed to count as pressed
+// in an action mapping.
+const AxisThreshold = 0.5
+
+// AxisDirection is one half of a gamepad axis, such as the left stick pushed
+// left, so sticks and triggers can drive actions like buttons do.
+type AxisDirection struct {
+	Axis     glfw.GamepadAxis
+	Positive bool // Right or down on sticks; triggers only go positive
+}
+
+// gamepadState tracks the gamepad in use, the first joystick with a gamepad
+// mapping.
+type gamepadState struct {
+	name       string
+	connected  bool
+	buttons    map[glfw.GamepadButton]KeyState
+	axes       [glfw.AxisLast + 1]float32
+	directions map[AxisDirection]KeyState
+}
+
+func newGamepadState() gamepadState {
+	return gamepadState{
+		buttons:    make(map[glfw.GamepadButton]KeyState),
+		directions: make(map[AxisDirection]KeyState),
+	}
+}
+
+// nextKeyState moves a key from its last state given whether it is down now.
+func nextKeyState(previous KeyState, down bool) KeyState {
+	switch {
+	case down && previous == KeyStateReleased:
+		return KeyStatePressed
+	case down:
+		return KeyStateHeld
+	default:
+		return KeyStateReleased
+	}
+}
+
+// update moves to a frame's gamepad state, nil meaning none is connected.
+func (g *gamepadState) update(frame *GamepadFrame) {
+	g.connected = frame != nil
+	g.name = ""
+	if g.connected {
+		g.name = frame.Name
+	}
+
+	for button := glfw.GamepadButton(0); button <= glfw.ButtonLast; button++ {
+		down := g.connected && slices.Contains(frame.Buttons, button)
+		g.buttons[button] = nextKeyState(g.buttons[button], down)
+	}
+
+	for axis := glfw.GamepadAxis(0); axis <= glfw.AxisLast; axis++ {
+		value := float32(0)
+		if g.connected {
+			value = frame.Axes[axis]
+		}
+		g.axes[axis] = value
+
+		positive := AxisDirection{Axis: axis, Positive: true}
+		negative := AxisDirection{Axis: axis, Positive: false}
+		g.directions[positive] = nextKeyState(g.directions[positive], value >= AxisThreshold)
+		g.directions[negative] = nextKeyState(g.directions[negative], value <= -AxisThreshold)
+	}
+}
+
+// IsGamepadConnected reports whether a joystick with a gamepad mapping is
+// plugged in; the first one found is used.
+func (im *InputManager) IsGamepadConnected() bool {
+	return im.gamepad.connected
+}
+
+func (im *InputManager) GetGamepadName() string {
+	return im.gamepad.name
+}
+
+func (im *InputManager) IsGamepadButtonPressed(button glfw.GamepadButton) bool {
+	return im.gamepad.buttons[button] == KeyStatePressed
+}
+
+func (im *InputManager) IsGamepadButtonHeld(button glfw.GamepadButton) bool {
+	state := im.gamepad.buttons[button]
+	return state == KeyStatePressed || state == KeyStateHeld
+}
+
+func (im *InputManager) IsGamepadButtonReleased(button glfw.GamepadButton) bool {
+	return im.gamepad.buttons[button] == KeyStateReleased
+}
+
+// GetGamepadAxis returns an axis from -1 to 1; triggers rest at -1.
+func (im *InputManager) GetGamepadAxis(axis glfw.GamepadAxis) float32 {
+	if axis < 0 || axis > glfw.AxisLast {
+		return 0
+	}
+	return im.gamepad.axes[axis]
+}
+
+func (im *InputManager) IsAxisDirectionPressed(direction AxisDirection) bool {
+	return im.gamepad.directions[direction] == KeyStatePressed
+}
+
+func (im *InputManager) IsAxisDirectionHeld(direction AxisDirection) bool {
+	state := im.gamepad.directions[direction]
+	return state == KeyStatePressed || state == KeyStateHeld
+}
+
+func (im *InputManager) IsAxisDirectionReleased(direction AxisDirection) bool {
+	return im.gamepad.directions[direction] == KeyStateReleased
+}
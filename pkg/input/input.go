@@ -3,6 +3,7 @@ package input
 import (
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/camera"
 )
 
 type KeyState int
@@ -22,7 +23,11 @@ const (
 )
 
 type InputManager struct {
-	window          *glfw.Window
+	window          *WindowSource
+	source          Source // Replaces the window's input when set, as replays do
+	frame           Frame  // Taken in by the last Update
+	heldKeys        map[glfw.Key]bool
+	heldButtons     map[MouseButton]bool
 	keyStates       map[glfw.Key]KeyState
 	prevKeyStates   map[glfw.Key]bool
 	mouseStates     map[MouseButton]KeyState
@@ -33,6 +38,12 @@ type InputManager struct {
 	scrollDelta     mgl32.Vec2
 	inputCallbacks  []InputCallback
 	deltaTime       float32 // Add this field
+	viewport        *camera.Viewport
+	gamepad         gamepadState
+
+	// Gathered from the frame's events, so they last exactly one update
+	typedText  string
+	keyPresses []glfw.Key
 }
 
 type InputCallback func(event InputEvent)
@@ -50,6 +61,8 @@ const (
 	EventTypeMouseRelease
 	EventTypeMouseMove
 	EventTypeMouseScroll
+	EventTypeKeyRepeat
+	EventTypeChar
 )
 
 type KeyPressEvent struct {
@@ -64,6 +77,20 @@ type KeyReleaseEvent struct {
 
 func (e KeyReleaseEvent) GetType() InputEventType { return EventTypeKeyRelease }
 
+// KeyRepeatEvent is sent while a key is held, at the system's repeat rate.
+type KeyRepeatEvent struct {
+	Key glfw.Key
+}
+
+func (e KeyRepeatEvent) GetType() InputEventType { return EventTypeKeyRepeat }
+
+// CharEvent carries a typed character, after keyboard layout and modifiers.
+type CharEvent struct {
+	Char rune
+}
+
+func (e CharEvent) GetType() InputEventType { return EventTypeChar }
+
 type MousePressEvent struct {
 	Button   MouseButton
 	Position mgl32.Vec2
@@ -92,106 +119,117 @@ type MouseScrollEvent struct {
 func (e MouseScrollEvent) GetType() InputEventType { return EventTypeMouseScroll }
 
 func NewInputManager(window *glfw.Window) *InputManager {
-	im := &InputManager{
-		window:          window,
+	return &InputManager{
+		window:          NewWindowSource(window),
+		heldKeys:        make(map[glfw.Key]bool),
+		heldButtons:     make(map[MouseButton]bool),
 		keyStates:       make(map[glfw.Key]KeyState),
 		prevKeyStates:   make(map[glfw.Key]bool),
 		mouseStates:     make(map[MouseButton]KeyState),
 		prevMouseStates: make(map[MouseButton]bool),
 		inputCallbacks:  make([]InputCallback, 0),
+		gamepad:         newGamepadState(),
+		keyPresses:      make([]glfw.Key, 0),
 	}
+}
 
-	im.setupCallbacks()
-	return im
-}
-
-func (im *InputManager) setupCallbacks() {
-	im.window.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-		switch action {
-		case glfw.Press:
-			for _, callback := range im.inputCallbacks {
-				callback(KeyPressEvent{Key: key})
-			}
-		case glfw.Release:
-			for _, callback := range im.inputCallbacks {
-				callback(KeyReleaseEvent{Key: key})
-			}
-		}
-	})
-
-	im.window.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
-		mouseBtn := MouseButton(button)
-		pos := im.GetMousePosition()
-
-		switch action {
-		case glfw.Press:
-			for _, callback := range im.inputCallbacks {
-				callback(MousePressEvent{Button: mouseBtn, Position: pos})
-			}
-		case glfw.Release:
-			for _, callback := range im.inputCallbacks {
-				callback(MouseReleaseEvent{Button: mouseBtn, Position: pos})
-			}
-		}
-	})
-
-	im.window.SetCursorPosCallback(func(w *glfw.Window, xpos, ypos float64) {
-		newPos := mgl32.Vec2{float32(xpos), float32(ypos)}
-		delta := newPos.Sub(im.mousePosition)
-		im.mouseDelta = delta
-
-		for _, callback := range im.inputCallbacks {
-			callback(MouseMoveEvent{Position: newPos, Delta: delta})
-		}
-	})
+// SetSource takes input from a source instead of the window, or from the
+// window again for nil. The window is still drained each Update, so nothing
+// typed meanwhile turns up once it's back.
+func (im *InputManager) SetSource(source Source) {
+	im.source = source
+}
 
-	im.window.SetScrollCallback(func(w *glfw.Window, xoffset, yoffset float64) {
-		delta := mgl32.Vec2{float32(xoffset), float32(yoffset)}
-		im.scrollDelta = delta
+func (im *InputManager) GetSource() Source {
+	return im.source
+}
 
-		for _, callback := range im.inputCallbacks {
-			callback(MouseScrollEvent{Delta: delta})
-		}
-	})
+// GetFrame returns the frame the last Update took in, for recording.
+func (im *InputManager) GetFrame() Frame {
+	return im.frame
 }
 
+// Update takes in the next frame: keys and buttons move between pressed,
+// held and released, and the events since the last Update go to the
+// callbacks.
 func (im *InputManager) Update() {
-	for key := range im.keyStates {
-		currentPressed := im.window.GetKey(key) == glfw.Press
-		wasPressed := im.prevKeyStates[key]
-
-		if currentPressed && !wasPressed {
-			im.keyStates[key] = KeyStatePressed
-		} else if currentPressed && wasPressed {
-			im.keyStates[key] = KeyStateHeld
-		} else {
-			im.keyStates[key] = KeyStateReleased
-		}
+	frame := im.window.Poll()
+	if im.source != nil {
+		frame = im.source.Poll()
+	}
+	im.frame = frame
+
+	clear(im.heldKeys)
+	for _, key := range frame.Keys {
+		im.heldKeys[key] = true
+	}
+	clear(im.heldButtons)
+	for _, button := range frame.MouseButtons {
+		im.heldButtons[button] = true
+	}
 
-		im.prevKeyStates[key] = currentPressed
+	for key := range im.keyStates {
+		im.keyStates[key] = nextKeyState(im.keyStates[key], im.heldKeys[key])
+		im.prevKeyStates[key] = im.heldKeys[key]
 	}
 
 	// Update mouse button states
 	for button := range im.mouseStates {
-		currentPressed := im.window.GetMouseButton(glfw.MouseButton(button)) == glfw.Press
-		wasPressed := im.prevMouseStates[button]
-
-		if currentPressed && !wasPressed {
-			im.mouseStates[button] = KeyStatePressed
-		} else if currentPressed && wasPressed {
-			im.mouseStates[button] = KeyStateHeld
-		} else {
-			im.mouseStates[button] = KeyStateReleased
-		}
-
-		im.prevMouseStates[button] = currentPressed
+		im.mouseStates[button] = nextKeyState(im.mouseStates[button], im.heldButtons[button])
+		im.prevMouseStates[button] = im.heldButtons[button]
 	}
 
-	x, y := im.window.GetCursorPos()
+	im.gamepad.update(frame.Gamepad)
+
 	im.prevMousePos = im.mousePosition
-	im.mousePosition = mgl32.Vec2{float32(x), float32(y)}
+	im.mousePosition = frame.MousePosition
+	im.mouseDelta = im.mousePosition.Sub(im.prevMousePos)
 
+	im.dispatchEvents(frame.Events)
+}
+
+// dispatchEvents sends a frame's events to the callbacks and gathers the
+// scrolling, typing and key presses they add up to.
+func (im *InputManager) dispatchEvents(events []RawEvent) {
 	im.scrollDelta = mgl32.Vec2{0, 0}
+	im.keyPresses = im.keyPresses[:0]
+	text := make([]rune, 0)
+	cursor := im.prevMousePos
+
+	for _, event := range events {
+		var inputEvent InputEvent
+		switch event.Type {
+		case EventTypeKeyPress:
+			im.keyPresses = append(im.keyPresses, event.Key)
+			inputEvent = KeyPressEvent{Key: event.Key}
+		case EventTypeKeyRepeat:
+			im.keyPresses = append(im.keyPresses, event.Key)
+			inputEvent = KeyRepeatEvent{Key: event.Key}
+		case EventTypeKeyRelease:
+			inputEvent = KeyReleaseEvent{Key: event.Key}
+		case EventTypeChar:
+			text = append(text, event.Char)
+			inputEvent = CharEvent{Char: event.Char}
+		case EventTypeMousePress:
+			inputEvent = MousePressEvent{Button: event.Button, Position: event.Position}
+		case EventTypeMouseRelease:
+			inputEvent = MouseReleaseEvent{Button: event.Button, Position: event.Position}
+		case EventTypeMouseMove:
+			inputEvent = MouseMoveEvent{Position: event.Position, Delta: event.Position.Sub(cursor)}
+			cursor = event.Position
+		case EventTypeMouseScroll:
+			im.scrollDelta = im.scrollDelta.Add(event.Position)
+			inputEvent = MouseScrollEvent{Delta: event.Position}
+		default:
+			continue
+		}
+
+		for _, callback := range im.inputCallbacks {
+			callback(inputEvent)
+		}
+	}
+
+	im.typedText = string(text)
 }
 
 func (im *InputManager) IsKeyPressed(key glfw.Key) bool {
@@ -252,6 +290,39 @@ func (im *InputManager) GetMousePosition() mgl32.Vec2 {
 	return im.mousePosition
 }
 
+// SetViewport lets the mouse helpers below map the cursor through the
+// viewport's letterboxing and camera.
+func (im *InputManager) SetViewport(viewport *camera.Viewport) {
+	im.viewport = viewport
+}
+
+// GetMouseViewportPosition returns the cursor in camera screen coordinates,
+// (0, 0) to the camera size, or the window position without a viewport.
+func (im *InputManager) GetMouseViewportPosition() mgl32.Vec2 {
+	if im.viewport == nil {
+		return im.mousePosition
+	}
+	pos, _ := im.viewport.ScreenToViewport(im.mousePosition)
+	return pos
+}
+
+// GetMouseWorldPosition returns the cursor in world coordinates.
+func (im *InputManager) GetMouseWorldPosition() mgl32.Vec2 {
+	if im.viewport == nil {
+		return im.mousePosition
+	}
+	return im.viewport.ScreenToWorld(im.mousePosition)
+}
+
+// IsMouseInViewport is false while the cursor is over the letterbox bars.
+func (im *InputManager) IsMouseInViewport() bool {
+	if im.viewport == nil {
+		return true
+	}
+	_, inside := im.viewport.ScreenToViewport(im.mousePosition)
+	return inside
+}
+
 func (im *InputManager) GetMouseDelta() mgl32.Vec2 {
 	return im.mouseDelta
 }
@@ -260,6 +331,18 @@ func (im *InputManager) GetScrollDelta() mgl32.Vec2 {
 	return im.scrollDelta
 }
 
+// GetTypedText returns the characters typed since the last Update, for text
+// fields.
+func (im *InputManager) GetTypedText() string {
+	return im.typedText
+}
+
+// GetKeyPresses returns every key pressed or repeated since the last Update,
+// in order, for text editing keys that should repeat while held.
+func (im *InputManager) GetKeyPresses() []glfw.Key {
+	return im.keyPresses
+}
+
 func (im *InputManager) AddInputCallback(callback InputCallback) {
 	im.inputCallbacks = append(im.inputCallbacks, callback)
 }
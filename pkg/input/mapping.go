@@ -5,14 +5,18 @@ import "github.com/go-gl/glfw/v3.3/glfw"
 type Action string
 
 type InputMapping struct {
-	keyMappings   map[Action][]glfw.Key
-	mouseMappings map[Action][]MouseButton
+	keyMappings     map[Action][]glfw.Key
+	mouseMappings   map[Action][]MouseButton
+	gamepadMappings map[Action][]glfw.GamepadButton
+	axisMappings    map[Action][]AxisDirection
 }
 
 func NewInputMapping() *InputMapping {
 	return &InputMapping{
-		keyMappings:   make(map[Action][]glfw.Key),
-		mouseMappings: make(map[Action][]MouseButton),
+		keyMappings:     make(map[Action][]glfw.Key),
+		mouseMappings:   make(map[Action][]MouseButton),
+		gamepadMappings: make(map[Action][]glfw.GamepadButton),
+		axisMappings:    make(map[Action][]AxisDirection),
 	}
 }
 
@@ -30,6 +34,16 @@ func (im *InputMapping) MapMouseButton(action Action, button MouseButton) {
 	im.mouseMappings[action] = append(im.mouseMappings[action], button)
 }
 
+func (im *InputMapping) MapGamepadButton(action Action, button glfw.GamepadButton) {
+	im.gamepadMappings[action] = append(im.gamepadMappings[action], button)
+}
+
+// MapGamepadAxis triggers the action when the axis is pushed past
+// AxisThreshold in the given direction.
+func (im *InputMapping) MapGamepadAxis(action Action, axis glfw.GamepadAxis, positive bool) {
+	im.axisMappings[action] = append(im.axisMappings[action], AxisDirection{Axis: axis, Positive: positive})
+}
+
 func (im *InputMapping) IsActionPressed(action Action, inputMgr *InputManager) bool {
 	if keys, exists := im.keyMappings[action]; exists {
 		for _, key := range keys {
@@ -47,6 +61,18 @@ func (im *InputMapping) IsActionPressed(action Action, inputMgr *InputManager) b
 		}
 	}
 
+	for _, button := range im.gamepadMappings[action] {
+		if inputMgr.IsGamepadButtonPressed(button) {
+			return true
+		}
+	}
+
+	for _, direction := range im.axisMappings[action] {
+		if inputMgr.IsAxisDirectionPressed(direction) {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -67,6 +93,18 @@ func (im *InputMapping) IsActionHeld(action Action, inputMgr *InputManager) bool
 		}
 	}
 
+	for _, button := range im.gamepadMappings[action] {
+		if inputMgr.IsGamepadButtonHeld(button) {
+			return true
+		}
+	}
+
+	for _, direction := range im.axisMappings[action] {
+		if inputMgr.IsAxisDirectionHeld(direction) {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -87,10 +125,24 @@ func (im *InputMapping) IsActionReleased(action Action, inputMgr *InputManager)
 		}
 	}
 
+	for _, button := range im.gamepadMappings[action] {
+		if inputMgr.IsGamepadButtonReleased(button) {
+			return true
+		}
+	}
+
+	for _, direction := range im.axisMappings[action] {
+		if inputMgr.IsAxisDirectionReleased(direction) {
+			return true
+		}
+	}
+
 	return false
 }
 
 func (im *InputMapping) ClearAction(action Action) {
 	delete(im.keyMappings, action)
 	delete(im.mouseMappings, action)
+	delete(im.gamepadMappings, action)
+	delete(im.axisMappings, action)
 }
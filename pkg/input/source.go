@@ -0,0 +1,153 @@
+package input
+
+import (
+	"slices"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// RawEvent is one window event. A frame keeps them in the order they came
+// in, so they can be sent to callbacks again in the same order.
+type RawEvent struct {
+	Type     InputEventType `json:"type"`
+	Key      glfw.Key       `json:"key,omitempty"`
+	Button   MouseButton    `json:"button,omitempty"`
+	Char     rune           `json:"char,omitempty"`
+	Position mgl32.Vec2     `json:"pos"` // Cursor position, or the scroll offset
+}
+
+// GamepadFrame is the state of the gamepad in use.
+type GamepadFrame struct {
+	Name    string                     `json:"name"`
+	Buttons []glfw.GamepadButton       `json:"buttons,omitempty"` // Held down
+	Axes    [glfw.AxisLast + 1]float32 `json:"axes"`
+}
+
+// Frame is everything one InputManager.Update takes in: what is held down
+// at the time, and the events since the last Update. Replays record and play
+// back a frame per update.
+type Frame struct {
+	Keys          []glfw.Key    `json:"keys,omitempty"`    // Held down, sorted
+	MouseButtons  []MouseButton `json:"buttons,omitempty"` // Held down, sorted
+	MousePosition mgl32.Vec2    `json:"mouse"`
+	Events        []RawEvent    `json:"events,omitempty"`
+	Gamepad       *GamepadFrame `json:"gamepad,omitempty"` // Nil with no gamepad connected
+}
+
+// Source is where the input manager takes frames from instead of the window,
+// such as a replay.
+type Source interface {
+	// Poll returns the frame for the next Update.
+	Poll() Frame
+}
+
+// WindowSource reads frames from a window's callbacks and the first
+// connected gamepad.
+type WindowSource struct {
+	window  *glfw.Window
+	keys    map[glfw.Key]bool
+	buttons map[MouseButton]bool
+	events  []RawEvent
+}
+
+// NewWindowSource takes over the window's input callbacks.
+func NewWindowSource(window *glfw.Window) *WindowSource {
+	s := &WindowSource{
+		window:  window,
+		keys:    make(map[glfw.Key]bool),
+		buttons: make(map[MouseButton]bool),
+		events:  make([]RawEvent, 0),
+	}
+	s.setupCallbacks()
+	return s
+}
+
+func (s *WindowSource) setupCallbacks() {
+	s.window.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		switch action {
+		case glfw.Press:
+			s.keys[key] = true
+			s.events = append(s.events, RawEvent{Type: EventTypeKeyPress, Key: key})
+		case glfw.Repeat:
+			s.events = append(s.events, RawEvent{Type: EventTypeKeyRepeat, Key: key})
+		case glfw.Release:
+			delete(s.keys, key)
+			s.events = append(s.events, RawEvent{Type: EventTypeKeyRelease, Key: key})
+		}
+	})
+
+	s.window.SetCharCallback(func(w *glfw.Window, char rune) {
+		s.events = append(s.events, RawEvent{Type: EventTypeChar, Char: char})
+	})
+
+	s.window.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		x, y := w.GetCursorPos()
+		event := RawEvent{Button: MouseButton(button), Position: mgl32.Vec2{float32(x), float32(y)}}
+
+		switch action {
+		case glfw.Press:
+			s.buttons[event.Button] = true
+			event.Type = EventTypeMousePress
+		case glfw.Release:
+			delete(s.buttons, event.Button)
+			event.Type = EventTypeMouseRelease
+		default:
+			return
+		}
+		s.events = append(s.events, event)
+	})
+
+	s.window.SetCursorPosCallback(func(w *glfw.Window, xpos, ypos float64) {
+		s.events = append(s.events, RawEvent{Type: EventTypeMouseMove, Position: mgl32.Vec2{float32(xpos), float32(ypos)}})
+	})
+
+	s.window.SetScrollCallback(func(w *glfw.Window, xoffset, yoffset float64) {
+		s.events = append(s.events, RawEvent{Type: EventTypeMouseScroll, Position: mgl32.Vec2{float32(xoffset), float32(yoffset)}})
+	})
+}
+
+func (s *WindowSource) Poll() Frame {
+	x, y := s.window.GetCursorPos()
+	frame := Frame{
+		MousePosition: mgl32.Vec2{float32(x), float32(y)},
+		Gamepad:       pollGamepad(),
+	}
+
+	for key := range s.keys {
+		frame.Keys = append(frame.Keys, key)
+	}
+	slices.Sort(frame.Keys)
+	for button := range s.buttons {
+		frame.MouseButtons = append(frame.MouseButtons, button)
+	}
+	slices.Sort(frame.MouseButtons)
+
+	if len(s.events) > 0 {
+		frame.Events = s.events
+		s.events = make([]RawEvent, 0, len(frame.Events))
+	}
+	return frame
+}
+
+// pollGamepad reads the first joystick with a gamepad mapping.
+func pollGamepad() *GamepadFrame {
+	for joystick := glfw.Joystick1; joystick <= glfw.JoystickLast; joystick++ {
+		if !joystick.IsGamepad() {
+			continue
+		}
+		state := joystick.GetGamepadState()
+		if state == nil {
+			return nil
+		}
+
+		gamepad := &GamepadFrame{Name: joystick.GetGamepadName(), Axes: state.Axes}
+		for button := glfw.GamepadButton(0); button <= glfw.ButtonLast; button++ {
+			if state.Buttons[button] == glfw.Press {
+				gamepad.Buttons = append(gamepad.Buttons, button)
+			}
+		}
+		return gamepad
+	}
+	return nil
+}
@@ -0,0 +1,485 @@
+package inspector
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/entity"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+	"github.com/lunararch/helios/pkg/graphics/sprite"
+	"github.com/lunararch/helios/pkg/input"
+	"github.com/lunararch/helios/pkg/ui"
+)
+
+const (
+	styleSelected = "inspector_selected"
+	styleHeading  = "inspector_heading"
+
+	panelMargin    = 8
+	hierarchyWidth = 200
+	propertyWidth  = 280
+	nameWidth      = 96 // Pixels of a property row given to its name
+)
+
+var highlightColor = mgl32.Vec4{1, 0.85, 0.2, 1}
+
+// Inspector is an overlay for looking into a running world: a hierarchy of
+// its entities on the left, and the selected entity's components on the
+// right, with their properties editable in place. Clicking an entity in the
+// world selects it too. Changes apply straight away, to the live objects.
+type Inspector struct {
+	Visible bool
+
+	canvas   *ui.Canvas
+	renderer *ui.Renderer
+	camera   *camera.Camera
+	mapping  *input.InputMapping // Empty, so the canvas leaves navigation keys to the scene
+
+	world    *entity.World
+	selected *entity.Entity
+
+	hierarchy    *ui.ScrollList
+	hierarchyIDs []entity.EntityID
+	items        map[entity.EntityID]*ui.Button
+
+	properties *ui.ScrollList
+	title      *ui.Label
+	status     *ui.Label
+	rows       []func() // Refresh each editor from its property
+	signature  string   // Property names the editors were built for
+}
+
+// NewInspector creates a hidden inspector drawing over a camera's screen
+// and picking entities through it.
+func NewInspector(cam *camera.Camera, renderer *ui.Renderer) *Inspector {
+	i := &Inspector{
+		canvas:   ui.NewCanvas(cam, compactTheme()),
+		renderer: renderer,
+		camera:   cam,
+		mapping:  input.NewInputMapping(),
+		items:    make(map[entity.EntityID]*ui.Button),
+	}
+	i.buildUI()
+	return i
+}
+
+// compactTheme is the default theme at one canvas pixel per font pixel, so
+// the panels leave most of the screen to the scene.
+func compactTheme() *ui.Theme {
+	theme := ui.DefaultTheme()
+	for _, name := range []string{
+		ui.StyleDefault, ui.StyleLabel, ui.StylePanel, ui.StyleButton, ui.StyleListItem,
+		ui.StyleSlider, ui.StyleCheckbox, ui.StyleTextInput, ui.StyleScrollList,
+	} {
+		style := theme.GetStyle(name).Clone()
+		style.TextScale = 1
+		style.Padding = ui.Insets{
+			Left:   min(style.Padding.Left, 4),
+			Top:    min(style.Padding.Top, 3),
+			Right:  min(style.Padding.Right, 4),
+			Bottom: min(style.Padding.Bottom, 3),
+		}
+		theme.SetStyle(name, style)
+	}
+
+	title := theme.GetStyle(ui.StyleTitle).Clone()
+	title.TextScale = 2
+	theme.SetStyle(ui.StyleTitle, title)
+
+	heading := theme.GetStyle(ui.StyleLabel).Clone()
+	heading.Text = theme.GetStyle(ui.StyleDefault).Accent
+	theme.SetStyle(styleHeading, heading)
+
+	selected := theme.GetStyle(ui.StyleListItem).Clone()
+	selected.SetSkin(ui.StateNormal, ui.Skin{Color: mgl32.Vec4{0.45, 0.55, 1, 0.35}, Radius: 4})
+	theme.SetStyle(styleSelected, selected)
+
+	return theme
+}
+
+func (i *Inspector) buildUI() {
+	i.hierarchy = ui.NewScrollList()
+	i.hierarchy.Grow = 1
+
+	hierarchyPanel := ui.NewColumn(6, ui.NewTitle("Hierarchy"), i.hierarchy)
+	hierarchyPanel.Transparent = false
+	hierarchyPanel.Padding = ui.Uniform(8)
+	hierarchyPanel.Anchor = ui.Anchor{
+		Min:    mgl32.Vec2{0, 0},
+		Max:    mgl32.Vec2{0, 1},
+		Offset: mgl32.Vec2{panelMargin, 0},
+		Margin: ui.Uniform(panelMargin),
+	}
+	hierarchyPanel.Size = mgl32.Vec2{hierarchyWidth, 0}
+
+	i.title = ui.NewTitle("Nothing selected")
+	i.properties = ui.NewScrollList()
+	i.properties.Grow = 1
+	i.status = ui.NewLabel("")
+	i.status.Color = &mgl32.Vec4{1, 0.45, 0.45, 1}
+
+	propertyPanel := ui.NewColumn(6, i.title, i.properties, i.status)
+	propertyPanel.Transparent = false
+	propertyPanel.Padding = ui.Uniform(8)
+	propertyPanel.Anchor = ui.Anchor{
+		Min:    mgl32.Vec2{1, 0},
+		Max:    mgl32.Vec2{1, 1},
+		Pivot:  mgl32.Vec2{1, 0},
+		Offset: mgl32.Vec2{-panelMargin, 0},
+		Margin: ui.Uniform(panelMargin),
+	}
+	propertyPanel.Size = mgl32.Vec2{propertyWidth, 0}
+
+	i.canvas.Add(hierarchyPanel, propertyPanel)
+}
+
+func (i *Inspector) Toggle() {
+	i.Visible = !i.Visible
+}
+
+// SetWorld points the inspector at the world to show, usually the current
+// scene's. Nil shows nothing.
+func (i *Inspector) SetWorld(world *entity.World) {
+	if world == i.world {
+		return
+	}
+	i.world = world
+	i.Select(nil)
+}
+
+// Select shows an entity's properties, or nothing for nil.
+func (i *Inspector) Select(e *entity.Entity) {
+	i.selected = e
+	i.status.SetText("")
+}
+
+func (i *Inspector) GetSelected() *entity.Entity {
+	return i.selected
+}
+
+// WantsKeyboard is true while a property is being typed into.
+func (i *Inspector) WantsKeyboard() bool {
+	return i.Visible && i.canvas.WantsKeyboard()
+}
+
+// IsPointerOver is true while the mouse is over one of the panels, when the
+// scene should leave clicks and the wheel alone.
+func (i *Inspector) IsPointerOver() bool {
+	return i.Visible && i.canvas.IsPointerOver()
+}
+
+// Update follows changes to the world, passes input to the panels and picks
+// the entity under a click outside them.
+func (i *Inspector) Update(deltaTime float32, inputManager *input.InputManager) {
+	if !i.Visible {
+		return
+	}
+
+	// A destroyed entity keeps its ID but leaves the world
+	if i.selected != nil && (i.world == nil || !i.hasEntity(i.selected)) {
+		i.Select(nil)
+	}
+
+	i.updateHierarchy()
+	i.updateProperties()
+	i.canvas.Update(deltaTime, inputManager, i.mapping)
+
+	if i.world != nil && inputManager.IsMouseButtonPressed(input.MouseButtonLeft) && !i.canvas.IsPointerOver() {
+		if _, inside := i.camera.ScreenToCamera(inputManager.GetMousePosition()); inside {
+			i.Select(i.world.PickEntity(i.camera.ScreenToWorld(inputManager.GetMousePosition())))
+		}
+	}
+}
+
+func (i *Inspector) hasEntity(e *entity.Entity) bool {
+	found, exists := i.world.GetEntity(e.ID)
+	return exists && found == e
+}
+
+// entityOrder lists the world's entities parents first, each followed by
+// its children, with their depth in the tree.
+func (i *Inspector) entityOrder() ([]*entity.Entity, []int) {
+	if i.world == nil {
+		return nil, nil
+	}
+
+	roots := make([]*entity.Entity, 0)
+	for _, e := range i.world.GetRootEntities() {
+		if e.GetParent() == nil {
+			roots = append(roots, e)
+		}
+	}
+	sort.Slice(roots, func(a, b int) bool { return roots[a].ID < roots[b].ID })
+
+	entities := make([]*entity.Entity, 0, len(roots))
+	depths := make([]int, 0, len(roots))
+	var visit func(e *entity.Entity, depth int)
+	visit = func(e *entity.Entity, depth int) {
+		entities = append(entities, e)
+		depths = append(depths, depth)
+		for _, child := range e.GetChildren() {
+			visit(child, depth+1)
+		}
+	}
+	for _, root := range roots {
+		visit(root, 0)
+	}
+	return entities, depths
+}
+
+// updateHierarchy rebuilds the entity list when entities come, go or move,
+// and marks the selected one.
+func (i *Inspector) updateHierarchy() {
+	entities, depths := i.entityOrder()
+
+	ids := make([]entity.EntityID, len(entities))
+	for index, e := range entities {
+		ids[index] = e.ID
+	}
+
+	if !slices.Equal(ids, i.hierarchyIDs) {
+		i.hierarchyIDs = ids
+		i.hierarchy.Clear()
+		clear(i.items)
+
+		for _, e := range entities {
+			i.items[e.ID] = i.hierarchy.AddItem("", func() { i.Select(e) })
+		}
+	}
+
+	for index, e := range entities {
+		item := i.items[e.ID]
+		item.Text = strings.Repeat("  ", depths[index]) + entityLabel(e)
+		if e == i.selected {
+			item.Style = styleSelected
+		} else {
+			item.Style = ui.StyleListItem
+		}
+	}
+}
+
+func entityLabel(e *entity.Entity) string {
+	name := e.GetName()
+	if name == "" {
+		name = fmt.Sprintf("#%d", e.ID)
+	}
+	if !e.IsActive() {
+		name += " (inactive)"
+	}
+	return name
+}
+
+// section is one group of properties: the entity's own, or a component's.
+type section struct {
+	name       string
+	properties []*Property
+}
+
+func (i *Inspector) sections() []section {
+	if i.selected == nil {
+		return nil
+	}
+
+	sections := []section{{name: "Entity", properties: Properties(i.selected)}}
+
+	components := i.selected.GetComponents()
+	types := make([]entity.ComponentType, 0, len(components))
+	for componentType := range components {
+		types = append(types, componentType)
+	}
+	slices.Sort(types)
+
+	for _, componentType := range types {
+		sections = append(sections, section{
+			name:       componentType.String(),
+			properties: ComponentProperties(components[componentType]),
+		})
+	}
+	return sections
+}
+
+// updateProperties rebuilds the editors when the selection or its set of
+// properties changes, then refreshes their values.
+func (i *Inspector) updateProperties() {
+	sections := i.sections()
+
+	var signature strings.Builder
+	if i.selected != nil {
+		fmt.Fprintf(&signature, "%d;", i.selected.ID)
+	}
+	for _, s := range sections {
+		signature.WriteString(s.name + ":")
+		for _, property := range s.properties {
+			signature.WriteString(property.Name + ",")
+		}
+	}
+
+	if signature.String() != i.signature {
+		i.signature = signature.String()
+		i.buildProperties(sections)
+	}
+
+	for _, refresh := range i.rows {
+		refresh()
+	}
+}
+
+func (i *Inspector) buildProperties(sections []section) {
+	i.properties.Clear()
+	i.rows = i.rows[:0]
+	i.properties.SetScroll(0)
+
+	if i.selected == nil {
+		i.title.SetText("Nothing selected")
+		return
+	}
+	i.title.SetText(entityLabel(i.selected))
+
+	for _, s := range sections {
+		heading := ui.NewLabel(s.name)
+		heading.Style = styleHeading
+		i.properties.Add(heading)
+
+		for _, property := range s.properties {
+			i.properties.Add(i.editor(property))
+		}
+	}
+}
+
+// apply shows an edit's error, or clears the last one.
+func (i *Inspector) apply(err error) {
+	if err != nil {
+		i.status.SetText(err.Error())
+	} else {
+		i.status.SetText("")
+	}
+}
+
+// editor returns a row editing one property: a checkbox for bools, a button
+// cycling through the options of properties with them, a text field per
+// element for everything else, and plain text for read-only properties.
+func (i *Inspector) editor(property *Property) ui.Widget {
+	if property.Type.Kind() == reflect.Bool && !property.IsReadOnly() {
+		checkbox := ui.NewCheckbox(property.Name, property.Get().Bool(), func(checked bool) {
+			i.apply(property.Set(checked))
+		})
+		i.rows = append(i.rows, func() {
+			if value := property.Get().Bool(); value != checkbox.IsChecked() {
+				checkbox.SetChecked(value)
+			}
+		})
+		return checkbox
+	}
+
+	name := ui.NewLabel(property.Name)
+	name.Size = mgl32.Vec2{nameWidth, 0}
+	row := ui.NewRow(4, name)
+
+	switch {
+	case property.IsReadOnly():
+		value := ui.NewLabel(property.Format(-1))
+		value.Grow = 1
+		row.Add(value)
+		i.rows = append(i.rows, func() { value.SetText(property.Format(-1)) })
+
+	case len(property.Options) > 0:
+		button := ui.NewButton(property.Format(-1), nil)
+		button.Grow = 1
+		button.OnClick = func() {
+			current := slices.Index(property.Options, property.Format(-1))
+			i.apply(property.Set(property.Options[(current+1)%len(property.Options)]))
+		}
+		row.Add(button)
+		i.rows = append(i.rows, func() { button.Text = property.Format(-1) })
+
+	case property.Len() > 0:
+		for index := 0; index < property.Len(); index++ {
+			row.Add(i.field(property, index))
+		}
+
+	default:
+		row.Add(i.field(property, -1))
+	}
+	return row
+}
+
+// field returns a text field for a property, or one element of it, applied
+// when Enter is pressed.
+func (i *Inspector) field(property *Property, index int) *ui.TextInput {
+	field := ui.NewTextInput("")
+	field.Size = mgl32.Vec2{24, 0}
+	field.Grow = 1
+	field.SetText(property.Format(index))
+	field.OnSubmit = func(text string) {
+		i.apply(property.Parse(index, text))
+	}
+
+	i.rows = append(i.rows, func() {
+		// Leave text being typed alone
+		if text := property.Format(index); !field.IsEditing() && text != field.GetText() {
+			field.SetText(text)
+		}
+	})
+	return field
+}
+
+// Render draws an outline around the selected entity in the world, then the
+// panels over the camera's screen.
+func (i *Inspector) Render() {
+	if !i.Visible {
+		return
+	}
+
+	if i.selected != nil {
+		i.renderHighlight()
+	}
+
+	i.renderer.Begin(i.camera)
+	i.canvas.Render(i.renderer)
+	i.renderer.End()
+}
+
+func (i *Inspector) renderHighlight() {
+	shapes := i.renderer.Shapes()
+	shapes.SetCamera(i.camera.GetProjectionMatrix(), i.camera.GetViewMatrix())
+	shapes.Begin()
+
+	// Two screen pixels wide at any zoom
+	thickness := 2 / i.camera.Zoom
+	paint := sprite.SolidPaint(highlightColor)
+
+	if bounds, ok := entityBounds(i.selected); ok {
+		shapes.StrokeRect(bounds.Min, bounds.Max, thickness, paint)
+	} else {
+		center := i.selected.GetWorldPosition().Vec2()
+		size := 8 / i.camera.Zoom
+		shapes.Line(center.Sub(mgl32.Vec2{size, size}), center.Add(mgl32.Vec2{size, size}), thickness, paint)
+		shapes.Line(center.Sub(mgl32.Vec2{size, -size}), center.Add(mgl32.Vec2{size, -size}), thickness, paint)
+	}
+
+	shapes.End()
+}
+
+// entityBounds returns the union of an entity's drawn components' bounds.
+func entityBounds(e *entity.Entity) (camera.Bounds, bool) {
+	var bounds camera.Bounds
+	found := false
+	for _, component := range e.GetComponents() {
+		cullable, ok := component.(entity.CullableComponent)
+		if !ok || !component.IsActive() {
+			continue
+		}
+		b := cullable.GetBounds()
+		if !found {
+			bounds, found = b, true
+			continue
+		}
+		bounds.Min = mgl32.Vec2{min(bounds.Min.X(), b.Min.X()), min(bounds.Min.Y(), b.Min.Y())}
+		bounds.Max = mgl32.Vec2{max(bounds.Max.X(), b.Max.X()), max(bounds.Max.Y(), b.Max.Y())}
+	}
+	return bounds, found
+}
@@ -0,0 +1,324 @@
+package inspector
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lunararch/helios/pkg/entity"
+)
+
+// Property is one value the inspector shows and edits, read and written
+// through reflection. Values are bools, numbers, strings, or arrays of up
+// to four floats such as mgl32 vectors, edited one element at a time.
+type Property struct {
+	Name    string
+	Type    reflect.Type
+	Options []string // Values to pick from instead of typing, if any
+
+	get func() reflect.Value
+	set func(value reflect.Value) error // Nil for read-only properties
+}
+
+func (p *Property) IsReadOnly() bool {
+	return p.set == nil
+}
+
+// Len returns the number of elements of an array property, or 0.
+func (p *Property) Len() int {
+	if p.Type.Kind() == reflect.Array {
+		return p.Type.Len()
+	}
+	return 0
+}
+
+// Get returns the current value. A value that is gone or has changed type,
+// like an animation parameter game code replaced, reads as the zero value.
+func (p *Property) Get() reflect.Value {
+	if value, err := p.current(); err == nil {
+		return value
+	}
+	return reflect.Zero(p.Type)
+}
+
+func (p *Property) current() (reflect.Value, error) {
+	value := p.get()
+	if !value.IsValid() || value.Type() != p.Type {
+		return reflect.Value{}, fmt.Errorf("'%s' no longer holds a %s", p.Name, p.Type)
+	}
+	return value, nil
+}
+
+// Format returns the value as text, or one array element's with index 0 and
+// up; -1 formats the whole value.
+func (p *Property) Format(index int) string {
+	value := p.Get()
+	if index >= 0 && value.Kind() == reflect.Array {
+		return formatValue(value.Index(index))
+	}
+	if value.Kind() == reflect.Array {
+		parts := make([]string, value.Len())
+		for i := range parts {
+			parts[i] = formatValue(value.Index(i))
+		}
+		return strings.Join(parts, " ")
+	}
+	return formatValue(value)
+}
+
+// Parse sets the value from text, or one array element's with index 0 and
+// up.
+func (p *Property) Parse(index int, text string) error {
+	if p.set == nil {
+		return fmt.Errorf("'%s' is read-only", p.Name)
+	}
+
+	current, err := p.current()
+	if err != nil {
+		return err
+	}
+	value := reflect.New(p.Type).Elem()
+	value.Set(current)
+
+	target := value
+	if index >= 0 && value.Kind() == reflect.Array {
+		target = value.Index(index)
+	}
+	if err := parseValue(target, strings.TrimSpace(text)); err != nil {
+		return fmt.Errorf("'%s': %w", p.Name, err)
+	}
+	return p.set(value)
+}
+
+// Set sets the value directly, converting it to the property's type.
+func (p *Property) Set(value any) error {
+	if p.set == nil {
+		return fmt.Errorf("'%s' is read-only", p.Name)
+	}
+	v := reflect.ValueOf(value)
+	if !v.Type().ConvertibleTo(p.Type) {
+		return fmt.Errorf("'%s' can't be set to a %s", p.Name, v.Type())
+	}
+	return p.set(v.Convert(p.Type))
+}
+
+func formatValue(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'g', 5, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', 6, 64)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+func parseValue(target reflect.Value, text string) error {
+	switch target.Kind() {
+	case reflect.Bool:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("'%s' is not true or false", text)
+		}
+		target.SetBool(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(text, target.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("'%s' is not a number", text)
+		}
+		target.SetFloat(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(text, 10, target.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("'%s' is not a whole number", text)
+		}
+		target.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(text, 10, target.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("'%s' is not a positive whole number", text)
+		}
+		target.SetUint(value)
+	case reflect.String:
+		target.SetString(text)
+	default:
+		return fmt.Errorf("%s values can't be edited", target.Type())
+	}
+	return nil
+}
+
+// supported reports whether the inspector can show values of a type.
+func supported(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Array:
+		kind := t.Elem().Kind()
+		return t.Len() <= 4 && (kind == reflect.Float32 || kind == reflect.Float64)
+	}
+	return false
+}
+
+// Names of getters that aren't worth showing: what a component is, and
+// lifecycle state the engine manages.
+var hiddenProperties = map[string]bool{
+	"Type":        true,
+	"Initialized": true,
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Properties finds the properties of a struct pointer: its exported fields
+// of supported types, then the values it has getters for, named GetX or
+// IsX. A getter with a matching SetX setter, which may return an error, is
+// editable; one without is read-only. Fields named ID are read-only too, as
+// they usually key a map.
+func Properties(target any) []*Property {
+	pointer := reflect.ValueOf(target)
+	if pointer.Kind() != reflect.Pointer || pointer.IsNil() || pointer.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	properties := make([]*Property, 0)
+	names := make(map[string]bool)
+
+	structValue := pointer.Elem()
+	for i := 0; i < structValue.NumField(); i++ {
+		field := structValue.Type().Field(i)
+		if !field.IsExported() || field.Anonymous || !supported(field.Type) {
+			continue
+		}
+
+		value := structValue.Field(i)
+		property := &Property{
+			Name: field.Name,
+			Type: field.Type,
+			get:  func() reflect.Value { return value },
+		}
+		if field.Name != "ID" && value.CanSet() {
+			property.set = func(v reflect.Value) error {
+				value.Set(v)
+				return nil
+			}
+		}
+		properties = append(properties, property)
+		names[field.Name] = true
+	}
+
+	// Methods come sorted by name
+	for i := 0; i < pointer.NumMethod(); i++ {
+		method := pointer.Type().Method(i)
+		name, isGetter := strings.CutPrefix(method.Name, "Get")
+		if !isGetter {
+			name, isGetter = strings.CutPrefix(method.Name, "Is")
+		}
+		if !isGetter || name == "" || names[name] || hiddenProperties[name] {
+			continue
+		}
+
+		getter := pointer.Method(i)
+		if getter.Type().NumIn() != 0 || getter.Type().NumOut() != 1 || !supported(getter.Type().Out(0)) {
+			continue
+		}
+
+		valueType := getter.Type().Out(0)
+		property := &Property{
+			Name: name,
+			Type: valueType,
+			get:  func() reflect.Value { return getter.Call(nil)[0] },
+		}
+		if setter := pointer.MethodByName("Set" + name); setter.IsValid() && isSetter(setter.Type(), valueType) {
+			property.set = func(v reflect.Value) error {
+				results := setter.Call([]reflect.Value{v})
+				if len(results) == 1 && !results[0].IsNil() {
+					return results[0].Interface().(error)
+				}
+				return nil
+			}
+		}
+		properties = append(properties, property)
+		names[name] = true
+	}
+
+	return properties
+}
+
+func isSetter(t reflect.Type, valueType reflect.Type) bool {
+	if t.NumIn() != 1 || t.In(0) != valueType {
+		return false
+	}
+	return t.NumOut() == 0 || (t.NumOut() == 1 && t.Out(0) == errorType)
+}
+
+// ComponentProperties returns the properties of a component. Animation
+// components also get their state, picked from the state machine's states,
+// the machine's playback fields and one property per parameter.
+func ComponentProperties(component entity.Component) []*Property {
+	properties := Properties(component)
+
+	animationComponent, ok := component.(*entity.AnimationComponent)
+	if !ok || animationComponent.GetStateMachine() == nil {
+		return properties
+	}
+	stateMachine := animationComponent.GetStateMachine()
+
+	// State and the machine's Playing field replace the read-only getters
+	properties = slices.DeleteFunc(properties, func(p *Property) bool {
+		return p.Name == "CurrentStateName" || p.Name == "Playing"
+	})
+
+	states := make([]string, 0, len(stateMachine.States))
+	for name := range stateMachine.States {
+		states = append(states, name)
+	}
+	sort.Strings(states)
+
+	properties = append(properties, &Property{
+		Name:    "State",
+		Type:    reflect.TypeOf(""),
+		Options: states,
+		get:     func() reflect.Value { return reflect.ValueOf(animationComponent.GetCurrentStateName()) },
+		set: func(v reflect.Value) error {
+			return animationComponent.SetState(v.String())
+		},
+	})
+
+	for _, property := range Properties(stateMachine) {
+		if property.Name == "CurrentTime" || property.Name == "Playing" {
+			properties = append(properties, property)
+		}
+	}
+
+	names := make([]string, 0, len(stateMachine.Parameters))
+	for name, value := range stateMachine.Parameters {
+		if value != nil && supported(reflect.TypeOf(value)) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		properties = append(properties, &Property{
+			Name: name,
+			Type: reflect.TypeOf(stateMachine.Parameters[name]),
+			get:  func() reflect.Value { return reflect.ValueOf(stateMachine.Parameters[name]) },
+			set: func(v reflect.Value) error {
+				animationComponent.SetParameter(name, v.Interface())
+				return nil
+			},
+		})
+	}
+
+	return properties
+}
@@ -0,0 +1,165 @@
+package profiler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+	"github.com/lunararch/helios/pkg/graphics/font"
+	"github.com/lunararch/helios/pkg/graphics/sprite"
+)
+
+const (
+	graphFrames    = 150 // Most recent frames shown, one bar each
+	graphBarWidth  = 2
+	graphHeight    = 100
+	graphMargin    = 8
+	graphPadding   = 6
+	legendLines    = 10
+	legendWidth    = 168
+	legendTextSize = 1
+)
+
+var (
+	graphBackground = mgl32.Vec4{0.02, 0.02, 0.05, 0.8}
+	graphFrameColor = mgl32.Vec4{0.35, 0.35, 0.4, 1}
+	graphLineColor  = mgl32.Vec4{1, 1, 1, 0.3}
+	graphTextColor  = mgl32.Vec4{0.85, 0.87, 0.95, 1}
+
+	// Top-level scopes get a color from their name, so it stays the same
+	// from frame to frame
+	graphPalette = []mgl32.Vec4{
+		{0.35, 0.6, 1, 1},
+		{1, 0.55, 0.25, 1},
+		{0.4, 0.85, 0.4, 1},
+		{0.95, 0.35, 0.45, 1},
+		{0.75, 0.5, 1, 1},
+		{1, 0.85, 0.3, 1},
+		{0.3, 0.85, 0.85, 1},
+		{0.9, 0.6, 0.8, 1},
+	}
+)
+
+func scopeColor(name string) mgl32.Vec4 {
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	return graphPalette[hash.Sum32()%uint32(len(graphPalette))]
+}
+
+// Overlay draws the recent frame times as a bar graph in the bottom-right
+// corner of the screen, each bar split into its top-level scopes, with the
+// average time of every scope beside it. The dotted lines mark the frame
+// budget and twice that.
+type Overlay struct {
+	Visible bool
+	Budget  time.Duration // Frame time at the graph's middle line
+
+	profiler *Profiler
+	shapes   *sprite.ShapeBatch
+}
+
+func NewOverlay(p *Profiler, shapes *sprite.ShapeBatch) *Overlay {
+	return &Overlay{
+		Budget:   time.Second / 60,
+		profiler: p,
+		shapes:   shapes,
+	}
+}
+
+// Render draws the graph in the camera's pixels. Nothing is drawn while the
+// overlay is hidden or no frames were recorded.
+func (o *Overlay) Render(cam *camera.Camera) {
+	count := o.profiler.GetFrameCount()
+	if !o.Visible || count == 0 {
+		return
+	}
+
+	size := cam.Size
+	graphWidth := float32(graphFrames * graphBarWidth)
+	panelMax := size.Sub(mgl32.Vec2{graphMargin, graphMargin})
+	panelMin := panelMax.Sub(mgl32.Vec2{graphWidth + legendWidth + 3*graphPadding, graphHeight + 2*graphPadding})
+	graphMin := mgl32.Vec2{panelMax.X() - graphPadding - graphWidth, panelMin.Y() + graphPadding}
+	bottom := graphMin.Y() + graphHeight
+	pixelsPerSecond := float32(graphHeight) / float32(2*o.Budget.Seconds())
+
+	o.shapes.SetCamera(cam.GetProjectionMatrix(), mgl32.Ident4())
+	feather := o.shapes.GetFeather()
+	o.shapes.SetFeather(0)
+	o.shapes.Begin()
+
+	o.shapes.FillRect(panelMin, panelMax, sprite.SolidPaint(graphBackground))
+
+	// Newest frame on the right
+	shown := min(count, graphFrames)
+	for i := 0; i < shown; i++ {
+		frame := o.profiler.GetFrame(count - shown + i)
+		x := graphMin.X() + float32(graphFrames-shown+i)*graphBarWidth
+
+		height := min(float32(frame.Duration.Seconds())*pixelsPerSecond, graphHeight)
+		o.shapes.FillRect(mgl32.Vec2{x, bottom - height}, mgl32.Vec2{x + graphBarWidth, bottom}, sprite.SolidPaint(graphFrameColor))
+
+		y := bottom
+		for _, sample := range frame.Samples {
+			if sample.Depth != 0 || y <= graphMin.Y() {
+				continue
+			}
+			top := max(y-float32(sample.Duration.Seconds())*pixelsPerSecond, graphMin.Y())
+			o.shapes.FillRect(mgl32.Vec2{x, top}, mgl32.Vec2{x + graphBarWidth, y}, sprite.SolidPaint(scopeColor(sample.Name)))
+			y = top
+		}
+	}
+
+	for _, budget := range []float32{1, 2} {
+		y := bottom - budget*graphHeight/2
+		for x := graphMin.X(); x < graphMin.X()+graphWidth; x += 6 {
+			o.shapes.FillRect(mgl32.Vec2{x, y}, mgl32.Vec2{x + 3, y + 1}, sprite.SolidPaint(graphLineColor))
+		}
+	}
+
+	o.renderLegend(mgl32.Vec2{panelMin.X() + graphPadding, panelMin.Y() + graphPadding})
+
+	o.shapes.End()
+	o.shapes.SetFeather(feather)
+}
+
+func (o *Overlay) renderLegend(position mgl32.Vec2) {
+	lineHeight := float32(font.LineAdvance * legendTextSize)
+	swatch := float32(font.GlyphHeight * legendTextSize)
+
+	var total, longest time.Duration
+	count := o.profiler.GetFrameCount()
+	for i := 0; i < count; i++ {
+		duration := o.profiler.GetFrame(i).Duration
+		total += duration
+		longest = max(longest, duration)
+	}
+	o.text(position, fmt.Sprintf("frame %s max %s", milliseconds(total/time.Duration(count)), milliseconds(longest)), graphTextColor)
+	position = position.Add(mgl32.Vec2{0, lineHeight + 2})
+
+	for i, stats := range o.profiler.GetStats() {
+		if i == legendLines {
+			break
+		}
+		x := position.X() + float32(stats.Depth)*swatch
+		if stats.Depth == 0 {
+			o.shapes.FillRect(mgl32.Vec2{x, position.Y()}, mgl32.Vec2{x + swatch, position.Y() + swatch}, sprite.SolidPaint(scopeColor(stats.Name)))
+		}
+		o.text(mgl32.Vec2{x + swatch + 4, position.Y()}, fmt.Sprintf("%s %s", stats.Name, milliseconds(stats.Average)), graphTextColor)
+		position = position.Add(mgl32.Vec2{0, lineHeight})
+	}
+}
+
+func (o *Overlay) text(position mgl32.Vec2, text string, color mgl32.Vec4) {
+	// Capitals read better at one screen pixel per font pixel
+	paint := sprite.SolidPaint(color)
+	for _, run := range font.Layout(position, strings.ToUpper(text), legendTextSize) {
+		o.shapes.FillRect(run.Min, run.Max, paint)
+	}
+}
+
+func milliseconds(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", d.Seconds()*1000)
+}
@@ -0,0 +1,235 @@
+package profiler
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultFrames is how many frames the ring buffer keeps, five seconds at
+// 60 FPS.
+const DefaultFrames = 300
+
+// Sample is one scope's timing within a frame.
+type Sample struct {
+	Name     string
+	Depth    int           // Number of scopes it was nested in
+	Start    time.Duration // Since the profiler was created
+	Duration time.Duration
+}
+
+// Frame holds the samples of one game loop iteration, in the order their
+// scopes began.
+type Frame struct {
+	Number   uint64
+	Start    time.Duration
+	Duration time.Duration
+	Samples  []Sample
+}
+
+// Profiler times named scopes in every frame and keeps the most recent
+// frames in a ring buffer. While disabled, BeginFrame, Begin and End return
+// straight away without recording or allocating anything.
+type Profiler struct {
+	enabled bool
+	epoch   time.Time
+
+	frames  []Frame // Ring buffer; frames[next] is the oldest once full
+	next    int
+	count   int
+	number  uint64
+	current *Frame
+	stack   []int // Indices of the open scopes in current.Samples
+}
+
+var profiler = New(DefaultFrames)
+
+// New creates a disabled profiler keeping the given number of frames. The
+// package-level functions use one made with DefaultFrames.
+func New(frames int) *Profiler {
+	return &Profiler{
+		epoch:  time.Now(),
+		frames: make([]Frame, max(frames, 1)),
+		stack:  make([]int, 0, 16),
+	}
+}
+
+// Get returns the profiler used by the package-level functions.
+func Get() *Profiler {
+	return profiler
+}
+
+func (p *Profiler) SetEnabled(enabled bool) {
+	if !enabled {
+		p.current = nil
+		p.stack = p.stack[:0]
+	}
+	p.enabled = enabled
+}
+
+func (p *Profiler) IsEnabled() bool {
+	return p.enabled
+}
+
+// Clear drops every recorded frame.
+func (p *Profiler) Clear() {
+	p.next, p.count = 0, 0
+	p.current = nil
+	p.stack = p.stack[:0]
+}
+
+// BeginFrame starts recording a frame, reusing the oldest one's memory once
+// the ring buffer is full.
+func (p *Profiler) BeginFrame() {
+	if !p.enabled {
+		return
+	}
+
+	frame := &p.frames[p.next]
+	frame.Number = p.number
+	frame.Start = time.Since(p.epoch)
+	frame.Duration = 0
+	frame.Samples = frame.Samples[:0]
+
+	p.number++
+	p.current = frame
+	p.stack = p.stack[:0]
+}
+
+// EndFrame finishes the frame, closing any scope left open.
+func (p *Profiler) EndFrame() {
+	if p.current == nil {
+		return
+	}
+
+	now := time.Since(p.epoch)
+	for _, index := range p.stack {
+		sample := &p.current.Samples[index]
+		sample.Duration = now - sample.Start
+	}
+	p.stack = p.stack[:0]
+
+	p.current.Duration = now - p.current.Start
+	p.current = nil
+	p.next = (p.next + 1) % len(p.frames)
+	p.count = min(p.count+1, len(p.frames))
+}
+
+// Scope is a running measurement, returned by Begin. The zero Scope, which
+// Begin returns while the profiler is off, does nothing when ended.
+type Scope struct {
+	profiler *Profiler
+	frame    uint64
+	index    int
+}
+
+// Begin starts timing a named scope inside the current frame. Scopes nest,
+// and each must be ended before the one it was started in:
+//
+//	defer profiler.Begin("update").End()
+func (p *Profiler) Begin(name string) Scope {
+	if p.current == nil {
+		return Scope{}
+	}
+
+	index := len(p.current.Samples)
+	p.current.Samples = append(p.current.Samples, Sample{
+		Name:  name,
+		Depth: len(p.stack),
+		Start: time.Since(p.epoch),
+	})
+	p.stack = append(p.stack, index)
+	return Scope{profiler: p, frame: p.current.Number, index: index}
+}
+
+func (s Scope) End() {
+	p := s.profiler
+	if p == nil || p.current == nil || p.current.Number != s.frame {
+		return
+	}
+
+	sample := &p.current.Samples[s.index]
+	sample.Duration = time.Since(p.epoch) - sample.Start
+
+	// Close this scope and any left open inside it
+	for len(p.stack) > 0 {
+		top := p.stack[len(p.stack)-1]
+		p.stack = p.stack[:len(p.stack)-1]
+		if top == s.index {
+			break
+		}
+		inner := &p.current.Samples[top]
+		inner.Duration = sample.Start + sample.Duration - inner.Start
+	}
+}
+
+// GetFrameCount returns how many finished frames are kept.
+func (p *Profiler) GetFrameCount() int {
+	return p.count
+}
+
+// GetFrame returns a finished frame, 0 being the oldest kept. It is reused
+// once the ring buffer wraps, so don't hold on to it.
+func (p *Profiler) GetFrame(i int) *Frame {
+	if i < 0 || i >= p.count {
+		return nil
+	}
+	start := (p.next - p.count + len(p.frames)) % len(p.frames)
+	return &p.frames[(start+i)%len(p.frames)]
+}
+
+// ScopeStats sums up one scope name over the kept frames.
+type ScopeStats struct {
+	Name    string
+	Depth   int           // Shallowest depth it was seen at
+	Average time.Duration // Per frame, over the frames it appeared in
+	Max     time.Duration // Longest total in a single frame
+	Frames  int           // Frames it appeared in
+}
+
+// GetStats sums every scope over the kept frames, longest average first.
+func (p *Profiler) GetStats() []ScopeStats {
+	totals := make(map[string]*ScopeStats)
+	perFrame := make(map[string]time.Duration)
+
+	for i := 0; i < p.count; i++ {
+		clear(perFrame)
+		for _, sample := range p.GetFrame(i).Samples {
+			stats, exists := totals[sample.Name]
+			if !exists {
+				stats = &ScopeStats{Name: sample.Name, Depth: sample.Depth}
+				totals[sample.Name] = stats
+			}
+			stats.Depth = min(stats.Depth, sample.Depth)
+			perFrame[sample.Name] += sample.Duration
+		}
+		for name, total := range perFrame {
+			stats := totals[name]
+			stats.Average += total
+			stats.Max = max(stats.Max, total)
+			stats.Frames++
+		}
+	}
+
+	result := make([]ScopeStats, 0, len(totals))
+	for _, stats := range totals {
+		stats.Average /= time.Duration(stats.Frames)
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Average != result[j].Average {
+			return result[i].Average > result[j].Average
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func SetEnabled(enabled bool) { profiler.SetEnabled(enabled) }
+func IsEnabled() bool         { return profiler.IsEnabled() }
+func BeginFrame()             { profiler.BeginFrame() }
+func EndFrame()               { profiler.EndFrame() }
+
+// Begin starts a scope on the package-level profiler; see Profiler.Begin.
+func Begin(name string) Scope {
+	return profiler.Begin(name)
+}
@@ -0,0 +1,93 @@
+package profiler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// traceEvent is one entry of the Chrome trace event format, which Perfetto
+// and chrome://tracing open. Complete events ("X") have a start and
+// duration in microseconds.
+type traceEvent struct {
+	Name     string            `json:"name"`
+	Category string            `json:"cat,omitempty"`
+	Phase    string            `json:"ph"`
+	Time     float64           `json:"ts"`
+	Duration float64           `json:"dur,omitempty"`
+	Process  int               `json:"pid"`
+	Thread   int               `json:"tid"`
+	Args     map[string]string `json:"args,omitempty"`
+}
+
+type traceFile struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+func microseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
+
+// WriteChromeTrace writes the kept frames as Chrome trace event JSON, each
+// frame as a "frame" event with its scopes nested inside.
+func (p *Profiler) WriteChromeTrace(w io.Writer) error {
+	events := []traceEvent{{
+		Name:  "thread_name",
+		Phase: "M",
+		Args:  map[string]string{"name": "game loop"},
+	}}
+
+	for i := 0; i < p.count; i++ {
+		frame := p.GetFrame(i)
+		events = append(events, traceEvent{
+			Name:     "frame",
+			Category: "frame",
+			Phase:    "X",
+			Time:     microseconds(frame.Start),
+			Duration: microseconds(frame.Duration),
+			Args:     map[string]string{"number": fmt.Sprint(frame.Number)},
+		})
+		for _, sample := range frame.Samples {
+			events = append(events, traceEvent{
+				Name:     sample.Name,
+				Category: "scope",
+				Phase:    "X",
+				Time:     microseconds(sample.Start),
+				Duration: microseconds(sample.Duration),
+			})
+		}
+	}
+
+	for i := range events {
+		events[i].Process, events[i].Thread = 1, 1
+	}
+
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(traceFile{TraceEvents: events, DisplayTimeUnit: "ms"}); err != nil {
+		return fmt.Errorf("failed to write trace: %w", err)
+	}
+	return nil
+}
+
+// SaveChromeTrace writes the kept frames to a file; see WriteChromeTrace.
+func (p *Profiler) SaveChromeTrace(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create trace '%s': %w", path, err)
+	}
+
+	writer := bufio.NewWriter(file)
+	if err := p.WriteChromeTrace(writer); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write trace '%s': %w", path, err)
+	}
+	return file.Close()
+}
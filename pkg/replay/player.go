@@ -0,0 +1,189 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lunararch/helios/pkg/input"
+)
+
+const (
+	maxTicksPerFrame      = 8   // In real time, so a slow frame doesn't snowball
+	fastForwardPerFrame   = 600 // While fast-forwarding
+	defaultFixedDeltaTime = 1.0 / 60.0
+)
+
+// Player plays a replay back. It is both the game loop's driver, deciding
+// how many updates run each frame and with what delta time, and the input
+// manager's source, giving each of those updates its recorded input:
+//
+//	gameLoop.SetDriver(player)
+//	inputManager.SetSource(player)
+//
+// Check should be called at the end of every update to compare checkpoints.
+type Player struct {
+	Speed float32 // 1 plays in real time, 0 pauses
+
+	header    Header
+	ticks     []Tick
+	next      int     // Tick the next Poll returns
+	current   int     // Tick the running update polled, or -1
+	scheduled int     // Ticks handed to the game loop so far
+	seek      int     // Fast-forward until this tick
+	budget    float32 // Real seconds not yet played
+
+	verified   int
+	divergence error
+}
+
+// NewPlayer reads a whole replay.
+func NewPlayer(r io.Reader) (*Player, error) {
+	decoder := json.NewDecoder(r)
+
+	var header Header
+	if err := decoder.Decode(&header); err != nil {
+		return nil, fmt.Errorf("failed to read replay header: %w", err)
+	}
+	if header.Version != Version {
+		return nil, fmt.Errorf("unsupported replay version %d, expected %d", header.Version, Version)
+	}
+
+	ticks := make([]Tick, 0)
+	for {
+		var tick Tick
+		err := decoder.Decode(&tick)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read replay tick %d: %w", len(ticks), err)
+		}
+		ticks = append(ticks, tick)
+	}
+
+	return &Player{
+		Speed:   1,
+		header:  header,
+		ticks:   ticks,
+		current: -1,
+	}, nil
+}
+
+// Load reads a replay file; see NewPlayer.
+func Load(path string) (*Player, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay '%s': %w", path, err)
+	}
+	defer file.Close()
+
+	p, err := NewPlayer(file)
+	if err != nil {
+		return nil, fmt.Errorf("replay '%s': %w", path, err)
+	}
+	return p, nil
+}
+
+func (p *Player) GetHeader() Header {
+	return p.header
+}
+
+// GetTick returns the number of ticks played.
+func (p *Player) GetTick() int {
+	return p.next
+}
+
+func (p *Player) GetTickCount() int {
+	return len(p.ticks)
+}
+
+// IsFinished is true once every tick has been played.
+func (p *Player) IsFinished() bool {
+	return p.next >= len(p.ticks)
+}
+
+// FastForward plays ticks as fast as possible until the given one, then goes
+// back to Speed. Ticks already played can't be returned to.
+func (p *Player) FastForward(tick int) {
+	p.seek = min(tick, len(p.ticks))
+}
+
+func (p *Player) IsFastForwarding() bool {
+	return p.seek > p.next
+}
+
+// GetVerified returns how many checkpoints matched.
+func (p *Player) GetVerified() int {
+	return p.verified
+}
+
+// GetDivergence returns the first checkpoint mismatch, or nil.
+func (p *Player) GetDivergence() error {
+	return p.divergence
+}
+
+// Ticks returns the recorded delta times of the ticks to play this frame:
+// as many as the real time played at Speed covers, or a batch of them while
+// fast-forwarding.
+func (p *Player) Ticks(frameTime float32) []float32 {
+	count := 0
+	if p.IsFastForwarding() {
+		count = min(p.seek-p.next, fastForwardPerFrame)
+		p.budget = 0
+	} else {
+		tickTime := float32(p.header.FixedDeltaTime)
+		if tickTime <= 0 {
+			tickTime = defaultFixedDeltaTime
+		}
+		p.budget += frameTime * max(p.Speed, 0)
+		count = int(p.budget / tickTime)
+		p.budget -= float32(count) * tickTime
+		if count > maxTicksPerFrame {
+			count, p.budget = maxTicksPerFrame, 0
+		}
+	}
+
+	start := p.next
+	count = min(count, len(p.ticks)-start)
+	p.scheduled = start + count
+
+	deltaTimes := make([]float32, count)
+	for i := range deltaTimes {
+		deltaTimes[i] = p.ticks[start+i].DeltaTime
+	}
+	return deltaTimes
+}
+
+// Poll returns the input of the next tick. Past the last one, or beyond what
+// Ticks handed out, it returns an empty frame.
+func (p *Player) Poll() input.Frame {
+	if p.next >= len(p.ticks) || p.next >= p.scheduled {
+		p.current = -1
+		return input.Frame{}
+	}
+	p.current = p.next
+	p.next++
+	return p.ticks[p.current].Input
+}
+
+// Check compares the state left by the tick just played with the recording,
+// at checkpoints. The first mismatch is kept for GetDivergence.
+func (p *Player) Check(checksum func() uint64) error {
+	if p.current < 0 || p.ticks[p.current].Checksum == nil {
+		return nil
+	}
+
+	tick := p.ticks[p.current]
+	if sum := checksum(); sum != *tick.Checksum {
+		err := fmt.Errorf("replay diverged at tick %d: checksum %016x, recorded %016x", tick.Number, sum, *tick.Checksum)
+		if p.divergence == nil {
+			p.divergence = err
+		}
+		return err
+	}
+	p.verified++
+	return nil
+}
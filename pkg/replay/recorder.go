@@ -0,0 +1,107 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lunararch/helios/pkg/input"
+)
+
+// Recorder writes a replay as the game runs, one tick per update. It
+// flushes at every checkpoint, so a crash loses at most the last interval.
+type Recorder struct {
+	header  Header
+	file    io.Closer // Nil unless the recorder created the file
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	ticks   uint64
+}
+
+// NewRecorder writes the header, filling in the version and a default
+// checkpoint interval.
+func NewRecorder(w io.Writer, header Header) (*Recorder, error) {
+	header.Version = Version
+	if header.CheckpointInterval <= 0 {
+		header.CheckpointInterval = DefaultCheckpointInterval
+	}
+
+	writer := bufio.NewWriter(w)
+	r := &Recorder{
+		header:  header,
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}
+	if err := r.encoder.Encode(header); err != nil {
+		return nil, fmt.Errorf("failed to write replay header: %w", err)
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write replay header: %w", err)
+	}
+	return r, nil
+}
+
+// Create records into a new file; see NewRecorder.
+func Create(path string, header Header) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay '%s': %w", path, err)
+	}
+
+	r, err := NewRecorder(file, header)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	r.file = file
+	return r, nil
+}
+
+func (r *Recorder) GetHeader() Header {
+	return r.header
+}
+
+// GetTickCount returns how many ticks were recorded.
+func (r *Recorder) GetTickCount() uint64 {
+	return r.ticks
+}
+
+// Record writes the update that just ran: its delta time, the input frame it
+// took in and, at checkpoints, the checksum of the state it left.
+func (r *Recorder) Record(deltaTime float32, frame input.Frame, checksum func() uint64) error {
+	tick := Tick{Number: r.ticks, DeltaTime: deltaTime, Input: frame}
+	checkpoint := (r.ticks+1)%uint64(r.header.CheckpointInterval) == 0
+	if checkpoint {
+		sum := checksum()
+		tick.Checksum = &sum
+	}
+
+	if err := r.encoder.Encode(tick); err != nil {
+		return fmt.Errorf("failed to write replay tick %d: %w", r.ticks, err)
+	}
+	r.ticks++
+
+	if checkpoint {
+		if err := r.writer.Flush(); err != nil {
+			return fmt.Errorf("failed to write replay tick %d: %w", tick.Number, err)
+		}
+	}
+	return nil
+}
+
+// Close flushes the ticks not yet written, and closes the file if the
+// recorder created it.
+func (r *Recorder) Close() error {
+	if err := r.writer.Flush(); err != nil {
+		if r.file != nil {
+			r.file.Close()
+		}
+		return fmt.Errorf("failed to write replay: %w", err)
+	}
+	if r.file != nil {
+		return r.file.Close()
+	}
+	return nil
+}
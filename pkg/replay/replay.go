@@ -0,0 +1,38 @@
+// Package replay records a game's input and timing, update by update, and
+// plays them back to reproduce a run exactly.
+//
+// A replay file is JSON lines: a Header, then a Tick for every update. Every
+// CheckpointInterval ticks also carry a checksum of the world, which playback
+// compares to catch the run drifting from the recording. Playback only
+// reproduces a run started from the same state, so replays are recorded and
+// played from the start of the game. Random numbers come from the engine's
+// generator, reseeded from the header when playback starts.
+package replay
+
+import "github.com/lunararch/helios/pkg/input"
+
+// Version is the file format written by Recorder; Player reads only this one.
+const Version = 1
+
+// DefaultCheckpointInterval is how often checksums are recorded when the
+// header doesn't say, once a second at 60 updates per second.
+const DefaultCheckpointInterval = 60
+
+// Header is the first line of a replay file: what the run started from.
+type Header struct {
+	Version            int     `json:"version"`
+	Seed               int64   `json:"seed"`        // Of the engine's random numbers
+	FixedDeltaTime     float64 `json:"fixed_delta"` // Seconds per update, for pacing playback
+	CheckpointInterval int     `json:"checkpoint_interval"`
+	Scene              string  `json:"scene"` // Current when recording began
+	Width              int     `json:"width"` // Window size, which mouse positions depend on
+	Height             int     `json:"height"`
+}
+
+// Tick is one update: the delta time it ran with and the input it took in.
+type Tick struct {
+	Number    uint64      `json:"tick"`
+	DeltaTime float32     `json:"dt"`
+	Input     input.Frame `json:"input"`
+	Checksum  *uint64     `json:"checksum,omitempty"` // Of the state after the update, at checkpoints
+}
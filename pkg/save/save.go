@@ -0,0 +1,113 @@
+// Package save writes the game's state to save slots on disk and reads it
+// back.
+//
+// A Snapshot holds the scene the game is in, the scenes stacked under it and,
+// for each of their worlds, the entities marked persistent. The entities'
+// components are saved through the Persistent interface, which the built-in
+// transform, sprite and animation components implement and custom components
+// can too. Everything else is rebuilt by the scenes themselves when loaded.
+//
+// Snapshots carry a version. Older saves are brought up to date on load by
+// the migrations registered with RegisterMigration.
+package save
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lunararch/helios/pkg/entity"
+)
+
+// Version is the snapshot layout written by this build. Bump it when the
+// layout changes and register a migration from the previous one.
+const Version = 1
+
+// Persistent is implemented by components that save their own state. The
+// data is any JSON; LoadState gets back what SaveState returned, applied to
+// the component the scene built.
+type Persistent interface {
+	SaveState() (json.RawMessage, error)
+	LoadState(data json.RawMessage) error
+}
+
+type Snapshot struct {
+	Version int                      `json:"version"`
+	SavedAt time.Time                `json:"saved_at"`
+	Scene   string                   `json:"scene"`
+	Stack   []string                 `json:"stack,omitempty"` // Scenes under the current one, bottom first
+	Worlds  map[string][]EntityState `json:"worlds"`          // By scene name
+}
+
+type EntityState struct {
+	ID         entity.EntityID           `json:"id"`
+	Name       string                    `json:"name"`
+	Active     bool                      `json:"active"`
+	Layer      int                       `json:"layer"`
+	Parent     entity.EntityID           `json:"parent,omitempty"` // 0 for none
+	Components map[string]ComponentState `json:"components"`       // By component type name
+}
+
+type ComponentState struct {
+	Active bool            `json:"active"`
+	Data   json.RawMessage `json:"data,omitempty"` // From Persistent.SaveState
+}
+
+// Migration brings a snapshot's decoded JSON up from one version to the next.
+// Numbers in it are json.Number, so IDs and 64-bit values keep every digit.
+type Migration func(snapshot map[string]any) error
+
+var migrations = make(map[int]Migration)
+
+// RegisterMigration sets the migration from version from to from+1.
+func RegisterMigration(from int, migration Migration) {
+	migrations[from] = migration
+}
+
+// decodeSnapshot reads a snapshot of any version up to target, migrating it
+// to target, which is Version outside tests.
+func decodeSnapshot(data []byte, target int) (*Snapshot, error) {
+	var raw map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	number, ok := raw["version"].(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("snapshot has no version")
+	}
+	version64, err := number.Int64()
+	if err != nil {
+		return nil, fmt.Errorf("snapshot version '%s' is not a whole number", number)
+	}
+	version := int(version64)
+	if version > target {
+		return nil, fmt.Errorf("snapshot version %d is newer than %d", version, target)
+	}
+
+	if version < target {
+		for ; version < target; version++ {
+			migration, exists := migrations[version]
+			if !exists {
+				return nil, fmt.Errorf("no migration from snapshot version %d", version)
+			}
+			if err := migration(raw); err != nil {
+				return nil, fmt.Errorf("failed to migrate snapshot from version %d: %w", version, err)
+			}
+		}
+		raw["version"] = target
+
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, fmt.Errorf("failed to encode migrated snapshot: %w", err)
+		}
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,206 @@
+package save
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lunararch/helios/pkg/entity"
+	"github.com/lunararch/helios/pkg/scene"
+)
+
+// Capture snapshots the current scene, the stack under it and the persistent
+// entities of their worlds.
+func Capture(manager *scene.SceneManager) (*Snapshot, error) {
+	current := manager.GetCurrentScene()
+	if current == nil {
+		return nil, fmt.Errorf("no scene to save")
+	}
+
+	snapshot := &Snapshot{
+		Version: Version,
+		SavedAt: time.Now(),
+		Scene:   current.GetName(),
+		Stack:   manager.GetStack(),
+		Worlds:  make(map[string][]EntityState),
+	}
+
+	for _, name := range append(slices.Clone(snapshot.Stack), snapshot.Scene) {
+		world := sceneWorld(manager, name)
+		if world == nil {
+			continue
+		}
+		entities, err := CaptureWorld(world)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save scene '%s': %w", name, err)
+		}
+		if len(entities) > 0 {
+			snapshot.Worlds[name] = entities
+		}
+	}
+	return snapshot, nil
+}
+
+// CaptureWorld snapshots a world's persistent entities, by ID.
+func CaptureWorld(world *entity.World) ([]EntityState, error) {
+	persistent := world.GetPersistentEntities()
+	states := make([]EntityState, 0, len(persistent))
+
+	for _, e := range persistent {
+		state := EntityState{
+			ID:         e.ID,
+			Name:       e.GetName(),
+			Active:     e.IsActive(),
+			Layer:      e.GetLayer(),
+			Components: make(map[string]ComponentState),
+		}
+		if parent := e.GetParent(); parent != nil {
+			state.Parent = parent.ID
+		}
+
+		for componentType, component := range e.GetComponents() {
+			componentState := ComponentState{Active: component.IsActive()}
+			if p, ok := component.(Persistent); ok {
+				data, err := p.SaveState()
+				if err != nil {
+					return nil, fmt.Errorf("failed to save %s of entity '%s': %w", componentType, e.GetName(), err)
+				}
+				componentState.Data = data
+			}
+			state.Components[componentType.String()] = componentState
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// Restore brings the game back to a snapshot: it switches to the saved scene
+// and stack at once, then restores the worlds. Whatever can be restored is;
+// the returned error joins everything that couldn't.
+func Restore(manager *scene.SceneManager, snapshot *Snapshot) error {
+	if err := manager.Restore(snapshot.Scene, snapshot.Stack); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(snapshot.Worlds))
+	for name := range snapshot.Worlds {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var errs []error
+	for _, name := range names {
+		world := sceneWorld(manager, name)
+		if world == nil {
+			errs = append(errs, fmt.Errorf("scene '%s' has no world to load into", name))
+			continue
+		}
+		if err := RestoreWorld(world, snapshot.Worlds[name]); err != nil {
+			errs = append(errs, fmt.Errorf("scene '%s': %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RestoreWorld applies saved entities to a world. Entities the scene already
+// built are matched by ID and updated, missing ones are created with only a
+// transform, and persistent entities the save doesn't have are destroyed.
+// Saved components the entity lacks are reported rather than created, since
+// only the scene knows how to build them.
+func RestoreWorld(world *entity.World, states []EntityState) error {
+	saved := make(map[entity.EntityID]bool, len(states))
+	for _, state := range states {
+		saved[state.ID] = true
+	}
+	for _, e := range world.GetPersistentEntities() {
+		if _, exists := world.GetEntity(e.ID); exists && !saved[e.ID] {
+			world.DestroyEntity(e.ID)
+		}
+	}
+
+	var errs []error
+	for _, state := range states {
+		e, exists := world.GetEntity(state.ID)
+		if !exists {
+			var err error
+			if e, err = world.CreateEntityWithID(state.ID, state.Name); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		e.SetName(state.Name)
+		e.SetActive(state.Active)
+		e.SetLayer(state.Layer)
+		e.SetPersistent(true)
+		if err := restoreComponents(e, state.Components); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, state := range states {
+		e, exists := world.GetEntity(state.ID)
+		if !exists {
+			continue
+		}
+		var parent *entity.Entity
+		if state.Parent != 0 {
+			if parent, exists = world.GetEntity(state.Parent); !exists {
+				errs = append(errs, fmt.Errorf("parent %d of entity '%s' not found", state.Parent, state.Name))
+				continue
+			}
+		}
+		if e.GetParent() != parent {
+			e.SetParent(parent)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restoreComponents loads components in type order, so an animation sets the
+// sprite frame after the sprite itself is restored.
+func restoreComponents(e *entity.Entity, states map[string]ComponentState) error {
+	components := make(map[string]entity.Component, len(e.GetComponents()))
+	types := make([]entity.ComponentType, 0, len(e.GetComponents()))
+	for componentType, component := range e.GetComponents() {
+		components[componentType.String()] = component
+		types = append(types, componentType)
+	}
+	slices.Sort(types)
+
+	var errs []error
+	for name := range states {
+		if _, exists := components[name]; !exists {
+			errs = append(errs, fmt.Errorf("entity '%s' has no %s component to load into", e.GetName(), name))
+		}
+	}
+
+	for _, componentType := range types {
+		name := componentType.String()
+		state, exists := states[name]
+		if !exists {
+			continue
+		}
+		component := components[name]
+		component.SetActive(state.Active)
+		if p, ok := component.(Persistent); ok && state.Data != nil {
+			if err := p.LoadState(state.Data); err != nil {
+				errs = append(errs, fmt.Errorf("failed to load %s of entity '%s': %w", name, e.GetName(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sceneWorld(manager *scene.SceneManager, name string) *entity.World {
+	s, exists := manager.GetScene(name)
+	if !exists {
+		return nil
+	}
+	worldScene, ok := s.(scene.WorldScene)
+	if !ok {
+		return nil
+	}
+	return worldScene.GetWorld()
+}
@@ -0,0 +1,275 @@
+package save
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A save file is a fixed header followed by the snapshot's JSON, gzipped if
+// the compressed flag is set. The checksum covers the payload as stored, so
+// a truncated or corrupted file is caught before it is decoded.
+//
+//	magic    [4]byte  "HSAV"
+//	format   uint16   fileFormat
+//	flags    uint16   flagCompressed
+//	checksum uint32   CRC-32 (IEEE) of the payload
+//	length   uint32   of the payload
+const (
+	fileMagic      = "HSAV"
+	fileFormat     = 1
+	fileExtension  = ".sav"
+	flagCompressed = 1 << 0
+
+	// Bounds what a damaged or hostile file can make Decode allocate
+	maxPayloadSize  = 64 << 20
+	maxSnapshotSize = 256 << 20 // Once decompressed
+)
+
+type fileHeader struct {
+	Magic    [4]byte
+	Format   uint16
+	Flags    uint16
+	Checksum uint32
+	Length   uint32
+}
+
+var slotPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Store keeps save slots as files in a directory, one per slot.
+type Store struct {
+	Dir      string
+	Compress bool // Gzip new saves; loading reads either
+}
+
+// SlotInfo describes a saved slot without restoring it.
+type SlotInfo struct {
+	Slot    string
+	Scene   string
+	SavedAt time.Time
+	Size    int64
+}
+
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir, Compress: true}
+}
+
+// Save writes a slot atomically: the file is written beside the old one and
+// renamed over it, so a crash mid-save leaves the previous save intact.
+func (s *Store) Save(slot string, snapshot *Snapshot) error {
+	path, err := s.path(slot)
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	if err := Encode(&buffer, snapshot, s.Compress); err != nil {
+		return fmt.Errorf("failed to save slot '%s': %w", slot, err)
+	}
+
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create save directory '%s': %w", s.Dir, err)
+	}
+	if err := writeFileAtomic(path, buffer.Bytes()); err != nil {
+		return fmt.Errorf("failed to save slot '%s': %w", slot, err)
+	}
+	return nil
+}
+
+// Load reads a slot, migrating it to the current Version.
+func (s *Store) Load(slot string) (*Snapshot, error) {
+	path, err := s.path(slot)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open save slot '%s': %w", slot, err)
+	}
+	defer file.Close()
+
+	snapshot, err := Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("save slot '%s': %w", slot, err)
+	}
+	return snapshot, nil
+}
+
+func (s *Store) Exists(slot string) bool {
+	path, err := s.path(slot)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+func (s *Store) Delete(slot string) error {
+	path, err := s.path(slot)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to delete save slot '%s': %w", slot, err)
+	}
+	return nil
+}
+
+// List describes every readable slot, newest first. Unreadable files are
+// skipped; Load reports what is wrong with them.
+func (s *Store) List() ([]SlotInfo, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read save directory '%s': %w", s.Dir, err)
+	}
+
+	slots := make([]SlotInfo, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		slot, isSave := strings.CutSuffix(name, fileExtension)
+		if entry.IsDir() || !isSave || !slotPattern.MatchString(slot) {
+			continue
+		}
+
+		snapshot, err := s.Load(slot)
+		if err != nil {
+			continue
+		}
+		info := SlotInfo{Slot: slot, Scene: snapshot.Scene, SavedAt: snapshot.SavedAt}
+		if stat, err := entry.Info(); err == nil {
+			info.Size = stat.Size()
+		}
+		slots = append(slots, info)
+	}
+
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].SavedAt.After(slots[j].SavedAt)
+	})
+	return slots, nil
+}
+
+func (s *Store) path(slot string) (string, error) {
+	if !slotPattern.MatchString(slot) {
+		return "", fmt.Errorf("invalid save slot name '%s'", slot)
+	}
+	return filepath.Join(s.Dir, slot+fileExtension), nil
+}
+
+// Encode writes a snapshot in the save file format.
+func Encode(w io.Writer, snapshot *Snapshot, compress bool) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	header := fileHeader{Format: fileFormat}
+	copy(header.Magic[:], fileMagic)
+
+	if compress {
+		var buffer bytes.Buffer
+		writer := gzip.NewWriter(&buffer)
+		if _, err := writer.Write(data); err != nil {
+			return fmt.Errorf("failed to compress snapshot: %w", err)
+		}
+		if err := writer.Close(); err != nil {
+			return fmt.Errorf("failed to compress snapshot: %w", err)
+		}
+		data = buffer.Bytes()
+		header.Flags |= flagCompressed
+	}
+
+	header.Checksum = crc32.ChecksumIEEE(data)
+	header.Length = uint32(len(data))
+
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Decode reads a snapshot in the save file format, checking it and migrating
+// it to the current Version.
+func Decode(r io.Reader) (*Snapshot, error) {
+	var header fileHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("failed to read save header: %w", err)
+	}
+	if string(header.Magic[:]) != fileMagic {
+		return nil, fmt.Errorf("not a save file")
+	}
+	if header.Format != fileFormat {
+		return nil, fmt.Errorf("unsupported save file format %d, expected %d", header.Format, fileFormat)
+	}
+
+	if header.Length > maxPayloadSize {
+		return nil, fmt.Errorf("save file payload of %d bytes is over the %d byte limit", header.Length, maxPayloadSize)
+	}
+
+	// Read through a limit rather than allocating the claimed length up front
+	data, err := io.ReadAll(io.LimitReader(r, int64(header.Length)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read save payload: %w", err)
+	}
+	if len(data) != int(header.Length) {
+		return nil, fmt.Errorf("save file is truncated: %d of %d payload bytes", len(data), header.Length)
+	}
+	if sum := crc32.ChecksumIEEE(data); sum != header.Checksum {
+		return nil, fmt.Errorf("save file is corrupted: checksum %08x, expected %08x", sum, header.Checksum)
+	}
+
+	if header.Flags&flagCompressed != 0 {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress save: %w", err)
+		}
+		if data, err = io.ReadAll(io.LimitReader(reader, maxSnapshotSize+1)); err != nil {
+			return nil, fmt.Errorf("failed to decompress save: %w", err)
+		}
+		if len(data) > maxSnapshotSize {
+			return nil, fmt.Errorf("save snapshot is over the %d byte limit", maxSnapshotSize)
+		}
+	}
+
+	return decodeSnapshot(data, Version)
+}
+
+// writeFileAtomic writes to a temporary file in the same directory, syncs it
+// and renames it over path, which replaces the file in one step.
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	tempPath := file.Name()
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tempPath, path)
+	}
+	if err != nil {
+		os.Remove(tempPath)
+	}
+	return err
+}
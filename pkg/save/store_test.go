@@ -0,0 +1,198 @@
+package save
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lunararch/helios/pkg/entity"
+)
+
+func testSnapshot() *Snapshot {
+	return &Snapshot{
+		Version: Version,
+		SavedAt: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+		Scene:   "gameplay",
+		Stack:   []string{"menu"},
+		Worlds: map[string][]EntityState{
+			"gameplay": {
+				{
+					ID:     1<<60 + 1, // More digits than a float64 keeps
+					Name:   "Knight",
+					Active: true,
+					Layer:  3,
+					Components: map[string]ComponentState{
+						"transform": {Active: true, Data: json.RawMessage(`{"position":[1.5,-2,0],"rotation":0.25}`)},
+						"sprite":    {Active: false},
+					},
+				},
+				{ID: 7, Name: "Sword", Active: true, Parent: 1<<60 + 1, Components: map[string]ComponentState{}},
+			},
+			"menu": {},
+		},
+	}
+}
+
+func encode(t *testing.T, snapshot *Snapshot, compress bool) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := Encode(&buffer, snapshot, compress); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		t.Run(fmt.Sprintf("compress=%v", compress), func(t *testing.T) {
+			want := testSnapshot()
+			got, err := Decode(bytes.NewReader(encode(t, want, compress)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("decoded\n%+v\nwant\n%+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		damage func(data []byte) []byte
+		err    string
+	}{
+		{"empty", func(data []byte) []byte { return nil }, "failed to read save header"},
+		{"bad magic", func(data []byte) []byte { data[0] = 'X'; return data }, "not a save file"},
+		{"future format", func(data []byte) []byte {
+			binary.LittleEndian.PutUint16(data[4:], fileFormat+1)
+			return data
+		}, "unsupported save file format"},
+		{"truncated", func(data []byte) []byte { return data[:len(data)-5] }, "truncated"},
+		{"flipped bit", func(data []byte) []byte { data[len(data)-3] ^= 0x10; return data }, "corrupted"},
+		{"huge length", func(data []byte) []byte {
+			binary.LittleEndian.PutUint32(data[12:], maxPayloadSize+1)
+			return data
+		}, "over the"},
+	}
+
+	for _, compress := range []bool{false, true} {
+		for _, test := range tests {
+			data := test.damage(encode(t, testSnapshot(), compress))
+			_, err := Decode(bytes.NewReader(data))
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s (compress=%v): got error %v, want one containing %q", test.name, compress, err, test.err)
+			}
+		}
+	}
+}
+
+// withMigrations swaps in a set of migrations for one test.
+func withMigrations(t *testing.T, replacement map[int]Migration) {
+	previous := migrations
+	migrations = replacement
+	t.Cleanup(func() { migrations = previous })
+}
+
+func TestMigrationChain(t *testing.T) {
+	var order []int
+	withMigrations(t, map[int]Migration{
+		// Version 1 renamed "level" to "scene"
+		0: func(snapshot map[string]any) error {
+			order = append(order, 0)
+			snapshot["scene"] = snapshot["level"]
+			delete(snapshot, "level")
+			return nil
+		},
+		// Version 2 added the scene stack
+		1: func(snapshot map[string]any) error {
+			order = append(order, 1)
+			if _, exists := snapshot["stack"]; !exists {
+				snapshot["stack"] = []any{"menu"}
+			}
+			return nil
+		},
+		// Version 3 made layers start from 1
+		2: func(snapshot map[string]any) error {
+			order = append(order, 2)
+			for _, world := range snapshot["worlds"].(map[string]any) {
+				for _, state := range world.([]any) {
+					state := state.(map[string]any)
+					layer, err := state["layer"].(json.Number).Int64()
+					if err != nil {
+						return err
+					}
+					state["layer"] = layer + 1
+				}
+			}
+			return nil
+		},
+	})
+
+	entityV0 := `{"id": 1152921504606846977, "name": "Knight", "active": true, "layer": 0, "components": {}}`
+	tests := []struct {
+		name  string
+		data  string
+		order []int
+		scene string
+		stack []string
+		layer int
+	}{
+		{"from 0", `{"version": 0, "level": "cave", "worlds": {"cave": [` + entityV0 + `]}}`, []int{0, 1, 2}, "cave", []string{"menu"}, 1},
+		{"from 1", `{"version": 1, "scene": "cave", "worlds": {"cave": [` + entityV0 + `]}}`, []int{1, 2}, "cave", []string{"menu"}, 1},
+		{"from 2", `{"version": 2, "scene": "cave", "worlds": {"cave": [` + entityV0 + `]}}`, []int{2}, "cave", nil, 1},
+		{"current", `{"version": 3, "scene": "cave", "worlds": {"cave": [` + entityV0 + `]}}`, nil, "cave", nil, 0},
+	}
+
+	for _, test := range tests {
+		order = nil
+		snapshot, err := decodeSnapshot([]byte(test.data), 3)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(order, test.order) {
+			t.Errorf("%s: ran migrations %v, want %v", test.name, order, test.order)
+		}
+		if snapshot.Version != 3 || snapshot.Scene != test.scene || !reflect.DeepEqual(snapshot.Stack, test.stack) {
+			t.Errorf("%s: got version %d, scene %q, stack %q", test.name, snapshot.Version, snapshot.Scene, snapshot.Stack)
+		}
+
+		states := snapshot.Worlds[test.scene]
+		if len(states) != 1 || states[0].ID != entity.EntityID(1<<60+1) || states[0].Layer != test.layer {
+			t.Errorf("%s: got entities %+v, want ID %d on layer %d", test.name, states, uint64(1<<60+1), test.layer)
+		}
+	}
+}
+
+func TestMigrationErrors(t *testing.T) {
+	withMigrations(t, map[int]Migration{
+		0: func(snapshot map[string]any) error { return nil },
+		1: func(snapshot map[string]any) error { return fmt.Errorf("no good") },
+	})
+
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{`{"version": 1}`, "failed to migrate snapshot from version 1: no good"},
+		{`{"version": -1}`, "no migration from snapshot version -1"},
+		{`{"version": 4}`, "snapshot version 4 is newer than 3"},
+		{`{"version": 1.5}`, "is not a whole number"},
+		{`{"scene": "cave"}`, "snapshot has no version"},
+		{`[1, 2]`, "failed to decode snapshot"},
+	}
+
+	for _, test := range tests {
+		_, err := decodeSnapshot([]byte(test.data), 3)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, want one containing %q", test.data, err, test.err)
+		}
+	}
+}
@@ -6,21 +6,30 @@ import (
 	"github.com/go-gl/gl/all-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/debug"
 	"github.com/lunararch/helios/pkg/engine"
 	"github.com/lunararch/helios/pkg/entity"
 	"github.com/lunararch/helios/pkg/graphics/animation"
 	"github.com/lunararch/helios/pkg/graphics/camera"
+	"github.com/lunararch/helios/pkg/graphics/lighting"
+	"github.com/lunararch/helios/pkg/graphics/material"
+	"github.com/lunararch/helios/pkg/graphics/postprocess"
 	"github.com/lunararch/helios/pkg/graphics/shader"
 	"github.com/lunararch/helios/pkg/graphics/sprite"
 	"github.com/lunararch/helios/pkg/graphics/texture"
 	"github.com/lunararch/helios/pkg/input"
+	"github.com/lunararch/helios/pkg/profiler"
+	"github.com/lunararch/helios/pkg/tween"
 )
 
 type AnimatedGameplayScene struct {
 	*BaseScene
 
 	batchShader *shader.Shader
+	flashShader *shader.Shader
 	spriteBatch *sprite.SpriteBatch
+	flash       *material.Material
+	flashAmount float32
 
 	world *entity.World
 
@@ -32,13 +41,24 @@ type AnimatedGameplayScene struct {
 	hornetEntity   *entity.Entity
 	animatedEntity *entity.Entity
 
-	characterSpriteSheet *animation.SpriteSheet
-	idleAnimation        *animation.AnimationClip
-	walkAnimation        *animation.AnimationClip
-	jumpAnimation        *animation.AnimationClip
+	// 8 frames the knight and hornet with the camera, 9 shakes it
+	knightCamera *entity.CameraComponent
+	hornetCamera *entity.CameraComponent
+
+	// 0 toggles a minimap of the whole level in the top-right corner
+	minimap *camera.Camera
+
+	characterController *animation.AnimationController
 
 	rotationTimer *engine.Timer
-	printTimer    *engine.Timer
+	cycleTimer    *engine.Timer
+	tweens        *tween.Manager
+
+	postProcess *postprocess.Chain
+	bloom       *postprocess.Bloom
+	vignette    *postprocess.Vignette
+	crt         *postprocess.CRT
+	lights      *lighting.Renderer
 
 	cameraSpeed float32
 }
@@ -61,13 +81,17 @@ func (s *AnimatedGameplayScene) Load() error {
 		return err
 	}
 
-	projection := mgl32.Ortho(0, s.camera.Size.X(), s.camera.Size.Y(), 0, -1, 1)
-	s.batchShader.Use()
-	s.batchShader.SetInt("texture1", 0)
-	s.batchShader.SetMat4("projection", projection)
-
 	s.spriteBatch = sprite.NewSpriteBatch(s.batchShader)
 
+	// The hornet flashes white whenever the knight spins
+	s.flashShader, err = shader.New("assets/shaders/batch.vert", "assets/shaders/flash.frag")
+	if err != nil {
+		return err
+	}
+	s.flash = material.New("flash", s.flashShader)
+	s.flash.SetVec3("flashColor", mgl32.Vec3{1, 1, 1})
+	s.setFlash(0)
+
 	s.knightTexture, err = texture.LoadFromFile("assets/textures/knight.png")
 	if err != nil {
 		return err
@@ -78,123 +102,122 @@ func (s *AnimatedGameplayScene) Load() error {
 		return err
 	}
 
-	// Create a simple character sprite sheet (using existing textures as example)
+	// Using knight texture as example
 	// In a real project, you'd have an actual sprite sheet
-	s.characterSheet = s.knightTexture                                          // Using knight texture as example
-	s.characterSpriteSheet = animation.NewSpriteSheet(s.characterSheet, 32, 32) // Assuming 32x32 frames
+	s.characterSheet = s.knightTexture
 
-	s.createAnimations()
+	s.characterController, err = animation.LoadAnimationController("assets/animations/character.json", &animation.ControllerResources{
+		Textures: map[string]*texture.Texture{"character": s.characterSheet},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.setupPostProcessing(); err != nil {
+		return err
+	}
 
 	s.world = entity.NewWorld()
 
 	s.knightEntity = s.world.CreateEntity("Knight")
+	s.knightEntity.SetPersistent(true)
 	s.knightEntity.GetTransform().SetPosition2D(100.0, 100.0)
 
 	knightSprite := entity.NewSpriteComponent(s.knightTexture, s.spriteBatch)
 	s.knightEntity.AddComponent(knightSprite)
 
 	s.hornetEntity = s.world.CreateEntity("Hornet")
+	s.hornetEntity.SetPersistent(true)
 	s.hornetEntity.GetTransform().SetPosition2D(300.0, 200.0)
 
 	hornetSprite := entity.NewSpriteComponent(s.hornetTexture, s.spriteBatch)
+	hornetSprite.SetMaterial(s.flash)
 	s.hornetEntity.AddComponent(hornetSprite)
 
+	// The sprite hangs from the entity position by its top-left corner
+	hornetWidth, hornetHeight := float32(s.hornetTexture.Width), float32(s.hornetTexture.Height)
+	s.hornetEntity.AddComponent(entity.NewOccluderComponent([]mgl32.Vec2{
+		{0, 0}, {hornetWidth, 0}, {hornetWidth, hornetHeight}, {0, hornetHeight},
+	}, s.lights))
+
 	s.animatedEntity = s.world.CreateEntity("Animated Character")
+	s.animatedEntity.SetPersistent(true)
 	s.animatedEntity.GetTransform().SetPosition2D(200.0, 150.0)
 
 	animatedSprite := entity.NewSpriteComponent(s.characterSheet, s.spriteBatch)
 	s.animatedEntity.AddComponent(animatedSprite)
 
 	animationComp := entity.NewAnimationComponent(animatedSprite)
-	s.setupAnimationStateMachine(animationComp)
+	animationComp.SetStateMachine(s.characterController.NewStateMachine())
 	s.animatedEntity.AddComponent(animationComp)
 
+	torch := lighting.NewPointLight(mgl32.Vec2{}, mgl32.Vec3{1.0, 0.8, 0.55}, 260)
+	s.animatedEntity.AddComponent(entity.NewLightComponent(torch, s.lights))
+
+	spotlight := lighting.NewSpotLight(mgl32.Vec2{}, mgl32.Vec3{0.5, 0.7, 1.0}, 320, math.Pi/2, 0.5)
+	s.knightEntity.AddComponent(entity.NewLightComponent(spotlight, s.lights))
+
+	s.camera.Follow.Mode = camera.FollowSmoothDamp
+	s.camera.Follow.DeadZone = mgl32.Vec2{64, 48}
+	s.camera.Follow.LookAhead = 0.3
+	s.camera.Framing.Enabled = true
+
+	s.knightCamera = entity.NewCameraComponent(s.camera)
+	s.knightCamera.SetActive(false)
+	s.knightEntity.AddComponent(s.knightCamera)
+
+	s.hornetCamera = entity.NewCameraComponent(s.camera)
+	s.hornetCamera.SetActive(false)
+	s.hornetEntity.AddComponent(s.hornetCamera)
+
+	s.minimap = camera.New(s.camera.Size.X()/4, s.camera.Size.Y()/4)
+	s.minimap.Rect = mgl32.Vec4{0.72, 0.03, 0.25, 0.25}
+	s.minimap.Order = 1
+	s.minimap.Zoom = 0.25
+	s.minimap.Position = s.camera.Size.Mul(0.5)
+	s.minimap.Clear = camera.ClearAll
+	s.minimap.Background = mgl32.Vec4{0.05, 0.05, 0.1, 1}
+	s.minimap.Enabled = false
+	if viewport := s.camera.GetViewport(); viewport != nil {
+		viewport.AddCamera(s.minimap)
+	}
+	s.AddCamera(s.minimap)
+
+	s.tweens = tween.NewManager()
+
 	s.rotationTimer = engine.NewRepeatingTimer(2.0)
 	s.rotationTimer.SetOnComplete(func() {
-		s.knightEntity.GetTransform().Rotate(0.5)
+		// A random turn either way, from the engine's generator so replays match
+		random := engine.GetRandom()
+		turn := random.Range(0.3, 0.8)
+		if random.Chance(0.5) {
+			turn = -turn
+		}
+		s.tweens.Add(tween.RotateBy(s.knightEntity.GetTransform(), turn, 0.4).SetEase(tween.OutBack))
+		s.setFlash(1.0)
+		s.tweens.Add(tween.Float32Func(func() float32 { return s.flashAmount }, s.setFlash, 0, 0.33))
 	})
 	s.rotationTimer.Start()
 
-	s.printTimer = engine.NewTimer(5.0)
-	s.printTimer.SetOnComplete(func() {
-		println("=== Animated Gameplay Scene Stats ===")
-		println("Entity count:", s.world.GetEntityCount())
-
-		if animComp, ok := s.animatedEntity.GetComponent(entity.ComponentTypeAnimation); ok {
-			animationComponent := animComp.(*entity.AnimationComponent)
-			println("Current animation state:", animationComponent.GetCurrentStateName())
-			println("Animation playing:", animationComponent.IsPlaying())
-		}
-
-		println("========================================")
-		s.printTimer.Restart()
+	// The hornet circles its starting point every five seconds
+	center := s.hornetEntity.GetTransform().Position.Vec2()
+	s.tweens.Add(tween.New(5.0, func(t float32) {
+		angle := float64(t) * 2 * math.Pi
+		s.hornetEntity.GetTransform().SetPosition2D(
+			center.X()+50*float32(math.Cos(angle)),
+			center.Y()+50*float32(math.Sin(angle)))
+	}).SetRepeat(tween.RepeatForever))
+
+	// Loops every five seconds, driving the character's animation parameters
+	s.cycleTimer = engine.NewTimer(5.0)
+	s.cycleTimer.SetOnComplete(func() {
+		s.cycleTimer.Restart()
 	})
-	s.printTimer.Start()
+	s.cycleTimer.Start()
 
 	return nil
 }
 
-func (s *AnimatedGameplayScene) createAnimations() {
-	// Create sample animations
-	// Note: These are simplified examples using the same texture
-	// In a real project, you'd have actual sprite sheet frames
-
-	builder := animation.NewAnimationBuilder(s.characterSpriteSheet, "idle", true)
-	s.idleAnimation = builder.
-		AddFrame(0, 0.5). // Frame 0 for 0.5 seconds
-		AddFrame(1, 0.5). // Frame 1 for 0.5 seconds
-		Build()
-
-	builder = animation.NewAnimationBuilder(s.characterSpriteSheet, "walk", true)
-	s.walkAnimation = builder.
-		AddFrameRange(2, 5, 0.2). // Frames 2-5, each for 0.2 seconds
-		Build()
-
-	builder = animation.NewAnimationBuilder(s.characterSpriteSheet, "jump", false)
-	s.jumpAnimation = builder.
-		AddFrame(6, 0.1). // Jump start
-		AddFrame(7, 0.3). // In air
-		AddFrame(8, 0.1). // Landing
-		Build()
-}
-
-func (s *AnimatedGameplayScene) setupAnimationStateMachine(animComp *entity.AnimationComponent) {
-	stateMachine := animComp.GetStateMachine()
-
-	// Create states
-	idleState := animation.NewAnimationState("idle", s.idleAnimation)
-	walkState := animation.NewAnimationState("walk", s.walkAnimation)
-	jumpState := animation.NewAnimationState("jump", s.jumpAnimation)
-
-	// Add transitions
-	idleState.AddTransition("walk", "walk", func(sm *animation.AnimationStateMachine) bool {
-		return sm.GetBool("isWalking")
-	})
-
-	idleState.AddTransition("jump", "jump", func(sm *animation.AnimationStateMachine) bool {
-		return sm.GetBool("isJumping")
-	})
-
-	walkState.AddTransition("idle", "idle", func(sm *animation.AnimationStateMachine) bool {
-		return !sm.GetBool("isWalking")
-	})
-
-	walkState.AddTransition("jump", "jump", func(sm *animation.AnimationStateMachine) bool {
-		return sm.GetBool("isJumping")
-	})
-
-	jumpState.AddTimedTransition("complete", "idle", jumpState.Clip.TotalTime, func(sm *animation.AnimationStateMachine) bool {
-		return true
-	})
-
-	// Add states to state machine
-	stateMachine.AddState(idleState)
-	stateMachine.AddState(walkState)
-	stateMachine.AddState(jumpState)
-
-	stateMachine.SetState("idle")
-}
-
 func (s *AnimatedGameplayScene) Update(deltaTime float32) error {
 	if err := s.BaseScene.Update(deltaTime); err != nil {
 		return err
@@ -205,29 +228,21 @@ func (s *AnimatedGameplayScene) Update(deltaTime float32) error {
 	}
 
 	s.rotationTimer.Update(deltaTime)
-	s.printTimer.Update(deltaTime)
+	s.cycleTimer.Update(deltaTime)
+	scope := profiler.Begin("tweens")
+	s.tweens.Update(deltaTime)
+	scope.End()
 
+	scope = profiler.Begin("world")
 	s.world.Update(deltaTime)
-
-	if s.hornetEntity != nil {
-		time := s.printTimer.GetProgress() * 6.28 // 2 * PI
-		transform := s.hornetEntity.GetTransform()
-		baseX := float32(300.0)
-		baseY := float32(200.0)
-		radius := float32(50.0)
-
-		newX := baseX + radius*float32(math.Cos(float64(time)))
-		newY := baseY + radius*float32(math.Sin(float64(time)))
-
-		transform.SetPosition2D(newX, newY)
-	}
+	scope.End()
 
 	if s.animatedEntity != nil {
 		if animComp, ok := s.animatedEntity.GetComponent(entity.ComponentTypeAnimation); ok {
 			animationComponent := animComp.(*entity.AnimationComponent)
 			stateMachine := animationComponent.GetStateMachine()
 
-			time := s.printTimer.GetProgress()
+			time := s.cycleTimer.GetProgress()
 			if time < 2.0 {
 				stateMachine.SetParameter("isWalking", false)
 				stateMachine.SetParameter("isJumping", false)
@@ -244,16 +259,88 @@ func (s *AnimatedGameplayScene) Update(deltaTime float32) error {
 	return nil
 }
 
+func (s *AnimatedGameplayScene) setFlash(amount float32) {
+	s.flashAmount = amount
+	s.flash.SetFloat("amount", amount)
+}
+
+// setupPostProcessing adds the scene's effects; 4, 5, 6 and 7 toggle bloom,
+// the vignette, the CRT filter and lighting.
+func (s *AnimatedGameplayScene) setupPostProcessing() error {
+	var err error
+	s.postProcess, err = postprocess.NewChain()
+	if err != nil {
+		return err
+	}
+
+	if s.lights, err = lighting.NewRenderer(lighting.QualityMedium); err != nil {
+		return err
+	}
+	if s.bloom, err = postprocess.NewBloom(0.8, 0.6); err != nil {
+		return err
+	}
+	if s.vignette, err = postprocess.NewVignette(); err != nil {
+		return err
+	}
+	if s.crt, err = postprocess.NewCRT(); err != nil {
+		return err
+	}
+
+	for _, effect := range []postprocess.Effect{s.lights, s.bloom, s.vignette, s.crt} {
+		if err := s.postProcess.Add(effect); err != nil {
+			return err
+		}
+	}
+	s.postProcess.SetEnabled(s.bloom, false)
+	s.postProcess.SetEnabled(s.crt, false)
+	return nil
+}
+
 func (s *AnimatedGameplayScene) Render(alpha float32) error {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-	s.batchShader.Use()
-	s.batchShader.SetMat4("view", s.camera.GetViewMatrix())
+	return s.RenderCameras(func(cam *camera.Camera) error {
+		if cam == s.camera {
+			return s.renderMain(alpha)
+		}
+		s.renderWorld(cam, alpha)
+		return nil
+	})
+}
+
+func (s *AnimatedGameplayScene) renderWorld(cam *camera.Camera, alpha float32) {
+	defer profiler.Begin("world_render").End()
+	s.spriteBatch.SetCamera(cam.GetProjectionMatrix(), cam.GetViewMatrix())
 
 	s.spriteBatch.Begin()
-	s.world.Render(alpha)
+	s.world.RenderCamera(alpha, cam)
 	s.spriteBatch.End()
+}
 
+// renderMain draws the main camera's view with lighting and post-processing.
+func (s *AnimatedGameplayScene) renderMain(alpha float32) error {
+	if err := s.postProcess.Begin(); err != nil {
+		return err
+	}
+
+	s.renderWorld(s.camera, alpha)
+
+	if s.postProcess.IsEnabled(s.lights) {
+		scope := profiler.Begin("lights")
+		if s.lights.BeginNormals(s.spriteBatch) {
+			s.world.RenderCamera(alpha, s.camera)
+			s.lights.EndNormals(s.spriteBatch)
+		}
+		s.lights.Render(s.camera)
+		scope.End()
+	}
+
+	scope := profiler.Begin("post_process")
+	s.postProcess.End()
+	scope.End()
+
+	debug.Get().RenderOverlays(s.world, s.camera)
+	debug.Get().Render(s.camera)
 	return nil
 }
 
@@ -305,6 +392,37 @@ func (s *AnimatedGameplayScene) HandleInput(inputManager *input.InputManager, in
 		}
 	}
 
+	if s.postProcess != nil {
+		if inputManager.IsKeyPressed(glfw.Key4) {
+			s.postProcess.SetEnabled(s.bloom, !s.postProcess.IsEnabled(s.bloom))
+		}
+		if inputManager.IsKeyPressed(glfw.Key5) {
+			s.postProcess.SetEnabled(s.vignette, !s.postProcess.IsEnabled(s.vignette))
+		}
+		if inputManager.IsKeyPressed(glfw.Key6) {
+			s.postProcess.SetEnabled(s.crt, !s.postProcess.IsEnabled(s.crt))
+		}
+		if inputManager.IsKeyPressed(glfw.Key7) {
+			s.postProcess.SetEnabled(s.lights, !s.postProcess.IsEnabled(s.lights))
+		}
+	}
+
+	if inputManager.IsKeyPressed(glfw.Key8) {
+		following := !s.knightCamera.IsActive()
+		s.knightCamera.SetActive(following)
+		s.hornetCamera.SetActive(following)
+		if !following {
+			s.tweens.Add(tween.Zoom(s.camera, 1.0, 0.5).SetEase(tween.OutQuad))
+		}
+	}
+	if inputManager.IsKeyPressed(glfw.Key9) {
+		s.camera.AddTrauma(0.5)
+		debug.Text(mgl32.Vec2{8, 8}, "Trauma +0.5", debug.Yellow, debug.InScreenSpace(), debug.Scale(2), debug.For(1))
+	}
+	if inputManager.IsKeyPressed(glfw.Key0) {
+		s.minimap.Enabled = !s.minimap.Enabled
+	}
+
 	scrollDelta := inputManager.GetScrollDelta()
 	if scrollDelta.Y() != 0 {
 		zoomFactor := 1.0 + scrollDelta.Y()*0.1
@@ -321,10 +439,17 @@ func (s *AnimatedGameplayScene) HandleInput(inputManager *input.InputManager, in
 	return nil
 }
 
+func (s *AnimatedGameplayScene) GetWorld() *entity.World {
+	return s.world
+}
+
 func (s *AnimatedGameplayScene) Unload() error {
 	if s.batchShader != nil {
 		s.batchShader.Delete()
 	}
+	if s.flashShader != nil {
+		s.flashShader.Delete()
+	}
 	if s.spriteBatch != nil {
 		s.spriteBatch.Delete()
 	}
@@ -337,9 +462,18 @@ func (s *AnimatedGameplayScene) Unload() error {
 	if s.characterSheet != nil {
 		s.characterSheet.Delete()
 	}
+	if s.postProcess != nil {
+		s.postProcess.Delete()
+	}
 	if s.world != nil {
 		s.world.Cleanup()
 	}
+	if s.minimap != nil {
+		if viewport := s.minimap.GetViewport(); viewport != nil {
+			viewport.RemoveCamera(s.minimap)
+		}
+		s.RemoveCamera(s.minimap)
+	}
 
 	return s.BaseScene.Unload()
 }
@@ -4,7 +4,9 @@ import (
 	"math"
 
 	"github.com/go-gl/gl/all-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/debug"
 	"github.com/lunararch/helios/pkg/engine"
 	"github.com/lunararch/helios/pkg/entity"
 	"github.com/lunararch/helios/pkg/graphics/camera"
@@ -12,6 +14,7 @@ import (
 	"github.com/lunararch/helios/pkg/graphics/sprite"
 	"github.com/lunararch/helios/pkg/graphics/texture"
 	"github.com/lunararch/helios/pkg/input"
+	"github.com/lunararch/helios/pkg/profiler"
 )
 
 type GameplayScene struct {
@@ -28,8 +31,12 @@ type GameplayScene struct {
 	knightEntity *entity.Entity
 	hornetEntity *entity.Entity
 
+	// V switches to split screen, one half following each character
+	splitScreen  bool
+	splitCameras [2]*camera.Camera
+
 	rotationTimer *engine.Timer
-	printTimer    *engine.Timer
+	cycleTimer    *engine.Timer
 
 	cameraSpeed float32
 }
@@ -52,11 +59,6 @@ func (s *GameplayScene) Load() error {
 		return err
 	}
 
-	projection := mgl32.Ortho(0, s.camera.Size.X(), s.camera.Size.Y(), 0, -1, 1)
-	s.batchShader.Use()
-	s.batchShader.SetInt("texture1", 0)
-	s.batchShader.SetMat4("projection", projection)
-
 	s.spriteBatch = sprite.NewSpriteBatch(s.batchShader)
 
 	s.knightTexture, err = texture.LoadFromFile("assets/textures/knight.png")
@@ -72,12 +74,14 @@ func (s *GameplayScene) Load() error {
 	s.world = entity.NewWorld()
 
 	s.knightEntity = s.world.CreateEntity("Knight")
+	s.knightEntity.SetPersistent(true)
 	s.knightEntity.GetTransform().SetPosition2D(100.0, 100.0)
 
 	knightSprite := entity.NewSpriteComponent(s.knightTexture, s.spriteBatch)
 	s.knightEntity.AddComponent(knightSprite)
 
 	s.hornetEntity = s.world.CreateEntity("Hornet")
+	s.hornetEntity.SetPersistent(true)
 	s.hornetEntity.GetTransform().SetPosition2D(300.0, 200.0)
 
 	hornetSprite := entity.NewSpriteComponent(s.hornetTexture, s.spriteBatch)
@@ -92,28 +96,40 @@ func (s *GameplayScene) Load() error {
 	weaponSprite.SetColor(mgl32.Vec4{1.0, 0.5, 0.5, 1.0}) // Reddish tint
 	weaponEntity.AddComponent(weaponSprite)
 
+	for i, followed := range []*entity.Entity{s.knightEntity, s.hornetEntity} {
+		cam := camera.New(s.camera.Size.X()/2, s.camera.Size.Y())
+		cam.Rect = mgl32.Vec4{float32(i) * 0.5, 0, 0.5, 1}
+		cam.Follow.Mode = camera.FollowSmoothDamp
+		if s.camera.BoundsEnabled {
+			cam.SetBounds(s.camera.MinBounds.X(), s.camera.MinBounds.Y(), s.camera.MaxBounds.X(), s.camera.MaxBounds.Y())
+		}
+		if viewport := s.camera.GetViewport(); viewport != nil {
+			viewport.AddCamera(cam)
+		}
+		followed.AddComponent(entity.NewCameraComponent(cam))
+		s.splitCameras[i] = cam
+	}
+
 	s.rotationTimer = engine.NewRepeatingTimer(2.0)
 	s.rotationTimer.SetOnComplete(func() {
 		s.knightEntity.GetTransform().Rotate(0.5)
 	})
 	s.rotationTimer.Start()
 
-	s.printTimer = engine.NewTimer(5.0)
-	s.printTimer.SetOnComplete(func() {
-		println("=== Gameplay Scene Stats ===")
-		println("Entity count:", s.world.GetEntityCount())
-		println("Knight position:", s.knightEntity.GetTransform().Position.X(), s.knightEntity.GetTransform().Position.Y())
-		println("Knight world position:", s.knightEntity.GetWorldPosition().X(), s.knightEntity.GetWorldPosition().Y())
-		println("Camera position:", s.camera.Position.X(), s.camera.Position.Y())
-		println("Camera zoom:", s.camera.Zoom)
-		println("============================")
-		s.printTimer.Restart()
+	// Loops every five seconds, driving the hornet's orbit
+	s.cycleTimer = engine.NewTimer(5.0)
+	s.cycleTimer.SetOnComplete(func() {
+		s.cycleTimer.Restart()
 	})
-	s.printTimer.Start()
+	s.cycleTimer.Start()
 
 	return nil
 }
 
+func (s *GameplayScene) GetWorld() *entity.World {
+	return s.world
+}
+
 func (s *GameplayScene) Unload() error {
 	if s.batchShader != nil {
 		s.batchShader.Delete()
@@ -130,6 +146,16 @@ func (s *GameplayScene) Unload() error {
 	if s.world != nil {
 		s.world.Cleanup()
 	}
+	for _, cam := range s.splitCameras {
+		if viewport := cam.GetViewport(); viewport != nil {
+			viewport.RemoveCamera(cam)
+		}
+		s.RemoveCamera(cam)
+	}
+	if s.splitScreen {
+		s.splitScreen = false
+		s.AddCamera(s.camera)
+	}
 
 	return s.BaseScene.Unload()
 }
@@ -144,12 +170,14 @@ func (s *GameplayScene) Update(deltaTime float32) error {
 	}
 
 	s.rotationTimer.Update(deltaTime)
-	s.printTimer.Update(deltaTime)
+	s.cycleTimer.Update(deltaTime)
 
+	scope := profiler.Begin("world")
 	s.world.Update(deltaTime)
+	scope.End()
 
 	if s.hornetEntity != nil {
-		time := s.printTimer.GetProgress() * 6.28 // 2 * PI
+		time := s.cycleTimer.GetProgress() * 6.28 // 2 * PI
 		transform := s.hornetEntity.GetTransform()
 		baseX := float32(300.0)
 		baseY := float32(200.0)
@@ -167,16 +195,36 @@ func (s *GameplayScene) Update(deltaTime float32) error {
 func (s *GameplayScene) Render(alpha float32) error {
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-	s.batchShader.Use()
-	s.batchShader.SetMat4("view", s.camera.GetViewMatrix())
+	return s.RenderCameras(func(cam *camera.Camera) error {
+		s.spriteBatch.SetCamera(cam.GetProjectionMatrix(), cam.GetViewMatrix())
 
-	s.spriteBatch.Begin()
+		scope := profiler.Begin("world_render")
+		s.spriteBatch.Begin()
 
-	s.world.Render(alpha)
+		s.world.RenderCamera(alpha, cam)
 
-	s.spriteBatch.End()
+		s.spriteBatch.End()
+		scope.End()
 
-	return nil
+		debug.Get().RenderOverlays(s.world, cam)
+		debug.Get().Render(cam)
+		return nil
+	})
+}
+
+func (s *GameplayScene) toggleSplitScreen() {
+	s.splitScreen = !s.splitScreen
+	if s.splitScreen {
+		s.RemoveCamera(s.camera)
+		for _, cam := range s.splitCameras {
+			s.AddCamera(cam)
+		}
+	} else {
+		for _, cam := range s.splitCameras {
+			s.RemoveCamera(cam)
+		}
+		s.AddCamera(s.camera)
+	}
 }
 
 func (s *GameplayScene) HandleInput(inputManager *input.InputManager, inputMapping *input.InputMapping) error {
@@ -219,6 +267,10 @@ func (s *GameplayScene) HandleInput(inputManager *input.InputManager, inputMappi
 		}
 	}
 
+	if inputManager.IsKeyPressed(glfw.KeyV) {
+		s.toggleSplitScreen()
+	}
+
 	s.camera.ClampToBounds()
 
 	return nil
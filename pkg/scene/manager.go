@@ -120,6 +120,61 @@ func (sm *SceneManager) GetCurrentScene() Scene {
 	return sm.currentScene
 }
 
+func (sm *SceneManager) GetScene(name string) (Scene, bool) {
+	scene, exists := sm.scenes[name]
+	return scene, exists
+}
+
+// GetStack returns the names of the scenes pushed under the current one,
+// bottom first.
+func (sm *SceneManager) GetStack() []string {
+	names := make([]string, len(sm.sceneStack))
+	for i, scene := range sm.sceneStack {
+		names[i] = scene.GetName()
+	}
+	return names
+}
+
+// Restore puts back a current scene and the stack under it, as loading a
+// save game does. Unlike SwitchToScene it takes effect straight away, and
+// every scene involved is loaded so their worlds can be filled in.
+func (sm *SceneManager) Restore(current string, stack []string) error {
+	next, exists := sm.scenes[current]
+	if !exists {
+		return fmt.Errorf("scene '%s' not found", current)
+	}
+
+	sceneStack := make([]Scene, 0, len(stack))
+	for _, name := range stack {
+		scene, exists := sm.scenes[name]
+		if !exists {
+			return fmt.Errorf("scene '%s' not found", name)
+		}
+		sceneStack = append(sceneStack, scene)
+	}
+
+	for _, scene := range sceneStack {
+		if !scene.IsLoaded() {
+			if err := scene.Load(); err != nil {
+				return fmt.Errorf("failed to load scene '%s': %w", scene.GetName(), err)
+			}
+		}
+		scene.Pause()
+	}
+	sm.sceneStack = sceneStack
+
+	if next == sm.currentScene {
+		sm.nextScene = nil
+		sm.transitioning = false
+		return nil
+	}
+
+	sm.nextScene = next
+	err := sm.performTransition()
+	sm.transitioning = false
+	return err
+}
+
 func (sm *SceneManager) GetSceneCount() int {
 	return len(sm.scenes)
 }
@@ -2,18 +2,45 @@ package scene
 
 import (
 	"github.com/go-gl/gl/all-core/gl"
-	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
 	"github.com/lunararch/helios/pkg/graphics/camera"
+	"github.com/lunararch/helios/pkg/graphics/shader"
+	"github.com/lunararch/helios/pkg/graphics/sprite"
+	"github.com/lunararch/helios/pkg/graphics/texture"
 	"github.com/lunararch/helios/pkg/input"
+	"github.com/lunararch/helios/pkg/ui"
 )
 
 type MenuScene struct {
 	*BaseScene
+
+	// Set by the game, since scenes can't switch scenes themselves
+	OnStart func(sceneName string)
+	OnQuit  func()
+
+	// Settings picked in the menu
+	PlayerName  string
+	Volume      float32
+	ScreenShake bool
+
+	shapeShader   *shader.Shader
+	shapeBatch    *sprite.ShapeBatch
+	batchShader   *shader.Shader
+	spriteBatch   *sprite.SpriteBatch
+	knightTexture *texture.Texture
+
+	canvas    *ui.Canvas
+	renderer  *ui.Renderer
+	startWith string
 }
 
 func NewMenuScene(camera *camera.Camera) *MenuScene {
 	return &MenuScene{
-		BaseScene: NewBaseScene("menu", camera),
+		BaseScene:   NewBaseScene("menu", camera),
+		PlayerName:  "Knight",
+		Volume:      0.8,
+		ScreenShake: true,
+		startWith:   "animated_gameplay",
 	}
 }
 
@@ -22,11 +49,126 @@ func (s *MenuScene) Load() error {
 		return err
 	}
 
+	var err error
+	s.shapeShader, err = shader.New("assets/shaders/shape.vert", "assets/shaders/shape.frag")
+	if err != nil {
+		return err
+	}
+	s.shapeBatch = sprite.NewShapeBatch(s.shapeShader)
+
+	s.batchShader, err = shader.New("assets/shaders/batch.vert", "assets/shaders/batch.frag")
+	if err != nil {
+		return err
+	}
+	s.spriteBatch = sprite.NewSpriteBatch(s.batchShader)
+
+	s.knightTexture, err = texture.LoadFromFile("assets/textures/knight.png")
+	if err != nil {
+		return err
+	}
+
+	s.renderer = ui.NewRenderer(s.spriteBatch, s.shapeBatch)
+	s.buildUI()
+
 	println("Menu scene loaded")
 	return nil
 }
 
+// buildUI lays the menu out in a panel centered on screen.
+func (s *MenuScene) buildUI() {
+	s.canvas = ui.NewCanvas(s.camera, nil)
+	s.canvas.OnCancel = s.quit
+
+	knight := ui.NewTextureImage(s.knightTexture)
+	knight.Size = mgl32.Vec2{32, 32}
+
+	name := ui.NewTextInput("Player name")
+	name.MaxLength = 16
+	name.Grow = 1
+	name.SetText(s.PlayerName)
+	name.OnChange = func(text string) { s.PlayerName = text }
+
+	levels := ui.NewScrollList()
+	levels.Size = mgl32.Vec2{0, 72}
+	for _, level := range []struct{ label, scene string }{
+		{"Animated gameplay", "animated_gameplay"},
+		{"Gameplay", "gameplay"},
+	} {
+		sceneName := level.scene
+		levels.AddItem(level.label, func() {
+			s.startWith = sceneName
+			s.start()
+		})
+	}
+
+	volume := ui.NewSlider(0, 1, s.Volume, func(value float32) { s.Volume = value })
+	volume.Step = 0.1
+	volume.Grow = 1
+	volumeLabel := ui.NewLabel("Volume")
+	volumeLabel.Size = mgl32.Vec2{72, 0}
+
+	shake := ui.NewCheckbox("Screen shake", s.ScreenShake, func(checked bool) { s.ScreenShake = checked })
+
+	start := ui.NewButton("Start", s.start)
+	start.Align = ui.AlignCenter
+	quit := ui.NewButton("Quit", s.quit)
+	quit.Align = ui.AlignCenter
+
+	panel := ui.NewColumn(8,
+		ui.NewTitle("Helios"),
+		ui.NewRow(8, knight, name),
+		start,
+		levels,
+		ui.NewRow(8, volumeLabel, volume),
+		shake,
+		quit,
+	)
+	panel.Transparent = false
+	panel.Padding = ui.Uniform(16)
+	panel.Anchor = ui.AnchorAt(0.5, 0.5)
+	panel.Size = mgl32.Vec2{300, 0}
+
+	s.canvas.Add(panel)
+	s.canvas.SetFocus(start)
+}
+
+func (s *MenuScene) start() {
+	if s.OnStart != nil {
+		s.OnStart(s.startWith)
+	} else {
+		println("Start pressed - should switch to", s.startWith)
+	}
+}
+
+func (s *MenuScene) quit() {
+	if s.OnQuit != nil {
+		s.OnQuit()
+	}
+}
+
+// WantsKeyboard is true while a text field is being edited, so the game's
+// own key bindings should be ignored.
+func (s *MenuScene) WantsKeyboard() bool {
+	return s.canvas != nil && s.canvas.WantsKeyboard()
+}
+
 func (s *MenuScene) Unload() error {
+	if s.knightTexture != nil {
+		s.knightTexture.Delete()
+	}
+	if s.spriteBatch != nil {
+		s.spriteBatch.Delete()
+	}
+	if s.batchShader != nil {
+		s.batchShader.Delete()
+	}
+	if s.shapeBatch != nil {
+		s.shapeBatch.Delete()
+	}
+	if s.shapeShader != nil {
+		s.shapeShader.Delete()
+	}
+
 	println("Menu scene unloaded")
 	return s.BaseScene.Unload()
 }
@@ -49,15 +191,28 @@ func (s *MenuScene) Render(alpha float32) error {
 	gl.ClearColor(0.1, 0.1, 0.2, 1.0)
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-	// Here you would render menu UI elements
-
+	s.renderBackdrop()
+	s.canvas.Render(s.renderer)
 	return nil
 }
 
-func (s *MenuScene) HandleInput(inputManager *input.InputManager, inputMapping *input.InputMapping) error {
-	if inputManager.IsKeyPressed(glfw.KeyEnter) {
-		println("Enter pressed - should switch to gameplay")
-	}
+// renderBackdrop draws the background behind the menu in camera pixels.
+func (s *MenuScene) renderBackdrop() {
+	size := s.camera.Size
+	s.shapeBatch.SetCamera(s.camera.GetProjectionMatrix(), mgl32.Ident4())
 
+	s.shapeBatch.Begin()
+
+	s.shapeBatch.FillRect(mgl32.Vec2{0, 0}, size, sprite.LinearGradient(
+		mgl32.Vec2{0, 0}, mgl32.Vec2{0, size.Y()},
+		mgl32.Vec4{0.12, 0.12, 0.25, 1}, mgl32.Vec4{0.04, 0.04, 0.1, 1}))
+	s.shapeBatch.FillCircle(size.Mul(0.5), size.Y()*0.6, sprite.RadialGradient(
+		size.Mul(0.5), size.Y()*0.6, mgl32.Vec4{0.3, 0.35, 0.7, 0.25}, mgl32.Vec4{0.3, 0.35, 0.7, 0}))
+
+	s.shapeBatch.End()
+}
+
+func (s *MenuScene) HandleInput(inputManager *input.InputManager, inputMapping *input.InputMapping) error {
+	s.canvas.Update(inputManager.GetDeltaTime(), inputManager, inputMapping)
 	return nil
 }
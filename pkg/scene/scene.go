@@ -1,6 +1,7 @@
 package scene
 
 import (
+	"github.com/lunararch/helios/pkg/entity"
 	"github.com/lunararch/helios/pkg/graphics/camera"
 	"github.com/lunararch/helios/pkg/input"
 )
@@ -26,20 +27,33 @@ type Scene interface {
 	IsLoaded() bool
 }
 
+// WorldScene is a scene built on an entity world, which tools like the
+// inspector can look into.
+type WorldScene interface {
+	Scene
+	GetWorld() *entity.World
+}
+
 type BaseScene struct {
-	name   string
-	loaded bool
-	paused bool
-	camera *camera.Camera
-}
-
-func NewBaseScene(name string, camera *camera.Camera) *BaseScene {
-	return &BaseScene{
-		name:   name,
-		loaded: false,
-		paused: false,
-		camera: camera,
+	name    string
+	loaded  bool
+	paused  bool
+	camera  *camera.Camera
+	cameras []*camera.Camera // Every camera the scene renders through, in order
+}
+
+func NewBaseScene(name string, mainCamera *camera.Camera) *BaseScene {
+	scene := &BaseScene{
+		name:    name,
+		loaded:  false,
+		paused:  false,
+		camera:  mainCamera,
+		cameras: make([]*camera.Camera, 0),
 	}
+	if mainCamera != nil {
+		scene.cameras = append(scene.cameras, mainCamera)
+	}
+	return scene
 }
 
 func (s *BaseScene) GetName() string {
@@ -66,10 +80,55 @@ func (s *BaseScene) IsPaused() bool {
 	return s.paused
 }
 
+// GetCamera returns the scene's main camera.
 func (s *BaseScene) GetCamera() *camera.Camera {
 	return s.camera
 }
 
+// AddCamera adds a camera the scene renders through, such as a split-screen
+// view, a minimap or a UI camera. Set its Rect, Order and LayerMask first.
+func (s *BaseScene) AddCamera(cam *camera.Camera) {
+	for _, existing := range s.cameras {
+		if existing == cam {
+			return
+		}
+	}
+	s.cameras = append(s.cameras, cam)
+}
+
+func (s *BaseScene) RemoveCamera(cam *camera.Camera) {
+	for i, existing := range s.cameras {
+		if existing == cam {
+			s.cameras = append(s.cameras[:i], s.cameras[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetCameras returns the scene's cameras in drawing order.
+func (s *BaseScene) GetCameras() []*camera.Camera {
+	camera.SortByOrder(s.cameras)
+	return s.cameras
+}
+
+// RenderCameras calls draw once for each enabled camera, in order, with GL
+// drawing into that camera's rectangle.
+func (s *BaseScene) RenderCameras(draw func(cam *camera.Camera) error) error {
+	for _, cam := range s.GetCameras() {
+		if !cam.Enabled {
+			continue
+		}
+
+		cam.Begin()
+		err := draw(cam)
+		cam.End()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *BaseScene) Load() error {
 	s.loaded = true
 	return nil
@@ -94,8 +153,8 @@ func (s *BaseScene) Update(deltaTime float32) error {
 		return nil
 	}
 
-	if s.camera != nil {
-		s.camera.Update(deltaTime)
+	for _, cam := range s.cameras {
+		cam.Update(deltaTime)
 	}
 
 	return nil
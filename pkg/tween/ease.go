@@ -0,0 +1,139 @@
+package tween
+
+import "math"
+
+// EaseFunc maps linear progress in [0, 1] to eased progress. Back and elastic
+// curves overshoot outside that range on purpose.
+type EaseFunc func(t float32) float32
+
+const (
+	backOvershoot = 1.70158
+	backInOut     = backOvershoot * 1.525
+	elasticPeriod = (2 * math.Pi) / 3
+	elasticInOut  = (2 * math.Pi) / 4.5
+)
+
+func Linear(t float32) float32 { return t }
+
+func InQuad(t float32) float32    { return t * t }
+func OutQuad(t float32) float32   { return 1 - (1-t)*(1-t) }
+func InOutQuad(t float32) float32 { return inOut(t, InQuad) }
+
+func InCubic(t float32) float32    { return t * t * t }
+func OutCubic(t float32) float32   { return 1 - pow(1-t, 3) }
+func InOutCubic(t float32) float32 { return inOut(t, InCubic) }
+
+func InQuart(t float32) float32    { return t * t * t * t }
+func OutQuart(t float32) float32   { return 1 - pow(1-t, 4) }
+func InOutQuart(t float32) float32 { return inOut(t, InQuart) }
+
+func InQuint(t float32) float32    { return t * t * t * t * t }
+func OutQuint(t float32) float32   { return 1 - pow(1-t, 5) }
+func InOutQuint(t float32) float32 { return inOut(t, InQuint) }
+
+func InSine(t float32) float32  { return 1 - float32(math.Cos(float64(t)*math.Pi/2)) }
+func OutSine(t float32) float32 { return float32(math.Sin(float64(t) * math.Pi / 2)) }
+func InOutSine(t float32) float32 {
+	return -(float32(math.Cos(math.Pi*float64(t))) - 1) / 2
+}
+
+func InExpo(t float32) float32 {
+	if t <= 0 {
+		return 0
+	}
+	return pow(2, 10*t-10)
+}
+
+func OutExpo(t float32) float32 {
+	if t >= 1 {
+		return 1
+	}
+	return 1 - pow(2, -10*t)
+}
+
+func InOutExpo(t float32) float32 { return inOut(t, InExpo) }
+
+func InCirc(t float32) float32    { return 1 - float32(math.Sqrt(float64(1-t*t))) }
+func OutCirc(t float32) float32   { return float32(math.Sqrt(float64(1 - (t-1)*(t-1)))) }
+func InOutCirc(t float32) float32 { return inOut(t, InCirc) }
+
+func InBack(t float32) float32 {
+	return (backOvershoot+1)*t*t*t - backOvershoot*t*t
+}
+
+func OutBack(t float32) float32 {
+	return 1 + (backOvershoot+1)*pow(t-1, 3) + backOvershoot*pow(t-1, 2)
+}
+
+func InOutBack(t float32) float32 {
+	if t < 0.5 {
+		return (pow(2*t, 2) * ((backInOut+1)*2*t - backInOut)) / 2
+	}
+	return (pow(2*t-2, 2)*((backInOut+1)*(t*2-2)+backInOut) + 2) / 2
+}
+
+func InElastic(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return clampUnit(t)
+	}
+	return -pow(2, 10*t-10) * float32(math.Sin((float64(t)*10-10.75)*elasticPeriod))
+}
+
+func OutElastic(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return clampUnit(t)
+	}
+	return pow(2, -10*t)*float32(math.Sin((float64(t)*10-0.75)*elasticPeriod)) + 1
+}
+
+func InOutElastic(t float32) float32 {
+	if t <= 0 || t >= 1 {
+		return clampUnit(t)
+	}
+	sin := float32(math.Sin((20*float64(t) - 11.125) * elasticInOut))
+	if t < 0.5 {
+		return -(pow(2, 20*t-10) * sin) / 2
+	}
+	return (pow(2, -20*t+10)*sin)/2 + 1
+}
+
+func InBounce(t float32) float32 { return 1 - OutBounce(1-t) }
+
+func OutBounce(t float32) float32 {
+	const n, d = 7.5625, 2.75
+
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
+
+func InOutBounce(t float32) float32 { return inOut(t, InBounce) }
+
+// inOut mirrors an ease-in curve into the matching ease-in-out curve.
+func inOut(t float32, in EaseFunc) float32 {
+	if t < 0.5 {
+		return in(t*2) / 2
+	}
+	return 1 - in((1-t)*2)/2
+}
+
+func pow(x, y float32) float32 {
+	return float32(math.Pow(float64(x), float64(y)))
+}
+
+func clampUnit(t float32) float32 {
+	if t <= 0 {
+		return 0
+	}
+	return 1
+}
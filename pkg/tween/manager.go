@@ -0,0 +1,102 @@
+package tween
+
+import "slices"
+
+// Manager owns running tweeners and advances them with the game's delta time,
+// so pausing or scaling time in the game loop applies to every tween.
+type Manager struct {
+	tweeners  []Tweener
+	removed   []Tweener // Removed by callbacks during Update, dropped after it
+	updating  bool
+	timeScale float32
+	paused    bool
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		tweeners:  make([]Tweener, 0),
+		timeScale: 1.0,
+	}
+}
+
+func (m *Manager) Add(tweener Tweener) Tweener {
+	m.tweeners = append(m.tweeners, tweener)
+	return tweener
+}
+
+func (m *Manager) Remove(tweener Tweener) {
+	// Removing now would shift the tweeners Update is walking and skip one
+	if m.updating {
+		m.removed = append(m.removed, tweener)
+		return
+	}
+
+	for i, existing := range m.tweeners {
+		if existing == tweener {
+			m.tweeners = append(m.tweeners[:i], m.tweeners[i+1:]...)
+			return
+		}
+	}
+}
+
+func (m *Manager) Update(deltaTime float32) {
+	if m.paused {
+		return
+	}
+
+	deltaTime *= m.timeScale
+
+	// Tweeners added by callbacks during this update start on the next one
+	m.updating = true
+	count := len(m.tweeners)
+	for i := 0; i < count && i < len(m.tweeners); i++ {
+		if !slices.Contains(m.removed, m.tweeners[i]) {
+			m.tweeners[i].Update(deltaTime)
+		}
+	}
+	m.updating = false
+
+	active := m.tweeners[:0]
+	for _, tweener := range m.tweeners {
+		if !tweener.IsFinished() && !slices.Contains(m.removed, tweener) {
+			active = append(active, tweener)
+		}
+	}
+	clear(m.removed)
+	m.removed = m.removed[:0]
+	for i := len(active); i < len(m.tweeners); i++ {
+		m.tweeners[i] = nil
+	}
+	m.tweeners = active
+}
+
+func (m *Manager) Clear() {
+	m.tweeners = m.tweeners[:0]
+}
+
+func (m *Manager) SetTimeScale(scale float32) {
+	if scale < 0 {
+		scale = 0
+	}
+	m.timeScale = scale
+}
+
+func (m *Manager) GetTimeScale() float32 {
+	return m.timeScale
+}
+
+func (m *Manager) Pause() {
+	m.paused = true
+}
+
+func (m *Manager) Resume() {
+	m.paused = false
+}
+
+func (m *Manager) IsPaused() bool {
+	return m.paused
+}
+
+func (m *Manager) Count() int {
+	return len(m.tweeners)
+}
@@ -0,0 +1,187 @@
+package tween
+
+// Sequence plays its steps one after another.
+type Sequence struct {
+	steps       []Tweener
+	index       int
+	repeat      int
+	repeatsLeft int
+	finished    bool
+	paused      bool
+	onComplete  func()
+}
+
+func NewSequence(steps ...Tweener) *Sequence {
+	return &Sequence{
+		steps: steps,
+	}
+}
+
+func (s *Sequence) Append(step Tweener) *Sequence {
+	s.steps = append(s.steps, step)
+	return s
+}
+
+func (s *Sequence) AppendDelay(seconds float32) *Sequence {
+	return s.Append(Delay(seconds))
+}
+
+func (s *Sequence) AppendCallback(callback func()) *Sequence {
+	return s.Append(Callback(callback))
+}
+
+// Join adds a step that plays all the given tweeners at the same time.
+func (s *Sequence) Join(steps ...Tweener) *Sequence {
+	return s.Append(NewParallel(steps...))
+}
+
+func (s *Sequence) SetRepeat(count int) *Sequence {
+	s.repeat = count
+	s.repeatsLeft = count
+	return s
+}
+
+func (s *Sequence) SetOnComplete(callback func()) *Sequence {
+	s.onComplete = callback
+	return s
+}
+
+func (s *Sequence) Update(deltaTime float32) (bool, float32) {
+	if s.finished {
+		return true, deltaTime
+	}
+	if s.paused {
+		return false, 0
+	}
+
+	passTime := float32(-1) // Time left when the current pass began
+	for s.index < len(s.steps) {
+		done, leftover := s.steps[s.index].Update(deltaTime)
+		if !done {
+			return false, 0
+		}
+
+		// A step that finishes mid-frame hands the rest of the frame to the
+		// next, so step boundaries don't lose time
+		deltaTime = leftover
+		s.index++
+
+		if s.index == len(s.steps) && s.repeatsLeft != 0 {
+			if s.repeatsLeft > 0 {
+				s.repeatsLeft--
+			}
+			s.rewind()
+
+			// A pass that took no time would repeat forever; go on next frame
+			if deltaTime == passTime {
+				return false, 0
+			}
+			passTime = deltaTime
+		}
+	}
+
+	s.finished = true
+	if s.onComplete != nil {
+		s.onComplete()
+	}
+	return true, deltaTime
+}
+
+func (s *Sequence) rewind() {
+	s.index = 0
+	for _, step := range s.steps {
+		step.Restart()
+	}
+}
+
+func (s *Sequence) Restart() {
+	s.rewind()
+	s.repeatsLeft = s.repeat
+	s.finished = false
+	s.paused = false
+}
+
+func (s *Sequence) Kill() {
+	s.finished = true
+}
+
+func (s *Sequence) Pause() {
+	s.paused = true
+}
+
+func (s *Sequence) Resume() {
+	s.paused = false
+}
+
+func (s *Sequence) IsFinished() bool {
+	return s.finished
+}
+
+// Parallel plays all of its tweeners together and finishes with the last one.
+type Parallel struct {
+	tweeners   []Tweener
+	finished   bool
+	onComplete func()
+}
+
+func NewParallel(tweeners ...Tweener) *Parallel {
+	return &Parallel{
+		tweeners: tweeners,
+	}
+}
+
+func (p *Parallel) Add(tweener Tweener) *Parallel {
+	p.tweeners = append(p.tweeners, tweener)
+	return p
+}
+
+func (p *Parallel) SetOnComplete(callback func()) *Parallel {
+	p.onComplete = callback
+	return p
+}
+
+// Update leaves over what the last tweener to finish did.
+func (p *Parallel) Update(deltaTime float32) (bool, float32) {
+	if p.finished {
+		return true, deltaTime
+	}
+
+	done, leftover := true, deltaTime
+	for _, tweener := range p.tweeners {
+		finished, tweenerLeftover := tweener.Update(deltaTime)
+		if !finished {
+			done = false
+		}
+		leftover = min(leftover, tweenerLeftover)
+	}
+
+	if !done {
+		return false, 0
+	}
+	p.finished = true
+	if p.onComplete != nil {
+		p.onComplete()
+	}
+	return true, leftover
+}
+
+func (p *Parallel) Restart() {
+	for _, tweener := range p.tweeners {
+		tweener.Restart()
+	}
+	p.finished = false
+}
+
+func (p *Parallel) IsFinished() bool {
+	return p.finished
+}
+
+// Delay is a tween that does nothing for the given number of seconds.
+func Delay(seconds float32) *Tween {
+	return New(seconds, func(t float32) {})
+}
+
+// Callback is a zero-length step that calls fn when reached.
+func Callback(fn func()) *Tween {
+	return New(0, func(t float32) {}).SetOnComplete(fn)
+}
@@ -0,0 +1,108 @@
+package tween
+
+import (
+	"math"
+	"testing"
+)
+
+const epsilon = 1e-4
+
+// finishTime steps a tweener by a fixed delta until it finishes and returns
+// the time it finished at: the time stepped, less what it left over.
+func finishTime(t *testing.T, tweener Tweener, deltaTime float32) float32 {
+	t.Helper()
+	var elapsed float32
+	for range 10000 {
+		elapsed += deltaTime
+		if done, leftover := tweener.Update(deltaTime); done {
+			return elapsed - leftover
+		}
+	}
+	t.Fatal("tweener never finished")
+	return 0
+}
+
+func TestLeftoverTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func() Tweener
+		duration float32
+	}{
+		{"tween", func() Tweener { return Delay(0.5) }, 0.5},
+		{"tween with delay", func() Tweener { return Delay(0.5).SetDelay(0.25) }, 0.75},
+		{"repeating tween", func() Tweener { return Delay(0.3).SetRepeat(2) }, 0.9},
+		{"sequence", func() Tweener { return NewSequence(Delay(0.25), Delay(0.35), Delay(0.1)) }, 0.7},
+		{"sequence with callbacks", func() Tweener {
+			return NewSequence(Delay(0.2)).AppendCallback(func() {}).AppendDelay(0.3)
+		}, 0.5},
+		{"repeating sequence", func() Tweener { return NewSequence(Delay(0.25), Delay(0.25)).SetRepeat(3) }, 2},
+		{"yoyo sequence", func() Tweener {
+			return NewSequence(Delay(0.2).SetYoyo(true).SetRepeat(1), Delay(0.15)).SetRepeat(1)
+		}, 1.1},
+		{"parallel", func() Tweener { return NewParallel(Delay(0.4), Delay(0.65), Delay(0.1)) }, 0.65},
+		{"sequence of parallels", func() Tweener {
+			return NewSequence().Join(Delay(0.2), Delay(0.3)).Join(Delay(0.45))
+		}, 0.75},
+	}
+
+	for _, test := range tests {
+		for _, deltaTime := range []float32{1.0 / 60, 1.0 / 24, 0.07, 0.5} {
+			got := finishTime(t, test.build(), deltaTime)
+			if math.Abs(float64(got-test.duration)) > epsilon {
+				t.Errorf("%s at %g seconds a frame finished at %g, want %g", test.name, deltaTime, got, test.duration)
+			}
+		}
+	}
+}
+
+func TestSequenceRunsStepsWithinAFrame(t *testing.T) {
+	var progress []float32
+	first := New(0.1, func(t float32) {})
+	second := New(0.2, func(t float32) { progress = append(progress, t) })
+
+	sequence := NewSequence(first, second)
+	sequence.Update(0.15)
+
+	// The first step ends 0.05 into the frame, so the second gets the rest
+	if len(progress) != 1 || math.Abs(float64(progress[0]-0.25)) > epsilon {
+		t.Errorf("second step progress = %v, want [0.25]", progress)
+	}
+}
+
+func TestZeroLengthRepeatingSequenceStops(t *testing.T) {
+	calls := 0
+	sequence := NewSequence(Callback(func() { calls++ })).SetRepeat(RepeatForever)
+	for range 3 {
+		if done, _ := sequence.Update(0.1); done {
+			t.Fatal("sequence repeating forever finished")
+		}
+	}
+	if calls < 3 {
+		t.Errorf("callback ran %d times over 3 updates, want at least 3", calls)
+	}
+}
+
+func TestManagerRemoveDuringUpdate(t *testing.T) {
+	manager := NewManager()
+
+	var second, third *Tween
+	updated := make(map[*Tween]bool)
+	first := New(0.1, func(t float32) {}).SetOnComplete(func() { manager.Remove(second) })
+	second = New(1, func(t float32) { updated[second] = true })
+	third = New(1, func(t float32) { updated[third] = true })
+
+	manager.Add(first)
+	manager.Add(second)
+	manager.Add(third)
+	manager.Update(0.2)
+
+	if !updated[third] {
+		t.Error("tweener after a removed one was skipped")
+	}
+	if updated[second] {
+		t.Error("removed tweener was still updated")
+	}
+	if manager.Count() != 1 {
+		t.Errorf("manager has %d tweeners, want 1", manager.Count())
+	}
+}
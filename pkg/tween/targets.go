@@ -0,0 +1,112 @@
+package tween
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/entity"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+)
+
+// Value tweens read their start value when they first start (after any delay),
+// so tweens queued in a sequence continue from wherever the previous one ended.
+
+func Float32(target *float32, to float32, duration float32) *Tween {
+	return Float32Func(func() float32 { return *target }, func(v float32) { *target = v }, to, duration)
+}
+
+func Float32Func(get func() float32, set func(float32), to float32, duration float32) *Tween {
+	var from float32
+	return newTween(duration,
+		func() { from = get() },
+		func(t float32) { set(from + (to-from)*t) },
+	)
+}
+
+func Vec2(target *mgl32.Vec2, to mgl32.Vec2, duration float32) *Tween {
+	return Vec2Func(func() mgl32.Vec2 { return *target }, func(v mgl32.Vec2) { *target = v }, to, duration)
+}
+
+func Vec2Func(get func() mgl32.Vec2, set func(mgl32.Vec2), to mgl32.Vec2, duration float32) *Tween {
+	var from mgl32.Vec2
+	return newTween(duration,
+		func() { from = get() },
+		func(t float32) { set(from.Add(to.Sub(from).Mul(t))) },
+	)
+}
+
+func Vec3(target *mgl32.Vec3, to mgl32.Vec3, duration float32) *Tween {
+	return Vec3Func(func() mgl32.Vec3 { return *target }, func(v mgl32.Vec3) { *target = v }, to, duration)
+}
+
+func Vec3Func(get func() mgl32.Vec3, set func(mgl32.Vec3), to mgl32.Vec3, duration float32) *Tween {
+	var from mgl32.Vec3
+	return newTween(duration,
+		func() { from = get() },
+		func(t float32) { set(from.Add(to.Sub(from).Mul(t))) },
+	)
+}
+
+func Vec4(target *mgl32.Vec4, to mgl32.Vec4, duration float32) *Tween {
+	return Vec4Func(func() mgl32.Vec4 { return *target }, func(v mgl32.Vec4) { *target = v }, to, duration)
+}
+
+func Vec4Func(get func() mgl32.Vec4, set func(mgl32.Vec4), to mgl32.Vec4, duration float32) *Tween {
+	var from mgl32.Vec4
+	return newTween(duration,
+		func() { from = get() },
+		func(t float32) { set(from.Add(to.Sub(from).Mul(t))) },
+	)
+}
+
+func Position(transform *entity.Transform, to mgl32.Vec3, duration float32) *Tween {
+	return Vec3(&transform.Position, to, duration)
+}
+
+// Position2D moves a transform in X/Y and leaves its Z untouched.
+func Position2D(transform *entity.Transform, to mgl32.Vec2, duration float32) *Tween {
+	return Vec2Func(
+		func() mgl32.Vec2 { return transform.Position.Vec2() },
+		func(v mgl32.Vec2) { transform.SetPosition2D(v.X(), v.Y()) },
+		to, duration,
+	)
+}
+
+func Rotation(transform *entity.Transform, to float32, duration float32) *Tween {
+	return Float32(&transform.Rotation, to, duration)
+}
+
+// RotateBy rotates relative to the rotation the transform has when the tween starts.
+func RotateBy(transform *entity.Transform, angle float32, duration float32) *Tween {
+	var from float32
+	return newTween(duration,
+		func() { from = transform.Rotation },
+		func(t float32) { transform.Rotation = from + angle*t },
+	)
+}
+
+func Scale(transform *entity.Transform, to mgl32.Vec2, duration float32) *Tween {
+	return Vec2(&transform.Scale, to, duration)
+}
+
+func Color(spriteComp *entity.SpriteComponent, to mgl32.Vec4, duration float32) *Tween {
+	return Vec4Func(spriteComp.GetColor, spriteComp.SetColor, to, duration)
+}
+
+func Alpha(spriteComp *entity.SpriteComponent, to float32, duration float32) *Tween {
+	return Float32Func(
+		func() float32 { return spriteComp.GetColor().W() },
+		func(v float32) {
+			color := spriteComp.GetColor()
+			color[3] = v
+			spriteComp.SetColor(color)
+		},
+		to, duration,
+	)
+}
+
+func Zoom(cam *camera.Camera, to float32, duration float32) *Tween {
+	return Float32(&cam.Zoom, to, duration)
+}
+
+func CameraRotation(cam *camera.Camera, to float32, duration float32) *Tween {
+	return Float32(&cam.Rotation, to, duration)
+}
@@ -0,0 +1,235 @@
+package tween
+
+// Tweener is anything the Manager can drive: single tweens, sequences and
+// parallel groups. Update returns true once the tweener has finished, with
+// the part of deltaTime left over after it did, so whatever plays next can
+// start from there instead of on the following frame.
+type Tweener interface {
+	Update(deltaTime float32) (bool, float32)
+	IsFinished() bool
+	Restart()
+}
+
+const RepeatForever = -1
+
+type Tween struct {
+	duration float32
+	delay    float32
+	ease     EaseFunc
+
+	repeat      int
+	repeatsLeft int
+	yoyo        bool
+
+	elapsed        float32
+	delayRemaining float32
+	reversed       bool
+	captured       bool
+	started        bool
+	finished       bool
+	paused         bool
+
+	capture func()
+	apply   func(t float32)
+
+	onStart    func()
+	onRepeat   func()
+	onComplete func()
+}
+
+// New creates a tween that calls apply with eased progress every update. The
+// value-specific constructors in this package are built on top of it.
+func New(duration float32, apply func(t float32)) *Tween {
+	return newTween(duration, nil, apply)
+}
+
+func newTween(duration float32, capture func(), apply func(t float32)) *Tween {
+	return &Tween{
+		duration: duration,
+		ease:     Linear,
+		capture:  capture,
+		apply:    apply,
+	}
+}
+
+func (t *Tween) SetEase(ease EaseFunc) *Tween {
+	if ease == nil {
+		ease = Linear
+	}
+	t.ease = ease
+	return t
+}
+
+func (t *Tween) SetDelay(delay float32) *Tween {
+	t.delay = delay
+	t.delayRemaining = delay
+	return t
+}
+
+// SetRepeat sets how many extra times the tween plays after the first pass.
+// Use RepeatForever to loop until stopped.
+func (t *Tween) SetRepeat(count int) *Tween {
+	t.repeat = count
+	t.repeatsLeft = count
+	return t
+}
+
+// SetYoyo makes every other repetition play backwards.
+func (t *Tween) SetYoyo(yoyo bool) *Tween {
+	t.yoyo = yoyo
+	return t
+}
+
+func (t *Tween) SetOnStart(callback func()) *Tween {
+	t.onStart = callback
+	return t
+}
+
+func (t *Tween) SetOnRepeat(callback func()) *Tween {
+	t.onRepeat = callback
+	return t
+}
+
+func (t *Tween) SetOnComplete(callback func()) *Tween {
+	t.onComplete = callback
+	return t
+}
+
+func (t *Tween) Update(deltaTime float32) (bool, float32) {
+	if t.finished {
+		return true, deltaTime
+	}
+	if t.paused {
+		return false, 0
+	}
+
+	if t.delayRemaining > 0 {
+		t.delayRemaining -= deltaTime
+		if t.delayRemaining > 0 {
+			return false, 0
+		}
+		deltaTime = -t.delayRemaining
+		t.delayRemaining = 0
+	}
+
+	if !t.started {
+		t.start()
+	}
+
+	if t.duration <= 0 {
+		t.complete()
+		return true, deltaTime
+	}
+
+	t.elapsed += deltaTime
+
+	for t.elapsed >= t.duration {
+		if t.repeatsLeft == 0 {
+			leftover := t.elapsed - t.duration
+			t.complete()
+			return true, leftover
+		}
+
+		if t.repeatsLeft > 0 {
+			t.repeatsLeft--
+		}
+		t.elapsed -= t.duration
+		if t.yoyo {
+			t.reversed = !t.reversed
+		}
+
+		if t.onRepeat != nil {
+			t.onRepeat()
+		}
+	}
+
+	t.applyProgress(t.elapsed / t.duration)
+	return false, 0
+}
+
+func (t *Tween) start() {
+	t.started = true
+
+	// Start values are captured once so repeats and restarts replay the same range
+	if !t.captured {
+		t.captured = true
+		if t.capture != nil {
+			t.capture()
+		}
+	}
+
+	if t.onStart != nil {
+		t.onStart()
+	}
+}
+
+func (t *Tween) complete() {
+	t.finished = true
+	t.applyProgress(1)
+
+	if t.onComplete != nil {
+		t.onComplete()
+	}
+}
+
+func (t *Tween) applyProgress(progress float32) {
+	if t.reversed {
+		progress = 1 - progress
+	}
+	t.apply(t.ease(progress))
+}
+
+// Restart replays the tween from the beginning, including its delay.
+func (t *Tween) Restart() {
+	t.elapsed = 0
+	t.delayRemaining = t.delay
+	t.repeatsLeft = t.repeat
+	t.reversed = false
+	t.started = false
+	t.finished = false
+	t.paused = false
+}
+
+// Complete jumps to the end value and fires the completion callback.
+func (t *Tween) Complete() {
+	if t.finished {
+		return
+	}
+	if !t.started {
+		t.start()
+	}
+	t.reversed = t.yoyo && t.repeat > 0 && t.repeat%2 == 1
+	t.complete()
+}
+
+// Kill stops the tween where it is without firing callbacks.
+func (t *Tween) Kill() {
+	t.finished = true
+}
+
+func (t *Tween) Pause() {
+	t.paused = true
+}
+
+func (t *Tween) Resume() {
+	t.paused = false
+}
+
+func (t *Tween) IsFinished() bool {
+	return t.finished
+}
+
+func (t *Tween) IsPaused() bool {
+	return t.paused
+}
+
+func (t *Tween) GetDuration() float32 {
+	return t.duration
+}
+
+func (t *Tween) GetProgress() float32 {
+	if t.duration <= 0 || t.finished {
+		return 1.0
+	}
+	return t.elapsed / t.duration
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/font"
+)
+
+// Button calls OnClick when clicked or when the accept action is used while
+// it has focus.
+type Button struct {
+	Element
+
+	Text    string
+	Align   Align
+	OnClick func()
+}
+
+func NewButton(text string, onClick func()) *Button {
+	button := &Button{Text: text, Align: AlignCenter, OnClick: onClick}
+	button.Focusable = true
+	button.interactive = true
+	return button
+}
+
+// Click runs OnClick as if the button had been pressed.
+func (b *Button) Click() {
+	if b.OnClick != nil && b.IsEnabled() {
+		b.OnClick()
+	}
+}
+
+func (b *Button) Measure(theme *Theme) mgl32.Vec2 {
+	style := b.style(theme, StyleButton)
+	return font.Measure(b.Text, style.TextScale).Add(style.Padding.Size())
+}
+
+func (b *Button) Draw(r *Renderer, theme *Theme) {
+	style := b.style(theme, StyleButton)
+	state := b.GetState()
+	r.DrawSkin(style.GetSkin(state), b.rect)
+
+	content := b.rect.Inset(style.Padding)
+	if state == StatePressed {
+		// Nudge the text down so the button looks pushed in
+		content = content.Translate(mgl32.Vec2{0, 1})
+	}
+	r.TextIn(content, b.Text, style.TextScale, b.Align, style.TextColor(state))
+}
+
+func (b *Button) HandleEvent(event Event) bool {
+	switch e := event.(type) {
+	case PointerUpEvent:
+		if e.Inside {
+			b.Click()
+		}
+		return true
+	case SubmitEvent:
+		b.Click()
+		return true
+	}
+	return false
+}
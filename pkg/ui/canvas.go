@@ -0,0 +1,468 @@
+package ui
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+	"github.com/lunararch/helios/pkg/input"
+)
+
+// Actions the canvas reads for keyboard and gamepad navigation; see
+// MapDefaultActions.
+const (
+	ActionUp       input.Action = "ui_up"
+	ActionDown     input.Action = "ui_down"
+	ActionLeft     input.Action = "ui_left"
+	ActionRight    input.Action = "ui_right"
+	ActionAccept   input.Action = "ui_accept"
+	ActionCancel   input.Action = "ui_cancel"
+	ActionNext     input.Action = "ui_next"
+	ActionPrevious input.Action = "ui_previous"
+)
+
+// MapDefaultActions maps the navigation actions to the arrow keys, Enter,
+// Space and Escape, and to the gamepad's d-pad, left stick, A, B and
+// bumpers.
+func MapDefaultActions(mapping *input.InputMapping) {
+	mapping.MapKey(ActionUp, glfw.KeyUp)
+	mapping.MapKey(ActionDown, glfw.KeyDown)
+	mapping.MapKey(ActionLeft, glfw.KeyLeft)
+	mapping.MapKey(ActionRight, glfw.KeyRight)
+	mapping.MapKey(ActionAccept, glfw.KeyEnter)
+	mapping.MapKey(ActionAccept, glfw.KeySpace)
+	mapping.MapKey(ActionCancel, glfw.KeyEscape)
+
+	mapping.MapGamepadButton(ActionUp, glfw.ButtonDpadUp)
+	mapping.MapGamepadButton(ActionDown, glfw.ButtonDpadDown)
+	mapping.MapGamepadButton(ActionLeft, glfw.ButtonDpadLeft)
+	mapping.MapGamepadButton(ActionRight, glfw.ButtonDpadRight)
+	mapping.MapGamepadButton(ActionAccept, glfw.ButtonA)
+	mapping.MapGamepadButton(ActionCancel, glfw.ButtonB)
+	mapping.MapGamepadButton(ActionNext, glfw.ButtonRightBumper)
+	mapping.MapGamepadButton(ActionPrevious, glfw.ButtonLeftBumper)
+
+	mapping.MapGamepadAxis(ActionUp, glfw.AxisLeftY, false)
+	mapping.MapGamepadAxis(ActionDown, glfw.AxisLeftY, true)
+	mapping.MapGamepadAxis(ActionLeft, glfw.AxisLeftX, false)
+	mapping.MapGamepadAxis(ActionRight, glfw.AxisLeftX, true)
+}
+
+// Canvas is the root of a widget tree, laid out over a camera's screen
+// and fed by the input manager. It keeps track of which widget the mouse is
+// over, which one is pressed and which one has focus.
+type Canvas struct {
+	Root     *Panel
+	Theme    *Theme
+	OnCancel func() // Called when the cancel action isn't used by the focused widget
+
+	camera  *camera.Camera
+	focus   Widget
+	hover   Widget
+	pressed Widget
+	pointer mgl32.Vec2
+	inside  bool // The pointer is over the camera's screen
+}
+
+// NewCanvas creates an empty canvas covering a camera's screen. A nil theme
+// uses DefaultTheme.
+func NewCanvas(cam *camera.Camera, theme *Theme) *Canvas {
+	if theme == nil {
+		theme = DefaultTheme()
+	}
+
+	root := NewPanel()
+	root.Transparent = true
+	root.self = root
+
+	return &Canvas{
+		Root:   root,
+		Theme:  theme,
+		camera: cam,
+	}
+}
+
+func (c *Canvas) Add(widgets ...Widget) {
+	c.Root.Add(widgets...)
+}
+
+func (c *Canvas) Find(name string) Widget {
+	return c.Root.Find(name)
+}
+
+func (c *Canvas) GetCamera() *camera.Camera {
+	return c.camera
+}
+
+// Layout places every widget over the camera's current size. Update and
+// Render both call it, so changes to the tree show up the same frame.
+func (c *Canvas) Layout() {
+	layoutWidget(c.Root, Rect{Max: c.camera.Size}, c.Theme)
+}
+
+// Update lays the tree out, updates every widget and sends them this frame's
+// input: the mouse first, then typed text for an editing widget, or the
+// navigation actions otherwise.
+func (c *Canvas) Update(deltaTime float32, inputManager *input.InputManager, inputMapping *input.InputMapping) {
+	c.Layout()
+	c.releaseDetached()
+	updateWidget(c.Root, deltaTime)
+
+	c.updatePointer(inputManager)
+
+	if c.WantsKeyboard() {
+		if text := inputManager.GetTypedText(); text != "" {
+			c.focus.HandleEvent(TextEvent{Text: text})
+		}
+		for _, key := range inputManager.GetKeyPresses() {
+			if c.focus == nil {
+				break
+			}
+			c.focus.HandleEvent(KeyEvent{Key: key})
+		}
+		return
+	}
+
+	c.updateNavigation(inputManager, inputMapping)
+}
+
+func updateWidget(w Widget, deltaTime float32) {
+	if w.GetElement().Hidden {
+		return
+	}
+	w.Update(deltaTime)
+	for _, child := range w.GetElement().children {
+		updateWidget(child, deltaTime)
+	}
+}
+
+// releaseDetached forgets widgets that were removed from the tree, hidden or
+// disabled since the last update.
+func (c *Canvas) releaseDetached() {
+	if c.focus != nil && !c.isUsable(c.focus) {
+		c.SetFocus(nil)
+	}
+	if c.hover != nil && !c.isUsable(c.hover) {
+		c.setHover(nil)
+	}
+	if c.pressed != nil && !c.isUsable(c.pressed) {
+		c.pressed.GetElement().pressed = false
+		c.pressed = nil
+	}
+}
+
+// isUsable is true for visible, enabled widgets in this canvas's tree.
+func (c *Canvas) isUsable(w Widget) bool {
+	e := w.GetElement()
+	root := e
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root == &c.Root.Element && e.IsVisible() && e.IsEnabled()
+}
+
+func (c *Canvas) updatePointer(inputManager *input.InputManager) {
+	position, inside := c.camera.ScreenToCamera(inputManager.GetMousePosition())
+	moved := position != c.pointer
+	c.pointer, c.inside = position, inside
+
+	var target Widget
+	if inside {
+		target = c.interactiveAt(position)
+	}
+	c.setHover(target)
+
+	switch {
+	case inputManager.IsMouseButtonPressed(input.MouseButtonLeft):
+		if target == nil {
+			c.SetFocus(nil)
+			break
+		}
+		if target.GetElement().Focusable {
+			c.SetFocus(target)
+		}
+		c.pressed = target
+		target.GetElement().pressed = true
+		target.HandleEvent(PointerDownEvent{Position: position})
+
+	case c.pressed != nil && inputManager.IsMouseButtonHeld(input.MouseButtonLeft):
+		if moved {
+			c.pressed.HandleEvent(PointerMoveEvent{Position: position})
+		}
+
+	case c.pressed != nil:
+		pressed := c.pressed
+		c.pressed = nil
+		pressed.GetElement().pressed = false
+		pressed.HandleEvent(PointerUpEvent{Position: position, Inside: pressed == target})
+	}
+
+	if scroll := inputManager.GetScrollDelta(); inside && scroll != (mgl32.Vec2{}) {
+		for w := c.widgetAt(c.Root, position); w != nil; w = w.GetElement().GetParent() {
+			if w.GetElement().IsEnabled() && w.HandleEvent(ScrollEvent{Delta: scroll}) {
+				break
+			}
+		}
+	}
+}
+
+func (c *Canvas) setHover(w Widget) {
+	if c.hover == w {
+		return
+	}
+	if c.hover != nil {
+		c.hover.GetElement().hovered = false
+	}
+	c.hover = w
+	if w != nil {
+		w.GetElement().hovered = true
+	}
+}
+
+// IsPointerOver reports whether the mouse is over the UI, meaning any widget
+// but a transparent panel, so a HUD's scene can ignore clicks the UI takes.
+func (c *Canvas) IsPointerOver() bool {
+	if c.hover != nil || c.pressed != nil {
+		return true
+	}
+	if !c.inside {
+		return false
+	}
+
+	hit := c.widgetAt(c.Root, c.pointer)
+	panel, isPanel := hit.(*Panel)
+	return hit != nil && !(isPanel && panel.Transparent)
+}
+
+// widgetAt returns the deepest visible widget under a point, skipping parts
+// of children clipped away by their parents.
+func (c *Canvas) widgetAt(w Widget, point mgl32.Vec2) Widget {
+	e := w.GetElement()
+	if e.Hidden || !e.rect.Contains(point) {
+		return nil
+	}
+	if e.clips && !e.rect.Inset(e.Padding).Contains(point) {
+		return w
+	}
+
+	// Later children are drawn on top, so they are hit first
+	for i := len(e.children) - 1; i >= 0; i-- {
+		if hit := c.widgetAt(e.children[i], point); hit != nil {
+			return hit
+		}
+	}
+	return w
+}
+
+// interactiveAt returns the widget that takes the mouse at a point: the
+// deepest interactive one, so a button inside a scroll list wins over the
+// list.
+func (c *Canvas) interactiveAt(point mgl32.Vec2) Widget {
+	for w := c.widgetAt(c.Root, point); w != nil; w = w.GetElement().GetParent() {
+		e := w.GetElement()
+		if e.interactive {
+			if !e.IsEnabled() {
+				return nil
+			}
+			return w
+		}
+	}
+	return nil
+}
+
+// SetFocus moves keyboard and gamepad focus to a widget, or clears it with
+// nil, and scrolls any list holding the widget to show it.
+func (c *Canvas) SetFocus(w Widget) {
+	if c.focus == w {
+		return
+	}
+
+	if c.focus != nil {
+		previous := c.focus
+		c.focus = nil
+		previous.GetElement().focused = false
+		previous.HandleEvent(FocusEvent{Focused: false})
+	}
+
+	if w == nil || !w.GetElement().Focusable {
+		return
+	}
+	c.focus = w
+	w.GetElement().focused = true
+	w.HandleEvent(FocusEvent{Focused: true})
+	scrollIntoView(w)
+}
+
+func (c *Canvas) GetFocus() Widget {
+	return c.focus
+}
+
+// WantsKeyboard is true while the focused widget is editing text, when
+// scenes should leave letter keys alone.
+func (c *Canvas) WantsKeyboard() bool {
+	editor, ok := c.focus.(Editor)
+	return ok && editor.IsEditing()
+}
+
+// navActions pairs the directional actions with where they move focus, in
+// the order they are handled when several are pressed at once.
+var navActions = []struct {
+	action    input.Action
+	direction NavDirection
+}{
+	{ActionUp, NavUp},
+	{ActionDown, NavDown},
+	{ActionLeft, NavLeft},
+	{ActionRight, NavRight},
+}
+
+func (c *Canvas) updateNavigation(inputManager *input.InputManager, inputMapping *input.InputMapping) {
+	for _, nav := range navActions {
+		if inputMapping.IsActionPressed(nav.action, inputManager) {
+			c.Navigate(nav.direction)
+		}
+	}
+
+	if inputMapping.IsActionPressed(ActionNext, inputManager) {
+		c.FocusNext(1)
+	}
+	if inputMapping.IsActionPressed(ActionPrevious, inputManager) {
+		c.FocusNext(-1)
+	}
+
+	if inputMapping.IsActionPressed(ActionAccept, inputManager) && c.focus != nil {
+		c.focus.HandleEvent(SubmitEvent{})
+	}
+
+	if inputMapping.IsActionPressed(ActionCancel, inputManager) {
+		if c.focus == nil || !c.focus.HandleEvent(CancelEvent{}) {
+			if c.OnCancel != nil {
+				c.OnCancel()
+			}
+		}
+	}
+}
+
+// focusables lists every widget that can take focus, in tree order.
+func (c *Canvas) focusables() []Widget {
+	result := make([]Widget, 0)
+	var walk func(w Widget)
+	walk = func(w Widget) {
+		e := w.GetElement()
+		if e.Hidden || e.Disabled {
+			return
+		}
+		if e.Focusable {
+			result = append(result, w)
+		}
+		for _, child := range e.children {
+			walk(child)
+		}
+	}
+	walk(c.Root)
+	return result
+}
+
+// Navigate moves focus to the nearest widget in a direction, after giving
+// the focused widget the chance to use the direction itself. Without focus,
+// the first focusable widget is focused.
+func (c *Canvas) Navigate(direction NavDirection) {
+	if c.focus != nil && c.focus.HandleEvent(NavigateEvent{Direction: direction}) {
+		return
+	}
+
+	candidates := c.focusables()
+	if c.focus == nil {
+		if len(candidates) > 0 {
+			c.SetFocus(candidates[0])
+		}
+		return
+	}
+
+	from := c.focus.GetElement().rect.Center()
+	axis := direction.vector()
+	side := mgl32.Vec2{-axis.Y(), axis.X()}
+
+	var best Widget
+	var bestScore float32
+	for _, candidate := range candidates {
+		if candidate == c.focus {
+			continue
+		}
+
+		offset := candidate.GetElement().rect.Center().Sub(from)
+		along := offset.Dot(axis)
+		if along <= 0 {
+			continue
+		}
+
+		// Prefer widgets straight ahead over nearer ones off to the side
+		across := offset.Dot(side)
+		if across < 0 {
+			across = -across
+		}
+		score := along + across*2
+		if best == nil || score < bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+
+	if best != nil {
+		c.SetFocus(best)
+	}
+}
+
+// FocusNext moves focus forwards or backwards through the focusable widgets
+// in tree order, wrapping around.
+func (c *Canvas) FocusNext(step int) {
+	candidates := c.focusables()
+	if len(candidates) == 0 {
+		return
+	}
+
+	index := -1
+	for i, candidate := range candidates {
+		if candidate == c.focus {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		if step < 0 {
+			index = 0
+		} else {
+			index = len(candidates) - 1
+		}
+	}
+
+	next := ((index+step)%len(candidates) + len(candidates)) % len(candidates)
+	c.SetFocus(candidates[next])
+}
+
+// Render lays the tree out and draws it through a renderer, in tree order,
+// over the camera's screen.
+func (c *Canvas) Render(r *Renderer) {
+	c.Layout()
+
+	r.Begin(c.camera)
+	drawWidget(c.Root, r, c.Theme)
+	r.End()
+}
+
+func drawWidget(w Widget, r *Renderer, theme *Theme) {
+	e := w.GetElement()
+	if e.Hidden {
+		return
+	}
+
+	w.Draw(r, theme)
+
+	if e.clips {
+		r.PushClip(e.rect.Inset(e.Padding))
+	}
+	for _, child := range e.children {
+		drawWidget(child, r, theme)
+	}
+	if e.clips {
+		r.PopClip()
+	}
+}
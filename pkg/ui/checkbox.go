@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/font"
+	"github.com/lunararch/helios/pkg/graphics/sprite"
+)
+
+const checkboxGap = 8 // Pixels between the box and the text
+
+// Checkbox is a box with a label that toggles when clicked or accepted.
+type Checkbox struct {
+	Element
+
+	Text     string
+	OnChange func(checked bool)
+
+	checked bool
+}
+
+func NewCheckbox(text string, checked bool, onChange func(checked bool)) *Checkbox {
+	checkbox := &Checkbox{Text: text, OnChange: onChange, checked: checked}
+	checkbox.Focusable = true
+	checkbox.interactive = true
+	return checkbox
+}
+
+// SetChecked changes the box, calling OnChange if it changed.
+func (c *Checkbox) SetChecked(checked bool) {
+	if checked == c.checked {
+		return
+	}
+	c.checked = checked
+	if c.OnChange != nil {
+		c.OnChange(checked)
+	}
+}
+
+func (c *Checkbox) IsChecked() bool {
+	return c.checked
+}
+
+func (c *Checkbox) Toggle() {
+	c.SetChecked(!c.checked)
+}
+
+func (c *Checkbox) boxSize(style *Style) float32 {
+	return font.GlyphHeight*style.TextScale + 6
+}
+
+func (c *Checkbox) Measure(theme *Theme) mgl32.Vec2 {
+	style := c.style(theme, StyleCheckbox)
+	box := c.boxSize(style)
+	text := font.Measure(c.Text, style.TextScale)
+	if c.Text == "" {
+		return mgl32.Vec2{box, box}
+	}
+	return mgl32.Vec2{box + checkboxGap + text.X(), max(box, text.Y())}
+}
+
+func (c *Checkbox) Draw(r *Renderer, theme *Theme) {
+	style := c.style(theme, StyleCheckbox)
+	state := c.GetState()
+
+	size := c.boxSize(style)
+	boxMin := mgl32.Vec2{c.rect.Min.X(), c.rect.Center().Y() - size/2}
+	box := Rect{Min: boxMin, Max: boxMin.Add(mgl32.Vec2{size, size})}
+	r.DrawSkin(style.GetSkin(state), box)
+
+	if c.checked {
+		accent := style.Accent
+		if state == StateDisabled {
+			accent[3] *= 0.4
+		}
+		r.Shapes().Polyline([]mgl32.Vec2{
+			box.Min.Add(mgl32.Vec2{size * 0.25, size * 0.5}),
+			box.Min.Add(mgl32.Vec2{size * 0.42, size * 0.7}),
+			box.Min.Add(mgl32.Vec2{size * 0.75, size * 0.3}),
+		}, max(size/8, 2), sprite.SolidPaint(accent))
+	}
+
+	text := Rect{Min: mgl32.Vec2{box.Max.X() + checkboxGap, c.rect.Min.Y()}, Max: c.rect.Max}
+	r.TextIn(text, c.Text, style.TextScale, AlignStart, style.TextColor(state))
+}
+
+func (c *Checkbox) HandleEvent(event Event) bool {
+	switch e := event.(type) {
+	case PointerUpEvent:
+		if e.Inside {
+			c.Toggle()
+		}
+		return true
+	case SubmitEvent:
+		c.Toggle()
+		return true
+	}
+	return false
+}
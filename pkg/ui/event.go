@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type EventType int
+
+const (
+	EventTypePointerDown EventType = iota
+	EventTypePointerMove
+	EventTypePointerUp
+	EventTypeScroll
+	EventTypeNavigate
+	EventTypeSubmit
+	EventTypeCancel
+	EventTypeText
+	EventTypeKey
+	EventTypeFocus
+)
+
+// Event is something the canvas tells a widget about, from input or focus
+// changes.
+type Event interface {
+	GetType() EventType
+}
+
+// PointerDownEvent is sent to the widget under the mouse when the left button
+// is pressed. That widget then gets every move and the release.
+type PointerDownEvent struct {
+	Position mgl32.Vec2
+}
+
+func (e PointerDownEvent) GetType() EventType { return EventTypePointerDown }
+
+// PointerMoveEvent is sent while the button is held, for dragging.
+type PointerMoveEvent struct {
+	Position mgl32.Vec2
+}
+
+func (e PointerMoveEvent) GetType() EventType { return EventTypePointerMove }
+
+type PointerUpEvent struct {
+	Position mgl32.Vec2
+	Inside   bool // Released over the widget that was pressed; a click
+}
+
+func (e PointerUpEvent) GetType() EventType { return EventTypePointerUp }
+
+// ScrollEvent is sent to the widget under the mouse and then its parents,
+// until one uses it.
+type ScrollEvent struct {
+	Delta mgl32.Vec2
+}
+
+func (e ScrollEvent) GetType() EventType { return EventTypeScroll }
+
+// NavigateEvent is sent to the focused widget before focus moves; sliders
+// use left and right to change their value.
+type NavigateEvent struct {
+	Direction NavDirection
+}
+
+func (e NavigateEvent) GetType() EventType { return EventTypeNavigate }
+
+// SubmitEvent is the accept action on the focused widget.
+type SubmitEvent struct{}
+
+func (e SubmitEvent) GetType() EventType { return EventTypeSubmit }
+
+// CancelEvent is the cancel action on the focused widget. If no widget uses
+// it, the canvas calls its OnCancel.
+type CancelEvent struct{}
+
+func (e CancelEvent) GetType() EventType { return EventTypeCancel }
+
+// TextEvent and KeyEvent go to an editing widget instead of navigation.
+type TextEvent struct {
+	Text string
+}
+
+func (e TextEvent) GetType() EventType { return EventTypeText }
+
+type KeyEvent struct {
+	Key glfw.Key
+}
+
+func (e KeyEvent) GetType() EventType { return EventTypeKey }
+
+type FocusEvent struct {
+	Focused bool
+}
+
+func (e FocusEvent) GetType() EventType { return EventTypeFocus }
+
+type NavDirection int
+
+const (
+	NavUp NavDirection = iota
+	NavDown
+	NavLeft
+	NavRight
+)
+
+func (d NavDirection) vector() mgl32.Vec2 {
+	switch d {
+	case NavUp:
+		return mgl32.Vec2{0, -1}
+	case NavDown:
+		return mgl32.Vec2{0, 1}
+	case NavLeft:
+		return mgl32.Vec2{-1, 0}
+	default:
+		return mgl32.Vec2{1, 0}
+	}
+}
+
+// Editor is a widget that takes typed text and keys while it is editing,
+// instead of the canvas using them for navigation.
+type Editor interface {
+	IsEditing() bool
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/texture"
+)
+
+// Image shows a texture region, stretched over the widget or fitted inside
+// it.
+type Image struct {
+	Element
+
+	Region     *texture.TextureRegion
+	Color      mgl32.Vec4
+	KeepAspect bool // Fit inside the rect without stretching, centered
+}
+
+func NewImage(region *texture.TextureRegion) *Image {
+	return &Image{Region: region, Color: mgl32.Vec4{1, 1, 1, 1}, KeepAspect: true}
+}
+
+// NewTextureImage shows a whole texture.
+func NewTextureImage(tex *texture.Texture) *Image {
+	return NewImage(texture.NewTextureRegion(tex, 0, 0, 1, 1))
+}
+
+// Measure is the region's size in texture pixels.
+func (i *Image) Measure(theme *Theme) mgl32.Vec2 {
+	if i.Region == nil || i.Region.Texture == nil {
+		return mgl32.Vec2{}
+	}
+	return mgl32.Vec2{float32(i.Region.GetWidth()), float32(i.Region.GetHeight())}
+}
+
+func (i *Image) Draw(r *Renderer, theme *Theme) {
+	if i.Region == nil || i.Region.Texture == nil {
+		return
+	}
+
+	rect := i.rect
+	natural := i.Measure(theme)
+	if i.KeepAspect && natural.X() > 0 && natural.Y() > 0 {
+		scale := min(rect.Width()/natural.X(), rect.Height()/natural.Y())
+		size := natural.Mul(scale)
+		rect = Rect{Min: rect.Center().Sub(size.Mul(0.5))}
+		rect.Max = rect.Min.Add(size)
+	}
+
+	color := i.Color
+	if !i.IsEnabled() {
+		color[3] *= 0.4
+	}
+	r.DrawRegion(i.Region, rect, color)
+}
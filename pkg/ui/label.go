@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/font"
+)
+
+// Label shows a line or more of text in the built-in pixel font.
+type Label struct {
+	Element
+
+	Text  string
+	Align Align       // Horizontal placement in the label's rect
+	Color *mgl32.Vec4 // Overrides the style's text color
+}
+
+func NewLabel(text string) *Label {
+	return &Label{Text: text}
+}
+
+// NewTitle returns a label in the large title style, centered.
+func NewTitle(text string) *Label {
+	label := NewLabel(text)
+	label.Style = StyleTitle
+	label.Align = AlignCenter
+	return label
+}
+
+func (l *Label) SetText(text string) {
+	l.Text = text
+}
+
+func (l *Label) GetText() string {
+	return l.Text
+}
+
+func (l *Label) Measure(theme *Theme) mgl32.Vec2 {
+	style := l.style(theme, StyleLabel)
+	return font.Measure(l.Text, style.TextScale).Add(style.Padding.Size())
+}
+
+func (l *Label) Draw(r *Renderer, theme *Theme) {
+	style := l.style(theme, StyleLabel)
+	color := style.TextColor(l.GetState())
+	if l.Color != nil {
+		color = *l.Color
+	}
+	r.DrawSkin(style.GetSkin(l.GetState()), l.rect)
+	r.TextIn(l.rect.Inset(style.Padding), l.Text, style.TextScale, l.Align, color)
+}
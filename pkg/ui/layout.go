@@ -0,0 +1,295 @@
+package ui
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Rect is a box in canvas pixels, with the origin at the top left.
+type Rect struct {
+	Min, Max mgl32.Vec2
+}
+
+func NewRect(x, y, width, height float32) Rect {
+	return Rect{Min: mgl32.Vec2{x, y}, Max: mgl32.Vec2{x + width, y + height}}
+}
+
+func (r Rect) Size() mgl32.Vec2 {
+	return r.Max.Sub(r.Min)
+}
+
+func (r Rect) Width() float32 {
+	return r.Max.X() - r.Min.X()
+}
+
+func (r Rect) Height() float32 {
+	return r.Max.Y() - r.Min.Y()
+}
+
+func (r Rect) Center() mgl32.Vec2 {
+	return r.Min.Add(r.Max).Mul(0.5)
+}
+
+func (r Rect) Contains(point mgl32.Vec2) bool {
+	return point.X() >= r.Min.X() && point.X() < r.Max.X() &&
+		point.Y() >= r.Min.Y() && point.Y() < r.Max.Y()
+}
+
+// Inset shrinks the rect by the insets, never past zero size.
+func (r Rect) Inset(insets Insets) Rect {
+	result := Rect{
+		Min: r.Min.Add(mgl32.Vec2{insets.Left, insets.Top}),
+		Max: r.Max.Sub(mgl32.Vec2{insets.Right, insets.Bottom}),
+	}
+	result.Max = mgl32.Vec2{max(result.Max.X(), result.Min.X()), max(result.Max.Y(), result.Min.Y())}
+	return result
+}
+
+func (r Rect) Translate(offset mgl32.Vec2) Rect {
+	return Rect{Min: r.Min.Add(offset), Max: r.Max.Add(offset)}
+}
+
+// Intersect returns the overlap of two rects, empty if they don't touch.
+func (r Rect) Intersect(other Rect) Rect {
+	result := Rect{
+		Min: mgl32.Vec2{max(r.Min.X(), other.Min.X()), max(r.Min.Y(), other.Min.Y())},
+		Max: mgl32.Vec2{min(r.Max.X(), other.Max.X()), min(r.Max.Y(), other.Max.Y())},
+	}
+	result.Max = mgl32.Vec2{max(result.Max.X(), result.Min.X()), max(result.Max.Y(), result.Min.Y())}
+	return result
+}
+
+// Insets are distances in from each edge of a rect.
+type Insets struct {
+	Left, Top, Right, Bottom float32
+}
+
+// Uniform returns the same inset on every edge.
+func Uniform(inset float32) Insets {
+	return Insets{inset, inset, inset, inset}
+}
+
+func (i Insets) Size() mgl32.Vec2 {
+	return mgl32.Vec2{i.Left + i.Right, i.Top + i.Bottom}
+}
+
+// Anchor places a widget inside its parent when the parent has no Flex
+// layout. Min and Max pick two points of the parent as fractions of its
+// content rect. On an axis where they are equal the widget keeps its
+// preferred size and puts its Pivot on that point; where they differ it
+// stretches between them.
+type Anchor struct {
+	Min, Max mgl32.Vec2 // Fractions of the parent's content rect, (0, 0) top-left
+	Pivot    mgl32.Vec2 // Fraction of the widget placed on the anchor point
+	Offset   mgl32.Vec2 // Pixels to move the widget by
+	Margin   Insets     // Pixels kept clear of the parent's edges when stretching
+}
+
+// AnchorAt pins a widget at its preferred size to a point of its parent, by
+// the same point of itself: AnchorAt(1, 0) puts it in the top-right corner
+// and AnchorAt(0.5, 0.5) centers it.
+func AnchorAt(x, y float32) Anchor {
+	return Anchor{Min: mgl32.Vec2{x, y}, Max: mgl32.Vec2{x, y}, Pivot: mgl32.Vec2{x, y}}
+}
+
+// AnchorFill stretches a widget over its parent, less the margin.
+func AnchorFill(margin Insets) Anchor {
+	return Anchor{Min: mgl32.Vec2{0, 0}, Max: mgl32.Vec2{1, 1}, Margin: margin}
+}
+
+// WithOffset returns the anchor moved by some pixels.
+func (a Anchor) WithOffset(x, y float32) Anchor {
+	a.Offset = mgl32.Vec2{x, y}
+	return a
+}
+
+func (a Anchor) place(parent Rect, size mgl32.Vec2) Rect {
+	var result Rect
+	parentSize := parent.Size()
+	near := [2]float32{a.Margin.Left, a.Margin.Top}
+	far := [2]float32{a.Margin.Right, a.Margin.Bottom}
+
+	for axis := 0; axis < 2; axis++ {
+		start := parent.Min[axis] + parentSize[axis]*a.Min[axis]
+		end := parent.Min[axis] + parentSize[axis]*a.Max[axis]
+		if a.Min[axis] == a.Max[axis] {
+			start -= size[axis] * a.Pivot[axis]
+			end = start + size[axis]
+		} else {
+			start += near[axis]
+			end = max(end-far[axis], start)
+		}
+		result.Min[axis] = start + a.Offset[axis]
+		result.Max[axis] = end + a.Offset[axis]
+	}
+	return result
+}
+
+// Direction is the main axis of a Flex layout.
+type Direction int
+
+const (
+	Column Direction = iota // Top to bottom
+	Row                     // Left to right
+)
+
+// Align places children along an axis of a Flex layout.
+type Align int
+
+const (
+	AlignStart Align = iota
+	AlignCenter
+	AlignEnd
+	AlignStretch // Fill the cross axis; on the main axis, spread the space between children
+)
+
+// Flex lays children out in a row or column at their preferred sizes,
+// giving any space left over to children with Grow set. Children that don't
+// fit overflow the end, which scroll lists rely on.
+type Flex struct {
+	Direction Direction
+	Gap       float32 // Pixels between children
+	Align     Align   // Cross axis placement
+	Justify   Align   // Main axis placement when no child grows
+}
+
+func (f *Flex) axes() (main, cross int) {
+	if f.Direction == Row {
+		return 0, 1
+	}
+	return 1, 0
+}
+
+// measure returns the size of the children laid out end to end.
+func (f *Flex) measure(children []Widget, theme *Theme) mgl32.Vec2 {
+	main, cross := f.axes()
+
+	var size mgl32.Vec2
+	count := 0
+	for _, child := range children {
+		if child.GetElement().Hidden {
+			continue
+		}
+		preferred := preferredSize(child, theme)
+		size[main] += preferred[main]
+		size[cross] = max(size[cross], preferred[cross])
+		count++
+	}
+	if count > 1 {
+		size[main] += f.Gap * float32(count-1)
+	}
+	return size
+}
+
+// layout places the children in the content rect and returns the size they
+// take up, which may be larger.
+func (f *Flex) layout(children []Widget, content Rect, theme *Theme) mgl32.Vec2 {
+	main, cross := f.axes()
+	contentSize := content.Size()
+
+	visible := make([]Widget, 0, len(children))
+	sizes := make([]mgl32.Vec2, 0, len(children))
+	var total, grow float32
+	for _, child := range children {
+		if child.GetElement().Hidden {
+			continue
+		}
+		size := preferredSize(child, theme)
+		visible = append(visible, child)
+		sizes = append(sizes, size)
+		total += size[main]
+		grow += child.GetElement().Grow
+	}
+	if len(visible) == 0 {
+		return mgl32.Vec2{}
+	}
+	total += f.Gap * float32(len(visible)-1)
+
+	leftover := contentSize[main] - total
+	position, gap := float32(0), f.Gap
+	if leftover > 0 && grow > 0 {
+		for i, child := range visible {
+			sizes[i][main] += leftover * child.GetElement().Grow / grow
+		}
+		total += leftover
+	} else if leftover > 0 {
+		switch f.Justify {
+		case AlignCenter:
+			position = leftover / 2
+		case AlignEnd:
+			position = leftover
+		case AlignStretch:
+			if len(visible) > 1 {
+				gap += leftover / float32(len(visible)-1)
+			}
+		}
+	}
+
+	var extent float32
+	for i, child := range visible {
+		size := sizes[i]
+		var offset float32
+		switch f.Align {
+		case AlignCenter:
+			offset = (contentSize[cross] - size[cross]) / 2
+		case AlignEnd:
+			offset = contentSize[cross] - size[cross]
+		case AlignStretch:
+			size[cross] = contentSize[cross]
+		}
+		extent = max(extent, size[cross])
+
+		var rect Rect
+		rect.Min[main] = content.Min[main] + position
+		rect.Min[cross] = content.Min[cross] + offset
+		rect.Max = rect.Min.Add(size)
+		layoutWidget(child, rect, theme)
+
+		position += size[main] + gap
+	}
+
+	var used mgl32.Vec2
+	used[main] = total
+	used[cross] = extent
+	return used
+}
+
+// preferredSize is a widget's Size, with zero axes filled in by measuring it.
+func preferredSize(w Widget, theme *Theme) mgl32.Vec2 {
+	e := w.GetElement()
+	size := e.Size
+	if size.X() > 0 && size.Y() > 0 {
+		return size
+	}
+
+	measured := w.Measure(theme)
+	if size.X() <= 0 {
+		size[0] = measured.X()
+	}
+	if size.Y() <= 0 {
+		size[1] = measured.Y()
+	}
+	return size
+}
+
+// layoutWidget gives a widget its rect and lays out its children inside it.
+func layoutWidget(w Widget, rect Rect, theme *Theme) {
+	e := w.GetElement()
+	e.rect = rect
+
+	content := rect.Inset(e.Padding).Translate(e.scroll.Mul(-1))
+	if e.Flex != nil {
+		e.contentSize = e.Flex.layout(e.children, content, theme)
+		return
+	}
+
+	e.contentSize = mgl32.Vec2{}
+	for _, child := range e.children {
+		childElement := child.GetElement()
+		if childElement.Hidden {
+			continue
+		}
+		childRect := childElement.Anchor.place(content, preferredSize(child, theme))
+		layoutWidget(child, childRect, theme)
+
+		extent := childRect.Max.Sub(content.Min)
+		e.contentSize = mgl32.Vec2{max(e.contentSize.X(), extent.X()), max(e.contentSize.Y(), extent.Y())}
+	}
+}
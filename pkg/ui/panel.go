@@ -0,0 +1,40 @@
+package ui
+
+// Panel groups widgets over a skinned background. Set Flex to lay its
+// children out in a row or column, or anchor them individually.
+type Panel struct {
+	Element
+
+	Transparent bool // Skip the background, for pure layout containers
+}
+
+func NewPanel(children ...Widget) *Panel {
+	panel := &Panel{}
+	panel.Add(children...)
+	return panel
+}
+
+// NewColumn returns a transparent panel laying its children out top to
+// bottom, stretched to its width.
+func NewColumn(gap float32, children ...Widget) *Panel {
+	panel := NewPanel(children...)
+	panel.Transparent = true
+	panel.Flex = &Flex{Direction: Column, Gap: gap, Align: AlignStretch}
+	return panel
+}
+
+// NewRow returns a transparent panel laying its children out left to right,
+// centered vertically.
+func NewRow(gap float32, children ...Widget) *Panel {
+	panel := NewPanel(children...)
+	panel.Transparent = true
+	panel.Flex = &Flex{Direction: Row, Gap: gap, Align: AlignCenter}
+	return panel
+}
+
+func (p *Panel) Draw(r *Renderer, theme *Theme) {
+	if p.Transparent {
+		return
+	}
+	r.DrawSkin(p.style(theme, StylePanel).GetSkin(p.GetState()), p.rect)
+}
@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/all-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/camera"
+	"github.com/lunararch/helios/pkg/graphics/font"
+	"github.com/lunararch/helios/pkg/graphics/sprite"
+	"github.com/lunararch/helios/pkg/graphics/texture"
+)
+
+type batchKind int
+
+const (
+	batchNone batchKind = iota
+	batchSprites
+	batchShapes
+)
+
+// Renderer draws widgets in camera pixels through a sprite batch, for
+// images and nine-slice skins, and a shape batch, for everything else.
+// Switching between the two flushes the other, so drawing order is kept;
+// skins of one kind in a row share a draw call.
+type Renderer struct {
+	sprites *sprite.SpriteBatch
+	shapes  *sprite.ShapeBatch
+
+	current  batchKind
+	sortMode sprite.SortMode // The sprite batch's mode, restored by End
+	size     mgl32.Vec2      // Canvas size in camera pixels
+	output   [4]int32        // GL viewport the canvas covers, for clipping
+	clips    []Rect
+}
+
+// NewRenderer draws with the given batches, which can be shared with the
+// scene as long as the scene isn't between their Begin and End.
+func NewRenderer(sprites *sprite.SpriteBatch, shapes *sprite.ShapeBatch) *Renderer {
+	return &Renderer{
+		sprites: sprites,
+		shapes:  shapes,
+		clips:   make([]Rect, 0),
+	}
+}
+
+// Begin starts drawing in a camera's pixels, ignoring its position and zoom,
+// into the current GL viewport.
+func (r *Renderer) Begin(cam *camera.Camera) {
+	r.size = cam.Size
+	gl.GetIntegerv(gl.VIEWPORT, &r.output[0])
+
+	projection := cam.GetProjectionMatrix()
+	r.sprites.SetCamera(projection, mgl32.Ident4())
+	r.shapes.SetCamera(projection, mgl32.Ident4())
+
+	r.sortMode = r.sprites.GetSortMode()
+	r.sprites.SetSortMode(sprite.SortDeferred)
+	r.sprites.Begin()
+	r.shapes.Begin()
+	r.current = batchNone
+}
+
+func (r *Renderer) End() {
+	r.sprites.End()
+	r.shapes.End()
+	r.sprites.SetSortMode(r.sortMode)
+
+	if len(r.clips) > 0 {
+		r.clips = r.clips[:0]
+		gl.Disable(gl.SCISSOR_TEST)
+	}
+}
+
+func (r *Renderer) use(kind batchKind) {
+	if r.current == kind {
+		return
+	}
+	r.flush()
+	r.current = kind
+}
+
+func (r *Renderer) flush() {
+	switch r.current {
+	case batchSprites:
+		r.sprites.Flush()
+	case batchShapes:
+		r.shapes.Flush()
+	}
+}
+
+// PushClip cuts off drawing outside a rect, within any clip already pushed.
+func (r *Renderer) PushClip(rect Rect) {
+	if len(r.clips) > 0 {
+		rect = rect.Intersect(r.clips[len(r.clips)-1])
+	}
+	r.flush()
+	r.clips = append(r.clips, rect)
+	r.applyClip()
+}
+
+func (r *Renderer) PopClip() {
+	if len(r.clips) == 0 {
+		return
+	}
+	r.flush()
+	r.clips = r.clips[:len(r.clips)-1]
+	r.applyClip()
+}
+
+// applyClip scissors to the top clip, converted from canvas pixels to the
+// GL viewport, which counts rows from the bottom.
+func (r *Renderer) applyClip() {
+	if len(r.clips) == 0 {
+		gl.Disable(gl.SCISSOR_TEST)
+		return
+	}
+	if r.size.X() <= 0 || r.size.Y() <= 0 {
+		return
+	}
+
+	clip := r.clips[len(r.clips)-1]
+	scaleX := float64(r.output[2]) / float64(r.size.X())
+	scaleY := float64(r.output[3]) / float64(r.size.Y())
+
+	left := math.Round(float64(r.output[0]) + float64(clip.Min.X())*scaleX)
+	right := math.Round(float64(r.output[0]) + float64(clip.Max.X())*scaleX)
+	bottom := math.Round(float64(r.output[1]) + float64(r.size.Y()-clip.Max.Y())*scaleY)
+	top := math.Round(float64(r.output[1]) + float64(r.size.Y()-clip.Min.Y())*scaleY)
+
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(int32(left), int32(bottom), int32(max(right-left, 0)), int32(max(top-bottom, 0)))
+}
+
+// DrawSkin draws a widget background over a rect.
+func (r *Renderer) DrawSkin(skin Skin, rect Rect) {
+	if skin.IsEmpty() {
+		return
+	}
+
+	if skin.Region != nil {
+		r.use(batchSprites)
+		r.sprites.DrawNineSlice(skin.Region, mgl32.Vec3{rect.Min.X(), rect.Min.Y(), 0}, rect.Size(), skin.Color)
+		return
+	}
+
+	r.use(batchShapes)
+	if skin.Color.W() > 0 {
+		r.shapes.FillRoundedRect(rect.Min, rect.Max, skin.Radius, sprite.SolidPaint(skin.Color))
+	}
+	if skin.BorderWidth > 0 && skin.Border.W() > 0 {
+		// Keep the outline inside the rect, like an image's border
+		half := skin.BorderWidth / 2
+		inner := rect.Inset(Uniform(half))
+		r.shapes.StrokeRoundedRect(inner.Min, inner.Max, max(skin.Radius-half, 0), skin.BorderWidth, sprite.SolidPaint(skin.Border))
+	}
+}
+
+// DrawRegion stretches a texture region over a rect.
+func (r *Renderer) DrawRegion(region *texture.TextureRegion, rect Rect, color mgl32.Vec4) {
+	r.use(batchSprites)
+	image := sprite.NewSpriteWithRegion(region, mgl32.Vec3{rect.Min.X(), rect.Min.Y(), 0}, rect.Size())
+	image.Color = color
+	r.sprites.Draw(image)
+}
+
+// Shapes returns the shape batch for custom drawing; shapes drawn through it
+// keep their place in the drawing order.
+func (r *Renderer) Shapes() *sprite.ShapeBatch {
+	r.use(batchShapes)
+	return r.shapes
+}
+
+func (r *Renderer) FillRect(rect Rect, radius float32, color mgl32.Vec4) {
+	r.use(batchShapes)
+	r.shapes.FillRoundedRect(rect.Min, rect.Max, radius, sprite.SolidPaint(color))
+}
+
+// Text draws a text with its top-left corner at position in the built-in
+// pixel font, with hard edges so the pixels stay crisp.
+func (r *Renderer) Text(position mgl32.Vec2, text string, scale float32, color mgl32.Vec4) {
+	if text == "" || color.W() <= 0 {
+		return
+	}
+
+	r.use(batchShapes)
+	feather := r.shapes.GetFeather()
+	r.shapes.SetFeather(0)
+
+	// Whole pixels, so every font pixel covers the same screen pixels
+	position = mgl32.Vec2{float32(math.Round(float64(position.X()))), float32(math.Round(float64(position.Y())))}
+	paint := sprite.SolidPaint(color)
+	for _, run := range font.Layout(position, text, scale) {
+		r.shapes.FillRect(run.Min, run.Max, paint)
+	}
+
+	r.shapes.SetFeather(feather)
+}
+
+// TextIn draws a text inside a rect, aligned horizontally and centered
+// vertically.
+func (r *Renderer) TextIn(rect Rect, text string, scale float32, align Align, color mgl32.Vec4) {
+	size := font.Measure(text, scale)
+	x := rect.Min.X()
+	switch align {
+	case AlignCenter:
+		x += (rect.Width() - size.X()) / 2
+	case AlignEnd:
+		x += rect.Width() - size.X()
+	}
+	r.Text(mgl32.Vec2{x, rect.Min.Y() + (rect.Height()-size.Y())/2}, text, scale, color)
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const scrollBarWidth = 6
+
+// ScrollList stacks its children in a column and scrolls them with the mouse
+// wheel or by dragging its scroll bar. Focusing a child, as navigation does,
+// scrolls it into view.
+type ScrollList struct {
+	Element
+
+	ScrollSpeed float32 // Pixels per wheel notch
+
+	dragging   bool
+	dragOffset float32 // Pointer position within the thumb when the drag began
+}
+
+func NewScrollList(children ...Widget) *ScrollList {
+	list := &ScrollList{ScrollSpeed: 30}
+	list.Flex = &Flex{Direction: Column, Gap: 2, Align: AlignStretch}
+	list.Padding = Insets{4, 4, 4 + scrollBarWidth + 4, 4}
+	list.clips = true
+	list.interactive = true
+	list.Add(children...)
+	return list
+}
+
+// AddItem adds a button styled as a list row.
+func (l *ScrollList) AddItem(text string, onClick func()) *Button {
+	item := NewButton(text, onClick)
+	item.Style = StyleListItem
+	item.Align = AlignStart
+	l.Add(item)
+	return item
+}
+
+// Measure asks for the children's width; lists are usually given a height.
+func (l *ScrollList) Measure(theme *Theme) mgl32.Vec2 {
+	size := l.Element.Measure(theme)
+	return mgl32.Vec2{size.X(), min(size.Y(), 200)}
+}
+
+func (l *ScrollList) view() Rect {
+	return l.rect.Inset(l.Padding)
+}
+
+func (l *ScrollList) maxScroll() float32 {
+	return max(l.contentSize.Y()-l.view().Height(), 0)
+}
+
+// SetScroll scrolls the content to an offset in pixels from the top.
+func (l *ScrollList) SetScroll(offset float32) {
+	l.scroll = mgl32.Vec2{0, mgl32.Clamp(offset, 0, l.maxScroll())}
+}
+
+func (l *ScrollList) GetScroll() float32 {
+	return l.scroll.Y()
+}
+
+// ScrollTo scrolls as little as needed to show a rect of the content.
+func (l *ScrollList) ScrollTo(rect Rect) {
+	view := l.view()
+	switch {
+	case rect.Min.Y() < view.Min.Y():
+		l.SetScroll(l.scroll.Y() - (view.Min.Y() - rect.Min.Y()))
+	case rect.Max.Y() > view.Max.Y():
+		l.SetScroll(l.scroll.Y() + rect.Max.Y() - view.Max.Y())
+	}
+}
+
+// scrollIntoView scrolls every list holding a widget to show it.
+func scrollIntoView(w Widget) {
+	rect := w.GetElement().rect
+	for parent := w.GetElement().parent; parent != nil; parent = parent.parent {
+		if list, ok := parent.self.(*ScrollList); ok {
+			before := list.scroll
+			list.ScrollTo(rect)
+			rect = rect.Translate(before.Sub(list.scroll))
+		}
+	}
+}
+
+func (l *ScrollList) Update(deltaTime float32) {
+	// Keep the offset valid as items are added and removed
+	l.SetScroll(l.scroll.Y())
+}
+
+// thumb returns the scroll bar's track and the thumb inside it.
+func (l *ScrollList) thumb() (track, thumb Rect) {
+	view := l.view()
+	track = Rect{
+		Min: mgl32.Vec2{l.rect.Max.X() - 4 - scrollBarWidth, view.Min.Y()},
+		Max: mgl32.Vec2{l.rect.Max.X() - 4, view.Max.Y()},
+	}
+
+	content := max(l.contentSize.Y(), view.Height())
+	height := max(track.Height()*view.Height()/content, scrollBarWidth*2)
+	top := track.Min.Y()
+	if limit := l.maxScroll(); limit > 0 {
+		top += (track.Height() - height) * l.scroll.Y() / limit
+	}
+	thumb = Rect{Min: mgl32.Vec2{track.Min.X(), top}, Max: mgl32.Vec2{track.Max.X(), top + height}}
+	return track, thumb
+}
+
+func (l *ScrollList) dragTo(y float32) {
+	track, thumb := l.thumb()
+	travel := track.Height() - thumb.Height()
+	if travel <= 0 {
+		return
+	}
+	l.SetScroll((y - l.dragOffset - track.Min.Y()) / travel * l.maxScroll())
+}
+
+func (l *ScrollList) HandleEvent(event Event) bool {
+	switch e := event.(type) {
+	case ScrollEvent:
+		if l.maxScroll() <= 0 {
+			return false
+		}
+		l.SetScroll(l.scroll.Y() - e.Delta.Y()*l.ScrollSpeed)
+		return true
+	case PointerDownEvent:
+		track, thumb := l.thumb()
+		if l.maxScroll() <= 0 || e.Position.X() < track.Min.X() {
+			return false
+		}
+		l.dragging = true
+		if thumb.Contains(e.Position) {
+			l.dragOffset = e.Position.Y() - thumb.Min.Y()
+		} else {
+			// Jump so the thumb is centered on the pointer
+			l.dragOffset = thumb.Height() / 2
+			l.dragTo(e.Position.Y())
+		}
+		return true
+	case PointerMoveEvent:
+		if l.dragging {
+			l.dragTo(e.Position.Y())
+			return true
+		}
+	case PointerUpEvent:
+		l.dragging = false
+		return true
+	}
+	return false
+}
+
+func (l *ScrollList) Draw(r *Renderer, theme *Theme) {
+	style := l.style(theme, StyleScrollList)
+	r.DrawSkin(style.GetSkin(l.GetState()), l.rect)
+
+	if l.maxScroll() <= 0 {
+		return
+	}
+	track, thumb := l.thumb()
+	faded := style.Accent
+	faded[3] *= 0.2
+	r.FillRect(track, scrollBarWidth/2, faded)
+	r.FillRect(thumb, scrollBarWidth/2, style.Accent)
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const sliderTrackHeight = 8
+
+// Slider picks a number between Min and Max by dragging its handle, or with
+// left and right while it has focus.
+type Slider struct {
+	Element
+
+	Min, Max float32
+	Step     float32 // Values snap to multiples of Step from Min; zero for any value
+	OnChange func(value float32)
+
+	value float32
+}
+
+func NewSlider(minValue, maxValue, value float32, onChange func(value float32)) *Slider {
+	slider := &Slider{Min: minValue, Max: maxValue, OnChange: onChange}
+	slider.Focusable = true
+	slider.interactive = true
+	slider.value = slider.clamp(value)
+	return slider
+}
+
+func (s *Slider) clamp(value float32) float32 {
+	if s.Step > 0 {
+		value = s.Min + float32(math.Round(float64((value-s.Min)/s.Step)))*s.Step
+	}
+	return mgl32.Clamp(value, min(s.Min, s.Max), max(s.Min, s.Max))
+}
+
+// SetValue moves the slider, calling OnChange if the value changed.
+func (s *Slider) SetValue(value float32) {
+	value = s.clamp(value)
+	if value == s.value {
+		return
+	}
+	s.value = value
+	if s.OnChange != nil {
+		s.OnChange(value)
+	}
+}
+
+func (s *Slider) GetValue() float32 {
+	return s.value
+}
+
+// GetFraction returns how far along the track the value is, from 0 to 1.
+func (s *Slider) GetFraction() float32 {
+	if s.Max == s.Min {
+		return 0
+	}
+	return (s.value - s.Min) / (s.Max - s.Min)
+}
+
+func (s *Slider) Measure(theme *Theme) mgl32.Vec2 {
+	return mgl32.Vec2{160, 24}
+}
+
+func (s *Slider) handleRadius() float32 {
+	return max(s.rect.Height()/2-2, sliderTrackHeight/2)
+}
+
+// track returns the horizontal range the handle's center moves along.
+func (s *Slider) track() (left, right float32) {
+	radius := s.handleRadius()
+	return s.rect.Min.X() + radius, max(s.rect.Max.X()-radius, s.rect.Min.X()+radius)
+}
+
+func (s *Slider) setFromPointer(x float32) {
+	left, right := s.track()
+	if right <= left {
+		return
+	}
+	fraction := mgl32.Clamp((x-left)/(right-left), 0, 1)
+	s.SetValue(s.Min + fraction*(s.Max-s.Min))
+}
+
+func (s *Slider) Draw(r *Renderer, theme *Theme) {
+	style := s.style(theme, StyleSlider)
+	state := s.GetState()
+
+	left, right := s.track()
+	centerY := s.rect.Center().Y()
+	track := Rect{
+		Min: mgl32.Vec2{left - sliderTrackHeight/2, centerY - sliderTrackHeight/2},
+		Max: mgl32.Vec2{right + sliderTrackHeight/2, centerY + sliderTrackHeight/2},
+	}
+	r.DrawSkin(style.GetSkin(state), track)
+
+	handleX := left + (right-left)*s.GetFraction()
+	filled := track
+	filled.Max[0] = handleX
+	accent := style.Accent
+	if state == StateDisabled {
+		accent[3] *= 0.4
+	}
+	r.FillRect(filled.Inset(Uniform(1)), sliderTrackHeight/2-1, accent)
+
+	radius := s.handleRadius()
+	handle := Rect{Min: mgl32.Vec2{handleX - radius, centerY - radius}, Max: mgl32.Vec2{handleX + radius, centerY + radius}}
+	r.FillRect(handle, radius, style.TextColor(state))
+	if state == StateFocused || state == StatePressed {
+		skin := style.GetSkin(StateFocused)
+		r.DrawSkin(Skin{Border: skin.Border, BorderWidth: skin.BorderWidth, Radius: radius}, handle)
+	}
+}
+
+func (s *Slider) HandleEvent(event Event) bool {
+	switch e := event.(type) {
+	case PointerDownEvent:
+		s.setFromPointer(e.Position.X())
+		return true
+	case PointerMoveEvent:
+		s.setFromPointer(e.Position.X())
+		return true
+	case NavigateEvent:
+		step := s.Step
+		if step <= 0 {
+			step = (s.Max - s.Min) / 20
+		}
+		switch e.Direction {
+		case NavLeft:
+			s.SetValue(s.value - step)
+			return true
+		case NavRight:
+			s.SetValue(s.value + step)
+			return true
+		}
+	}
+	return false
+}
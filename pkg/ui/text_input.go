@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"math"
+	"unicode"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/font"
+)
+
+const caretBlinkTime = 0.5 // Seconds the caret is shown, then hidden
+
+// TextInput is a one-line text field. Clicking it or accepting while it has
+// focus starts editing; Enter submits and Escape stops. While it edits, the
+// canvas sends it typed text instead of navigating.
+type TextInput struct {
+	Element
+
+	Placeholder string // Shown faded while the field is empty
+	MaxLength   int    // Most characters allowed, 0 for no limit
+	OnChange    func(text string)
+	OnSubmit    func(text string)
+
+	text    []rune
+	caret   int // Index in text the caret is before
+	editing bool
+	blink   float32
+	offset  float32 // Pixels the text is scrolled left to keep the caret in view
+
+	drawStyle *Style // Style of the last draw, for turning clicks into caret positions
+}
+
+func NewTextInput(placeholder string) *TextInput {
+	input := &TextInput{Placeholder: placeholder, text: make([]rune, 0)}
+	input.Focusable = true
+	input.interactive = true
+	return input
+}
+
+// SetText replaces the text and moves the caret to its end, without calling
+// OnChange.
+func (t *TextInput) SetText(text string) {
+	t.text = []rune(text)
+	if t.MaxLength > 0 && len(t.text) > t.MaxLength {
+		t.text = t.text[:t.MaxLength]
+	}
+	t.caret = len(t.text)
+}
+
+func (t *TextInput) GetText() string {
+	return string(t.text)
+}
+
+func (t *TextInput) IsEditing() bool {
+	return t.editing
+}
+
+func (t *TextInput) StartEditing() {
+	t.editing = true
+	t.blink = 0
+}
+
+func (t *TextInput) StopEditing() {
+	t.editing = false
+}
+
+func (t *TextInput) Update(deltaTime float32) {
+	t.blink += deltaTime
+}
+
+func (t *TextInput) Measure(theme *Theme) mgl32.Vec2 {
+	style := t.style(theme, StyleTextInput)
+	return mgl32.Vec2{200, font.GlyphHeight*style.TextScale + style.Padding.Top + style.Padding.Bottom}
+}
+
+func (t *TextInput) changed() {
+	t.blink = 0
+	if t.OnChange != nil {
+		t.OnChange(string(t.text))
+	}
+}
+
+func (t *TextInput) insert(text string) {
+	inserted := false
+	for _, r := range text {
+		if unicode.IsControl(r) || (t.MaxLength > 0 && len(t.text) >= t.MaxLength) {
+			continue
+		}
+		t.text = append(t.text[:t.caret], append([]rune{r}, t.text[t.caret:]...)...)
+		t.caret++
+		inserted = true
+	}
+	if inserted {
+		t.changed()
+	}
+}
+
+func (t *TextInput) handleKey(key glfw.Key) {
+	switch key {
+	case glfw.KeyBackspace:
+		if t.caret > 0 {
+			t.text = append(t.text[:t.caret-1], t.text[t.caret:]...)
+			t.caret--
+			t.changed()
+		}
+	case glfw.KeyDelete:
+		if t.caret < len(t.text) {
+			t.text = append(t.text[:t.caret], t.text[t.caret+1:]...)
+			t.changed()
+		}
+	case glfw.KeyLeft:
+		t.caret = max(t.caret-1, 0)
+	case glfw.KeyRight:
+		t.caret = min(t.caret+1, len(t.text))
+	case glfw.KeyHome:
+		t.caret = 0
+	case glfw.KeyEnd:
+		t.caret = len(t.text)
+	case glfw.KeyEnter, glfw.KeyKPEnter:
+		t.StopEditing()
+		if t.OnSubmit != nil {
+			t.OnSubmit(string(t.text))
+		}
+	case glfw.KeyEscape:
+		t.StopEditing()
+	default:
+		return
+	}
+	t.blink = 0
+}
+
+// caretAt returns the caret index nearest a point.
+func (t *TextInput) caretAt(x float32, style *Style) int {
+	content := t.rect.Inset(style.Padding)
+	advance := font.GlyphAdvance * style.TextScale
+	index := int(math.Round(float64((x - content.Min.X() + t.offset) / advance)))
+	return max(0, min(index, len(t.text)))
+}
+
+func (t *TextInput) HandleEvent(event Event) bool {
+	switch e := event.(type) {
+	case PointerDownEvent:
+		t.StartEditing()
+		if t.drawStyle != nil {
+			t.caret = t.caretAt(e.Position.X(), t.drawStyle)
+		}
+		return true
+	case SubmitEvent:
+		if !t.editing {
+			t.StartEditing()
+			t.caret = len(t.text)
+		}
+		return true
+	case CancelEvent:
+		if t.editing {
+			t.StopEditing()
+			return true
+		}
+	case FocusEvent:
+		if !e.Focused {
+			t.StopEditing()
+		}
+	case TextEvent:
+		t.insert(e.Text)
+		return true
+	case KeyEvent:
+		t.handleKey(e.Key)
+		return true
+	}
+	return false
+}
+
+func (t *TextInput) Draw(r *Renderer, theme *Theme) {
+	style := t.style(theme, StyleTextInput)
+	t.drawStyle = style
+
+	state := t.GetState()
+	if t.editing {
+		state = StateFocused
+	}
+	r.DrawSkin(style.GetSkin(state), t.rect)
+
+	content := t.rect.Inset(style.Padding)
+	advance := font.GlyphAdvance * style.TextScale
+
+	// Scroll just enough to keep the caret inside the field
+	caretX := float32(t.caret) * advance
+	if caretX-t.offset > content.Width()-style.TextScale {
+		t.offset = caretX - content.Width() + style.TextScale
+	}
+	if caretX < t.offset {
+		t.offset = caretX
+	}
+	t.offset = max(t.offset, 0)
+
+	r.PushClip(content)
+	origin := mgl32.Vec2{content.Min.X() - t.offset, content.Min.Y() + (content.Height()-font.GlyphHeight*style.TextScale)/2}
+	if len(t.text) == 0 && !t.editing {
+		faded := style.TextColor(state)
+		faded[3] *= 0.4
+		r.Text(origin, t.Placeholder, style.TextScale, faded)
+	} else {
+		r.Text(origin, string(t.text), style.TextScale, style.TextColor(state))
+	}
+
+	if t.editing && math.Mod(float64(t.blink), 2*caretBlinkTime) < caretBlinkTime {
+		// In the gap before the next character
+		x := origin.X() + max(caretX-style.TextScale, 0)
+		r.FillRect(Rect{
+			Min: mgl32.Vec2{x, origin.Y() - 1},
+			Max: mgl32.Vec2{x + max(style.TextScale/2, 1), origin.Y() + font.GlyphHeight*style.TextScale + 1},
+		}, 0, style.Accent)
+	}
+	r.PopClip()
+}
@@ -0,0 +1,189 @@
+package ui
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/lunararch/helios/pkg/graphics/texture"
+)
+
+// State is what a widget is doing, for picking its skin.
+type State int
+
+const (
+	StateNormal State = iota
+	StateHovered
+	StatePressed
+	StateFocused
+	StateDisabled
+)
+
+// Names of the styles widgets draw with unless their Element.Style says
+// otherwise.
+const (
+	StyleDefault    = "default"
+	StylePanel      = "panel"
+	StyleLabel      = "label"
+	StyleTitle      = "title"
+	StyleButton     = "button"
+	StyleListItem   = "list_item"
+	StyleSlider     = "slider"
+	StyleCheckbox   = "checkbox"
+	StyleTextInput  = "text_input"
+	StyleScrollList = "scroll_list"
+)
+
+// Skin is how a widget's background looks in one state: a nine-slice image,
+// or a rounded rectangle drawn with shapes when Region is nil.
+type Skin struct {
+	Region      *texture.TextureRegion // Nine-slice image, stretched between its borders
+	Color       mgl32.Vec4             // Tint of Region, or the fill color
+	Border      mgl32.Vec4             // Outline color of shape skins
+	BorderWidth float32
+	Radius      float32 // Corner radius of shape skins
+}
+
+// IsEmpty is true for skins that draw nothing.
+func (s Skin) IsEmpty() bool {
+	return s.Region == nil && s.Color.W() <= 0 && (s.BorderWidth <= 0 || s.Border.W() <= 0)
+}
+
+// Style is the look of one kind of widget.
+type Style struct {
+	Skins     map[State]Skin // States without a skin use StateNormal's
+	Text      mgl32.Vec4     // Text color
+	TextScale float32        // Size of a font pixel, in canvas pixels
+	Padding   Insets         // Space between the skin's edge and the content
+	Accent    mgl32.Vec4     // Slider fill, check mark, caret and scroll bar
+}
+
+func NewStyle() *Style {
+	return &Style{
+		Skins:     make(map[State]Skin),
+		Text:      mgl32.Vec4{1, 1, 1, 1},
+		TextScale: 2,
+		Accent:    mgl32.Vec4{1, 1, 1, 1},
+	}
+}
+
+func (s *Style) SetSkin(state State, skin Skin) *Style {
+	s.Skins[state] = skin
+	return s
+}
+
+func (s *Style) GetSkin(state State) Skin {
+	if skin, exists := s.Skins[state]; exists {
+		return skin
+	}
+	return s.Skins[StateNormal]
+}
+
+// SetNineSlice skins a state with an image, which needs borders set on the
+// region to keep its corners unstretched.
+func (s *Style) SetNineSlice(state State, region *texture.TextureRegion, tint mgl32.Vec4) *Style {
+	return s.SetSkin(state, Skin{Region: region, Color: tint})
+}
+
+// TextColor returns the text color for a state; disabled text is faded.
+func (s *Style) TextColor(state State) mgl32.Vec4 {
+	if state == StateDisabled {
+		return mgl32.Vec4{s.Text.X(), s.Text.Y(), s.Text.Z(), s.Text.W() * 0.4}
+	}
+	return s.Text
+}
+
+// Clone returns a copy to change without touching the original.
+func (s *Style) Clone() *Style {
+	clone := *s
+	clone.Skins = make(map[State]Skin, len(s.Skins))
+	for state, skin := range s.Skins {
+		clone.Skins[state] = skin
+	}
+	return &clone
+}
+
+// Theme maps style names to styles, so one theme can dress every widget.
+type Theme struct {
+	styles map[string]*Style
+}
+
+func NewTheme() *Theme {
+	return &Theme{styles: map[string]*Style{StyleDefault: NewStyle()}}
+}
+
+func (t *Theme) SetStyle(name string, style *Style) {
+	t.styles[name] = style
+}
+
+// GetStyle returns a named style, or the default style if there is none.
+func (t *Theme) GetStyle(name string) *Style {
+	if style, exists := t.styles[name]; exists {
+		return style
+	}
+	return t.styles[StyleDefault]
+}
+
+// DefaultTheme is a dark blue theme drawn entirely with shapes.
+func DefaultTheme() *Theme {
+	theme := NewTheme()
+
+	text := mgl32.Vec4{0.9, 0.92, 1, 1}
+	accent := mgl32.Vec4{0.45, 0.55, 1, 1}
+	outline := mgl32.Vec4{0.5, 0.55, 0.9, 1}
+	focus := mgl32.Vec4{1, 0.85, 0.4, 1}
+
+	base := NewStyle()
+	base.Text = text
+	base.Accent = accent
+	base.Padding = Uniform(6)
+	theme.SetStyle(StyleDefault, base)
+
+	label := base.Clone()
+	label.Padding = Insets{}
+	theme.SetStyle(StyleLabel, label)
+
+	title := label.Clone()
+	title.TextScale = 4
+	theme.SetStyle(StyleTitle, title)
+
+	panel := base.Clone()
+	panel.Padding = Uniform(12)
+	panel.SetSkin(StateNormal, Skin{Color: mgl32.Vec4{0.08, 0.08, 0.15, 0.9}, Border: outline, BorderWidth: 2, Radius: 12})
+	theme.SetStyle(StylePanel, panel)
+
+	button := base.Clone()
+	button.Padding = Insets{12, 8, 12, 8}
+	button.SetSkin(StateNormal, Skin{Color: mgl32.Vec4{0.22, 0.25, 0.55, 1}, Border: outline, BorderWidth: 1, Radius: 6})
+	button.SetSkin(StateHovered, Skin{Color: mgl32.Vec4{0.3, 0.34, 0.7, 1}, Border: outline, BorderWidth: 1, Radius: 6})
+	button.SetSkin(StatePressed, Skin{Color: mgl32.Vec4{0.15, 0.17, 0.4, 1}, Border: outline, BorderWidth: 1, Radius: 6})
+	button.SetSkin(StateFocused, Skin{Color: mgl32.Vec4{0.22, 0.25, 0.55, 1}, Border: focus, BorderWidth: 2, Radius: 6})
+	button.SetSkin(StateDisabled, Skin{Color: mgl32.Vec4{0.2, 0.2, 0.25, 0.6}, Border: outline.Mul(0.5), BorderWidth: 1, Radius: 6})
+	theme.SetStyle(StyleButton, button)
+
+	item := base.Clone()
+	item.Padding = Insets{8, 4, 8, 4}
+	item.SetSkin(StateHovered, Skin{Color: mgl32.Vec4{1, 1, 1, 0.08}, Radius: 4})
+	item.SetSkin(StatePressed, Skin{Color: mgl32.Vec4{0, 0, 0, 0.2}, Radius: 4})
+	item.SetSkin(StateFocused, Skin{Color: mgl32.Vec4{0.45, 0.55, 1, 0.3}, Border: focus, BorderWidth: 1, Radius: 4})
+	theme.SetStyle(StyleListItem, item)
+
+	field := Skin{Color: mgl32.Vec4{0.04, 0.04, 0.08, 1}, Border: mgl32.Vec4{0.35, 0.38, 0.6, 1}, BorderWidth: 1, Radius: 4}
+	focusedField := field
+	focusedField.Border, focusedField.BorderWidth = focus, 2
+	hoveredField := field
+	hoveredField.Border = outline
+
+	for _, name := range []string{StyleSlider, StyleCheckbox, StyleTextInput} {
+		style := base.Clone()
+		style.SetSkin(StateNormal, field)
+		style.SetSkin(StateHovered, hoveredField)
+		style.SetSkin(StatePressed, hoveredField)
+		style.SetSkin(StateFocused, focusedField)
+		theme.SetStyle(name, style)
+	}
+
+	list := base.Clone()
+	list.Padding = Uniform(4)
+	list.SetSkin(StateNormal, Skin{Color: mgl32.Vec4{0.04, 0.04, 0.08, 0.6}, Border: mgl32.Vec4{0.35, 0.38, 0.6, 1}, BorderWidth: 1, Radius: 6})
+	theme.SetStyle(StyleScrollList, list)
+
+	return theme
+}
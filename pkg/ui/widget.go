@@ -0,0 +1,184 @@
+package ui
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Widget is a node of the UI tree. Widgets embed an Element, which holds
+// what every widget shares and provides defaults for the other methods.
+type Widget interface {
+	GetElement() *Element
+
+	// Measure returns the widget's natural size, used on axes its Size
+	// leaves at zero.
+	Measure(theme *Theme) mgl32.Vec2
+	Update(deltaTime float32)
+	// Draw draws the widget itself; the canvas draws its children after it.
+	Draw(r *Renderer, theme *Theme)
+	// HandleEvent returns true if the widget used the event, which stops it
+	// going further.
+	HandleEvent(event Event) bool
+}
+
+// Element is the part of a widget that places it in the tree and layout.
+type Element struct {
+	Name     string
+	Style    string     // Theme style to draw with; empty uses the widget's default
+	Anchor   Anchor     // Placement inside a parent without a Flex layout
+	Size     mgl32.Vec2 // Preferred size; zero axes use the measured size
+	Grow     float32    // Share of the space left over in a Flex parent
+	Padding  Insets     // Space kept clear around the children
+	Flex     *Flex      // Lays the children out in a row or column instead of by anchor
+	Hidden   bool       // Hidden widgets are skipped by layout, drawing and input, with their children
+	Disabled bool       // Disabled widgets and their children ignore input and draw dimmed
+
+	// Focusable widgets take keyboard and gamepad focus; interactive ones
+	// take the mouse. Both are set by the widgets that need them.
+	Focusable   bool
+	interactive bool
+
+	self        Widget
+	parent      *Element
+	children    []Widget
+	rect        Rect
+	scroll      mgl32.Vec2 // Content offset, for scroll lists
+	contentSize mgl32.Vec2 // Size the children took up at the last layout
+	clips       bool       // Children are cut off at the padded rect
+
+	hovered bool
+	pressed bool
+	focused bool
+}
+
+func (e *Element) GetElement() *Element {
+	return e
+}
+
+// Measure sizes a Flex container to its children; other containers have no
+// natural size and are usually anchored or given a Size.
+func (e *Element) Measure(theme *Theme) mgl32.Vec2 {
+	if e.Flex == nil {
+		return mgl32.Vec2{}
+	}
+	return e.Flex.measure(e.children, theme).Add(e.Padding.Size())
+}
+
+func (e *Element) Update(deltaTime float32) {}
+
+func (e *Element) Draw(r *Renderer, theme *Theme) {}
+
+func (e *Element) HandleEvent(event Event) bool {
+	return false
+}
+
+// Add appends children, drawn in order on top of the widget and each
+// other. A child already in a tree is moved.
+func (e *Element) Add(children ...Widget) {
+	for _, child := range children {
+		childElement := child.GetElement()
+		if childElement.parent != nil {
+			childElement.parent.Remove(child)
+		}
+		childElement.self = child
+		childElement.parent = e
+		e.children = append(e.children, child)
+	}
+}
+
+func (e *Element) Remove(child Widget) {
+	for i, existing := range e.children {
+		if existing == child {
+			e.children = append(e.children[:i], e.children[i+1:]...)
+			child.GetElement().parent = nil
+			return
+		}
+	}
+}
+
+func (e *Element) Clear() {
+	for _, child := range e.children {
+		child.GetElement().parent = nil
+	}
+	e.children = e.children[:0]
+}
+
+func (e *Element) GetChildren() []Widget {
+	return e.children
+}
+
+// GetParent returns the widget this one was added to, or nil.
+func (e *Element) GetParent() Widget {
+	if e.parent == nil {
+		return nil
+	}
+	return e.parent.self
+}
+
+// Find returns the first widget below this one with the given name, depth
+// first, or nil.
+func (e *Element) Find(name string) Widget {
+	for _, child := range e.children {
+		if child.GetElement().Name == name {
+			return child
+		}
+		if found := child.GetElement().Find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// GetRect returns the rect from the last layout, in canvas pixels.
+func (e *Element) GetRect() Rect {
+	return e.rect
+}
+
+// IsVisible is false if the widget or any parent is hidden.
+func (e *Element) IsVisible() bool {
+	for element := e; element != nil; element = element.parent {
+		if element.Hidden {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEnabled is false if the widget or any parent is disabled.
+func (e *Element) IsEnabled() bool {
+	for element := e; element != nil; element = element.parent {
+		if element.Disabled {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Element) IsHovered() bool {
+	return e.hovered
+}
+
+func (e *Element) IsFocused() bool {
+	return e.focused
+}
+
+// GetState returns the state to pick a skin for.
+func (e *Element) GetState() State {
+	switch {
+	case !e.IsEnabled():
+		return StateDisabled
+	case e.pressed && e.hovered:
+		return StatePressed
+	case e.hovered:
+		return StateHovered
+	case e.focused:
+		return StateFocused
+	default:
+		return StateNormal
+	}
+}
+
+// style returns the element's theme style, or the widget's default.
+func (e *Element) style(theme *Theme, fallback string) *Style {
+	if e.Style != "" {
+		return theme.GetStyle(e.Style)
+	}
+	return theme.GetStyle(fallback)
+}